@@ -0,0 +1,121 @@
+// Команда backfill-authors переназначает ботов, созданных до перехода на субъекты JWT (миграция 014),
+// их авторам. До миграции автор хранился числовым ID пользователя платформы; миграция сохраняет его
+// с префиксом legacy:, и такие боты недоступны своим авторам, пока ID не заменён субъектом токена SSO.
+// Боты, автор которых - числовой субъект JWT без префикса, не переназначаются.
+//
+//	backfill-authors -mapping authors.csv
+//
+// Файл соответствия содержит строки "<прежний числовой ID>,<субъект JWT>". Все боты переназначаются
+// в одной транзакции; в конце печатаются прежние ID, которых нет в файле.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+)
+
+const usage = "usage: backfill-authors -mapping <file.csv>"
+
+var legacyIDRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+func main() {
+	if err := run(logs.DefaultLogger(), os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(l *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("backfill-authors", flag.ContinueOnError)
+	mappingPath := fs.String("mapping", "", "CSV-файл соответствия прежних ID субъектам JWT")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *mappingPath == "" {
+		return errors.New(usage)
+	}
+
+	authors, err := readMapping(*mappingPath)
+	if err != nil {
+		return err
+	}
+
+	uri := os.Getenv("DATABASE_URI")
+	if uri == "" {
+		return errors.New("DATABASE_URI must be set")
+	}
+	db, err := sqlx.Connect("postgres", uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Токены ботов не читаются, поэтому ключи шифрования не нужны
+	repos := postgres.NewRepository(db, nil, nil, l)
+	n, err := repos.ReassignBotAuthors(ctx, authors)
+	if err != nil {
+		return err
+	}
+	l.InfoContext(ctx, "bot authors reassigned", slog.Int("bots", n))
+
+	left, err := repos.LegacyBotAuthors(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range left {
+		l.WarnContext(ctx, "bot author is not mapped", slog.String("legacy_id", id))
+	}
+	return nil
+}
+
+// readMapping читает файл соответствия "<прежний ID>,<субъект JWT>".
+func readMapping(path string) (map[string]bots.AccountID, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	authors := make(map[string]bots.AccountID)
+	for {
+		record, err2 := r.Read()
+		if errors.Is(err2, io.EOF) {
+			break
+		} else if err2 != nil {
+			return nil, fmt.Errorf("reading mapping: %w", err2)
+		}
+		legacyID, account := record[0], record[1]
+		if !legacyIDRegexp.MatchString(legacyID) {
+			return nil, fmt.Errorf("mapping: legacy id %q is not numeric", legacyID)
+		}
+		if account == "" {
+			return nil, fmt.Errorf("mapping: empty account for legacy id %s", legacyID)
+		}
+		if prev, ok := authors[legacyID]; ok && prev != bots.AccountID(account) {
+			return nil, fmt.Errorf("mapping: legacy id %s is mapped to %s and %s", legacyID, prev, account)
+		}
+		authors[legacyID] = bots.AccountID(account)
+	}
+	return authors, nil
+}
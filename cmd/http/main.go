@@ -3,11 +3,19 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
 	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 
@@ -16,11 +24,19 @@ import (
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/command"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/query"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/outbox"
 	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/requests"
 	"github.com/bmstu-itstech/itsreg-bots/internal/infra/telegram"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/webhooks"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/jwtauth"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs/sl"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/server"
 )
@@ -33,6 +49,70 @@ func connectDB() (*sqlx.DB, error) {
 	return sqlx.Connect("postgres", uri)
 }
 
+// botWorkers возвращает количество горутин, обрабатывающих обновления одного бота.
+func botWorkers() (int, error) {
+	s := os.Getenv("BOT_WORKERS")
+	if s == "" {
+		return defaultBotWorkers, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("BOT_WORKERS must be a positive integer, got %q", s)
+	}
+	return n, nil
+}
+
+const defaultBotWorkers = 8
+
+// shutdownTimeout ограничивает время завершения HTTP-запросов и, отдельно, обработки обновлений ботов
+// при остановке сервиса.
+const shutdownTimeout = 15 * time.Second
+
+// replicaID возвращает идентификатор реплики сервиса для распределения ботов между репликами.
+func replicaID() (string, error) {
+	if id := os.Getenv("REPLICA_ID"); id != "" {
+		return id, nil
+	}
+	return os.Hostname()
+}
+
+// deletedBotsRetention возвращает срок, после которого удалённые боты удаляются окончательно; 0 означает,
+// что удалённые боты хранятся, пока их не удалят вручную.
+func deletedBotsRetention() (time.Duration, error) {
+	s := os.Getenv("DELETED_BOTS_RETENTION_DAYS")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("DELETED_BOTS_RETENTION_DAYS must be a positive integer, got %q", s)
+	}
+	return time.Duration(n) * 24 * time.Hour, nil
+}
+
+const purgeDeletedBotsInterval = time.Hour
+
+// purgeDeletedBots раз в purgeDeletedBotsInterval окончательно удаляет ботов, удалённых раньше, чем
+// retention назад, пока не будет отменён ctx.
+func purgeDeletedBots(
+	ctx context.Context, h command.PurgeDeletedBotsHandler, retention time.Duration, l *slog.Logger,
+) {
+	ticker := time.NewTicker(purgeDeletedBotsInterval)
+	defer ticker.Stop()
+
+	for {
+		err := h.Handle(ctx, request.PurgeDeletedBotsCommand{DeletedBefore: time.Now().Add(-retention)})
+		if err != nil && ctx.Err() == nil {
+			l.ErrorContext(ctx, "failed to purge deleted bots", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func main() {
 	l := logs.DefaultLogger()
 	mc := metrics.NoOp{}
@@ -42,70 +122,324 @@ func main() {
 		log.Fatal(err)
 	}
 
-	repos := postgres.NewRepository(db, l)
-	sender := telegram.NewMessageSender(l)
+	replica, err := replicaID()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	process := ProcessHandlerAdapter{command.NewProcessHandler(repos, repos, sender, l, mc)}
-	entry := EntryHandlerAdapter{command.NewEntryHandler(repos, repos, sender, l, mc)}
-	instanceManager := telegram.NewInstanceManager(l, process, entry)
+	workers, err := botWorkers()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	retention, err := deletedBotsRetention()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	jwtConfig, err := jwtauth.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	tokens, err := jwtauth.NewVerifier(jwtConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Форвардер подтверждает событие в таблице outbox только после того, как подписчики шины его обработали
+	bus := gochannel.NewGoChannel(gochannel.Config{BlockPublishUntilSubscriberAck: true}, watermill.NopLogger{})
+	external, err := events.NewExternalPublisher(os.Getenv("EVENTS_BACKEND"), db, l)
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventPubs := []message.Publisher{bus}
+	if external != nil {
+		eventPubs = append(eventPubs, external)
+	}
+	// События сохраняются в таблицу outbox, а форвардер пересылает их в транспорты после фиксации транзакции
+	eventForwarder, err := events.NewForwarder(db.DB, l, eventPubs...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	outboxPublisher, err := events.NewOutboxPublisher(db.DB, l)
+	if err != nil {
+		log.Fatal(err)
+	}
+	publisher := events.NewPublisher(l, outboxPublisher)
+
+	tokenKeys, err := envelope.KeyringFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tokenKeys == nil {
+		l.Warn("TOKEN_ENCRYPTION_KEYS is not set, bot tokens are stored in plain text")
+	}
+
+	repos := postgres.NewRepository(db, tokenKeys, events.NewOutbox(l), l)
+	clients := telegram.NewClientCache(mc)
+	tokenVerifier := telegram.NewTokenVerifier()
+	sent := telegram.NewSentCounter()
+	sender := telegram.NewMessageSender(clients, sent, l)
+
+	deliver := DeliverMessageAdapter{command.NewDeliverMessageHandler(repos, repos, repos, sender, repos, l, mc)}
+	relay, err := outbox.NewRelay(repos, deliver, workers, l)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	deliverWebhook := DeliverWebhookAdapter{command.NewDeliverWebhookHandler(repos, webhooks.NewSender(), repos, l, mc)}
+	dispatcher := webhooks.NewDispatcher(repos, deliverWebhook, workers, l)
+	enqueueWebhookEvent := EnqueueWebhookEventAdapter{
+		command.NewEnqueueWebhookEventHandler(repos, repos, l, mc), dispatcher,
+	}
+	webhookRouter, err := message.NewRouter(
+		message.RouterConfig{CloseTimeout: shutdownTimeout}, sl.NewWatermillLoggerAdapter(l),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhooks.AddConsumers(webhookRouter, bus, enqueueWebhookEvent, l)
+
+	executor := requests.NewExecutor(l)
+	process := ProcessHandlerAdapter{command.NewProcessHandler(repos, repos, executor, repos, l, mc), relay}
+	entry := EntryHandlerAdapter{command.NewEntryHandler(repos, repos, executor, repos, l, mc), relay}
+	instanceManager := telegram.NewInstanceManager(
+		clients, sent, repos, repos, repos, replica, workers, l, process, entry,
+	)
 
 	a := app.Application{
 		Commands: app.Commands{
-			CreateBot:    command.NewCreateBotHandler(repos, l, mc),
-			DeleteBot:    command.NewDeleteBotHandler(repos, l, mc),
-			DisableBot:   command.NewDisableBotHandler(repos, l, mc),
-			EnableBot:    command.NewEnableBotHandler(repos, instanceManager, l, mc),
-			Entry:        command.NewEntryHandler(repos, repos, sender, l, mc),
-			Mailing:      command.NewMailingHandler(repos, repos, sender, l, mc),
-			Process:      command.NewProcessHandler(repos, repos, sender, l, mc),
-			Start:        command.NewStartHandler(instanceManager, repos, l, mc),
-			StartEnabled: command.NewStartEnabledHandler(instanceManager, repos, l, mc),
-			Stop:         command.NewStopHandler(instanceManager, l, mc),
-			UpdateBot:    command.NewUpdateBotHandler(repos, l, mc),
+			CreateAPIKey:         command.NewCreateAPIKeyHandler(repos, repos, l, mc),
+			CreateBot:            command.NewCreateBotHandler(repos, repos, clients, tokenVerifier, instanceManager, repos, l, mc),
+			CreateWebhook:        command.NewCreateWebhookHandler(repos, repos, repos, repos, l, mc),
+			DeleteBot:            command.NewDeleteBotHandler(repos, repos, clients, repos, l, mc),
+			DeleteWebhook:        command.NewDeleteWebhookHandler(repos, repos, repos, repos, l, mc),
+			DeliverMessage:       deliver.H,
+			DeliverWebhook:       deliverWebhook.H,
+			DisableBot:           command.NewDisableBotHandler(repos, repos, publisher, repos, l, mc),
+			EnableBot:            command.NewEnableBotHandler(repos, repos, instanceManager, publisher, repos, l, mc),
+			EnqueueWebhookEvent:  enqueueWebhookEvent.H,
+			Entry:                command.NewEntryHandler(repos, repos, executor, repos, l, mc),
+			EraseParticipantData: command.NewEraseParticipantDataHandler(repos, repos, repos, repos, l, mc),
+			InviteCollaborator:   command.NewInviteCollaboratorHandler(repos, repos, repos, l, mc),
+			Mailing:              command.NewMailingHandler(repos, repos, repos, executor, publisher, repos, l, mc),
+			Process:              command.NewProcessHandler(repos, repos, executor, repos, l, mc),
+			PurgeBot:             command.NewPurgeBotHandler(repos, repos, repos, l, mc),
+			PurgeDeletedBots:     command.NewPurgeDeletedBotsHandler(repos, repos, l, mc),
+			RemoveCollaborator:   command.NewRemoveCollaboratorHandler(repos, repos, repos, l, mc),
+			RestoreBot:           command.NewRestoreBotHandler(repos, repos, repos, tokenVerifier, repos, l, mc),
+			RevokeAPIKey:         command.NewRevokeAPIKeyHandler(repos, repos, l, mc),
+			Start:                command.NewStartHandler(instanceManager, repos, repos, repos, l, mc),
+			StartEnabled:         command.NewStartEnabledHandler(instanceManager, repos, repos, l, mc),
+			Stop:                 command.NewStopHandler(instanceManager, repos, repos, repos, l, mc),
+			TouchAPIKey:          command.NewTouchAPIKeyHandler(repos, repos, l, mc),
+			UpdateBot:            command.NewUpdateBotHandler(repos, repos, clients, tokenVerifier, instanceManager, repos, l, mc),
+			UpdateCollaborator:   command.NewUpdateCollaboratorHandler(repos, repos, repos, l, mc),
+			UpdateWebhook:        command.NewUpdateWebhookHandler(repos, repos, repos, repos, l, mc),
 		},
 		Queries: app.Queries{
-			GetBot:      query.NewGetBotHandler(repos, l, mc),
-			GetStatus:   query.NewGetStatusHandler(instanceManager, repos, l, mc),
-			GetThreads:  query.NewGetThreadsHandler(repos, instanceManager, l, mc),
-			GetUserBots: query.NewGetUserBotsHandler(repos, l, mc),
+			AuthenticateAPIKey:   query.NewAuthenticateAPIKeyHandler(repos, l, mc),
+			GetAPIKeys:           query.NewGetAPIKeysHandler(repos, l, mc),
+			GetAuditLog:          query.NewGetAuditLogHandler(repos, repos, repos, l, mc),
+			GetBot:               query.NewGetBotHandler(repos, repos, l, mc),
+			GetBotThreads:        query.NewGetBotThreadsHandler(repos, repos, repos, repos, l, mc),
+			GetCollaborators:     query.NewGetCollaboratorsHandler(repos, repos, l, mc),
+			GetDeletedBots:       query.NewGetDeletedBotsHandler(repos, repos, l, mc),
+			GetParticipantData:   query.NewGetParticipantDataHandler(repos, repos, repos, l, mc),
+			GetParticipantStats:  query.NewGetParticipantStatsHandler(repos, repos, repos, l, mc),
+			GetStatus:            query.NewGetStatusHandler(instanceManager, repos, repos, l, mc),
+			GetUserBots:          query.NewGetUserBotsHandler(repos, repos, l, mc),
+			GetWebhook:           query.NewGetWebhookHandler(repos, repos, repos, l, mc),
+			GetWebhookDeliveries: query.NewGetWebhookDeliveriesHandler(repos, repos, repos, l, mc),
+			GetWebhooks:          query.NewGetWebhooksHandler(repos, repos, repos, l, mc),
+			StreamThreads:        query.NewStreamThreadsHandler(repos, repos, repos, repos, l, mc),
 		},
 	}
 
-	err = a.Commands.StartEnabled.Handle(context.Background(), request.StartEnabledBotsCommand{})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Подписчики должны появиться на шине до первого события, иначе оно будет потеряно
+	webhookRouterDone := make(chan struct{})
+	go func() {
+		defer close(webhookRouterDone)
+		if err2 := webhookRouter.Run(ctx); err2 != nil {
+			l.ErrorContext(ctx, "webhook router failed", slog.String("error", err2.Error()))
+		}
+	}()
+	<-webhookRouter.Running()
+
+	eventForwarderDone := make(chan struct{})
+	go func() {
+		defer close(eventForwarderDone)
+		if err2 := eventForwarder.Run(ctx); err2 != nil {
+			l.ErrorContext(ctx, "event forwarder failed", slog.String("error", err2.Error()))
+		}
+	}()
+
+	err = a.Commands.StartEnabled.Handle(ctx, request.StartEnabledBotsCommand{})
 	if err != nil {
-		l.ErrorContext(context.Background(), "failed to start enabled bots", slog.String("error", err.Error()))
+		l.ErrorContext(ctx, "failed to start enabled bots", slog.String("error", err.Error()))
+	}
+	go instanceManager.MaintainLeases(ctx)
+	if retention > 0 {
+		go purgeDeletedBots(ctx, a.Commands.PurgeDeletedBots, retention, l)
 	}
 
-	server.RunHTTPServer(func(router chi.Router) http.Handler {
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		if err2 := relay.Run(ctx); err2 != nil {
+			l.ErrorContext(ctx, "outbox relay failed", slog.String("error", err2.Error()))
+		}
+	}()
+
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(ctx)
+	}()
+
+	keys := APIKeyVerifierAdapter{a.Queries.AuthenticateAPIKey, a.Commands.TouchAPIKey}
+	auth := jwtauth.NewHTTPMiddleware(tokens, keys)
+	server.RunHTTPServer(ctx, shutdownTimeout, auth, func(router chi.Router) http.Handler {
 		return httpapi.HandlerFromMux(httpapi.NewHTTPServer(&a), router)
 	})
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err = instanceManager.Shutdown(shutdownCtx); err != nil {
+		l.ErrorContext(shutdownCtx, "failed to stop bot instances", slog.String("error", err.Error()))
+	}
+	<-relayDone
+	<-webhookRouterDone
+	<-eventForwarderDone
+	<-dispatcherDone
+
+	if err = bus.Close(); err != nil {
+		l.ErrorContext(shutdownCtx, "failed to close event bus", slog.String("error", err.Error()))
+	}
+
+	if err = db.Close(); err != nil {
+		l.ErrorContext(shutdownCtx, "failed to close database", slog.String("error", err.Error()))
+	}
+	l.InfoContext(shutdownCtx, "service stopped")
 }
 
 // Страшно, очень страшно.
 // Как сделать иначе?
 
 type ProcessHandlerAdapter struct {
-	H command.ProcessHandler
+	H     command.ProcessHandler
+	Relay *outbox.Relay
 }
 
 func (a ProcessHandlerAdapter) Process(
-	ctx context.Context, botID bots.BotID, userID bots.UserID, msg bots.Message,
+	ctx context.Context, botID bots.BotID, origin port.Origin, msg bots.Message,
 ) error {
-	return a.H.Handle(ctx, request.ProcessCommand{
-		BotID:   string(botID),
-		UserID:  int64(userID),
-		Message: dto.Message{Text: msg.Text()},
+	err := a.H.Handle(ctx, request.ProcessCommand{
+		BotID:     string(botID),
+		UpdateID:  int64(origin.UpdateID),
+		ChatID:    int64(origin.ChatID),
+		UserID:    int64(origin.UserID),
+		Group:     origin.Group,
+		Addressed: origin.Addressed,
+		Profile:   dto.ProfileToDTO(origin.Profile),
+		Message:   dto.Message{Text: msg.Text()},
 	})
+	// Ответ бота уже в outbox, отправляем его без ожидания следующего опроса
+	a.Relay.Notify()
+	return err
 }
 
 type EntryHandlerAdapter struct {
-	H command.EntryHandler
+	H     command.EntryHandler
+	Relay *outbox.Relay
+}
+
+func (a EntryHandlerAdapter) Entry(
+	ctx context.Context, botID bots.BotID, origin port.Origin, key bots.EntryKey,
+) error {
+	err := a.H.Handle(ctx, request.EntryCommand{
+		BotID:     string(botID),
+		UpdateID:  int64(origin.UpdateID),
+		ChatID:    int64(origin.ChatID),
+		UserID:    int64(origin.UserID),
+		Group:     origin.Group,
+		Addressed: origin.Addressed,
+		Profile:   dto.ProfileToDTO(origin.Profile),
+		Key:       string(key),
+	})
+	a.Relay.Notify()
+	return err
+}
+
+type DeliverMessageAdapter struct {
+	H command.DeliverMessageHandler
+}
+
+func (a DeliverMessageAdapter) Deliver(ctx context.Context, msg port.OutboxMessage) error {
+	opts := make([]string, len(msg.Message.Options()))
+	for i, opt := range msg.Message.Options() {
+		opts[i] = opt.String()
+	}
+	return a.H.Handle(ctx, request.DeliverMessageCommand{
+		OutboxID: msg.ID,
+		BotID:    string(msg.ParticipantID.BotID()),
+		ChatID:   int64(msg.ParticipantID.ChatID()),
+		UserID:   int64(msg.ParticipantID.UserID()),
+		Text:     msg.Message.Text(),
+		Options:  opts,
+		Attempts: msg.Attempts,
+	})
+}
+
+type EnqueueWebhookEventAdapter struct {
+	H          command.EnqueueWebhookEventHandler
+	Dispatcher *webhooks.Dispatcher
+}
+
+func (a EnqueueWebhookEventAdapter) Enqueue(
+	ctx context.Context, botID bots.BotID, eventUUID string, event string, payload []byte,
+) error {
+	err := a.H.Handle(ctx, request.EnqueueWebhookEventCommand{
+		BotID:     string(botID),
+		EventUUID: eventUUID,
+		Event:     event,
+		Payload:   payload,
+	})
+	a.Dispatcher.Notify()
+	return err
+}
+
+type DeliverWebhookAdapter struct {
+	H command.DeliverWebhookHandler
 }
 
-func (a EntryHandlerAdapter) Entry(ctx context.Context, botID bots.BotID, userID bots.UserID, key bots.EntryKey) error {
-	return a.H.Handle(ctx, request.EntryCommand{
-		BotID:  string(botID),
-		UserID: int64(userID),
-		Key:    string(key),
+func (a DeliverWebhookAdapter) Deliver(ctx context.Context, d port.WebhookDelivery) error {
+	return a.H.Handle(ctx, request.DeliverWebhookCommand{
+		DeliveryID: d.ID,
+		BotID:      string(d.BotID),
+		WebhookID:  string(d.WebhookID),
+		Event:      d.Event,
+		Payload:    d.Payload,
+		Attempts:   d.Attempts,
 	})
 }
+
+type APIKeyVerifierAdapter struct {
+	H     query.AuthenticateAPIKeyHandler
+	Touch command.TouchAPIKeyHandler
+}
+
+func (a APIKeyVerifierAdapter) VerifyAPIKey(ctx context.Context, key string) (string, jwtauth.Scope, error) {
+	k, err := a.H.Handle(ctx, request.AuthenticateAPIKeyQuery{Key: key})
+	if errors.Is(err, bots.ErrInvalidAPIKey) || errors.Is(err, bots.ErrAPIKeyExpired) {
+		return "", jwtauth.Scope{}, fmt.Errorf("%w: %s", jwtauth.ErrInvalidAPIKey, err.Error())
+	} else if err != nil {
+		return "", jwtauth.Scope{}, err
+	}
+	// Потеря отметки об использовании ключа не влияет на аутентификацию; ошибку журналирует декоратор команды
+	_ = a.Touch.Handle(ctx, request.TouchAPIKeyCommand{KeyID: k.ID, UsedAt: time.Now()})
+	return k.AccountID, jwtauth.Scope{Bots: k.Scope.Bots, Actions: k.Scope.Actions}, nil
+}
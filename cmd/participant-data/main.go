@@ -0,0 +1,177 @@
+// Команда participant-data выгружает или удаляет данные пользователя Telegram во всех ботах сервиса
+// либо в одном боте по запросу пользователя на доступ к данным или их удаление.
+//
+//	participant-data export -user 123456789 [-bot example_bot] > data.json
+//	participant-data erase -user 123456789 [-bot example_bot] [-anonymize]
+//
+// Без -bot обрабатываются все боты, в том числе удалённые. Выгрузка печатается в stdout в формате
+// ответа GET /bots/{id}/participants/{userId}/data.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/command"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/query"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/clients/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+const usage = "usage: participant-data export|erase -user <id> [-bot <id>] [-anonymize]"
+
+func main() {
+	if err := run(logs.DefaultLogger(), os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(l *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	action := args[0]
+	if action != "export" && action != "erase" {
+		return errors.New(usage)
+	}
+
+	fs := flag.NewFlagSet(action, flag.ContinueOnError)
+	userID := fs.Int64("user", 0, "ID пользователя Telegram")
+	botID := fs.String("bot", "", "ID бота; по умолчанию все боты")
+	anonymize := fs.Bool("anonymize", false, "оставить ответы, отвязав их от пользователя, вместо удаления")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *userID == 0 {
+		return errors.New(usage)
+	}
+
+	keys, err := envelope.KeyringFromEnv()
+	if err != nil {
+		return err
+	}
+
+	uri := os.Getenv("DATABASE_URI")
+	if uri == "" {
+		return errors.New("DATABASE_URI must be set")
+	}
+	db, err := sqlx.Connect("postgres", uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	repos := postgres.NewRepository(db, keys, nil, l)
+	mc := metrics.NoOp{}
+
+	if action == "erase" {
+		err = command.NewEraseUserDataHandler(repos, repos, l, mc).Handle(ctx, request.EraseUserDataCommand{
+			UserID:    *userID,
+			BotID:     *botID,
+			Anonymize: *anonymize,
+		})
+		if err != nil {
+			return err
+		}
+		l.InfoContext(ctx, "participant data erased",
+			slog.Int64("user_id", *userID),
+			slog.String("bot_id", *botID),
+			slog.Bool("anonymize", *anonymize),
+		)
+		return nil
+	}
+
+	data, err := query.NewExportUserDataHandler(repos, l, mc).Handle(ctx, request.ExportUserDataQuery{
+		UserID: *userID,
+		BotID:  *botID,
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(batchParticipantDataFromApp(data)); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+func batchParticipantDataFromApp(ds []dto.ParticipantData) []bots.ParticipantData {
+	res := make([]bots.ParticipantData, len(ds))
+	for i, d := range ds {
+		res[i] = participantDataFromApp(d)
+	}
+	return res
+}
+
+func participantDataFromApp(d dto.ParticipantData) bots.ParticipantData {
+	threads := make([]bots.ParticipantThread, len(d.Threads))
+	for i, thread := range d.Threads {
+		answers := make(map[string]string, len(thread.Answers))
+		for state, msg := range thread.Answers {
+			answers[strconv.Itoa(state)] = msg.Text
+		}
+		vars := thread.Vars
+		if vars == nil {
+			vars = make(map[string]string)
+		}
+		threads[i] = bots.ParticipantThread{
+			Id:        thread.ID,
+			Key:       thread.Key,
+			State:     thread.State,
+			StartedAt: thread.StartedAt,
+			Answers:   answers,
+			Vars:      vars,
+		}
+	}
+	records := make([]bots.ParticipantRecord, len(d.Records))
+	for i, r := range d.Records {
+		records[i] = bots.ParticipantRecord{
+			Source:    r.Source,
+			Payload:   json.RawMessage(r.Payload),
+			CreatedAt: r.CreatedAt,
+		}
+	}
+	res := bots.ParticipantData{
+		BotId:   d.BotID,
+		ChatId:  d.ChatID,
+		UserId:  d.UserID,
+		Threads: threads,
+		Records: records,
+	}
+	if !d.BlockedAt.IsZero() {
+		res.BlockedAt = &d.BlockedAt
+	}
+	if d.Profile.Username != "" {
+		res.Username = &d.Profile.Username
+	}
+	if d.Profile.FirstName != "" {
+		res.FirstName = &d.Profile.FirstName
+	}
+	if d.Profile.LastName != "" {
+		res.LastName = &d.Profile.LastName
+	}
+	if d.Profile.LanguageCode != "" {
+		res.LanguageCode = &d.Profile.LanguageCode
+	}
+	return res
+}
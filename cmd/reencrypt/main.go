@@ -0,0 +1,65 @@
+// Команда reencrypt шифрует активным ключом из TOKEN_ENCRYPTION_KEYS токены ботов и секреты Webhook,
+// которые хранятся открыто или зашифрованы прежними ключами. Запускается после добавления нового ключа в начало
+// связки; после неё прежние ключи можно удалить.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+)
+
+func main() {
+	if err := run(logs.DefaultLogger()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(l *slog.Logger) error {
+	keys, err := envelope.KeyringFromEnv()
+	if err != nil {
+		return err
+	}
+	if keys == nil {
+		return errors.New("TOKEN_ENCRYPTION_KEYS or TOKEN_ENCRYPTION_KEYS_FILE must be set")
+	}
+
+	uri := os.Getenv("DATABASE_URI")
+	if uri == "" {
+		return errors.New("DATABASE_URI must be set")
+	}
+	db, err := sqlx.Connect("postgres", uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	repos := postgres.NewRepository(db, keys, nil, l)
+	n, err := repos.ReencryptBotTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("reencrypted %d bot tokens before failure: %w", n, err)
+	}
+	l.InfoContext(ctx, "bot tokens reencrypted", slog.String("key_id", keys.ActiveKeyID()), slog.Int("reencrypted", n))
+
+	n, err = repos.ReencryptWebhookSecrets(ctx)
+	if err != nil {
+		return fmt.Errorf("reencrypted %d webhook secrets before failure: %w", n, err)
+	}
+	l.InfoContext(ctx, "webhook secrets reencrypted",
+		slog.String("key_id", keys.ActiveKeyID()), slog.Int("reencrypted", n))
+	return nil
+}
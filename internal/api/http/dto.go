@@ -1,9 +1,12 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
 )
 
 func batchBotsFromApp(bots []dto.Bot) []Bot {
@@ -15,13 +18,296 @@ func batchBotsFromApp(bots []dto.Bot) []Bot {
 }
 
 func botFromApp(bot dto.Bot) Bot {
-	return Bot{
-		Author:  bot.Author,
-		Enabled: bot.Enabled,
-		Id:      bot.ID,
-		Script:  scriptFromApp(bot.Script),
-		Token:   bot.Token,
+	res := Bot{
+		Author:     bot.Author,
+		ChatPolicy: ChatPolicy(bot.ChatPolicy),
+		Enabled:    bot.Enabled,
+		Id:         bot.ID,
+		Script:     scriptFromApp(bot.Script),
+		Token:      bot.Token,
 	}
+	if bot.TelegramID != 0 {
+		res.Telegram = &TelegramBot{
+			Id:       bot.TelegramID,
+			Username: bot.TelegramUsername,
+		}
+	}
+	return res
+}
+
+func batchDeletedBotsFromApp(bs []dto.DeletedBot) []DeletedBot {
+	res := make([]DeletedBot, len(bs))
+	for i, b := range bs {
+		res[i] = DeletedBot{
+			Bot:       botFromApp(b.Bot),
+			DeletedAt: b.DeletedAt,
+		}
+	}
+	return res
+}
+
+func participantStatsFromApp(stats dto.ParticipantStats) ParticipantStats {
+	return ParticipantStats{
+		Blocked: stats.Blocked,
+		Total:   stats.Total,
+	}
+}
+
+func botStatusFromApp(status response.GetStatusResponse) BotStatus {
+	res := BotStatus{
+		Status:           Status(status.Status),
+		UpdatesProcessed: status.UpdatesProcessed,
+		MessagesSent:     status.MessagesSent,
+		Restarts:         status.Restarts,
+	}
+	if status.Replica != "" {
+		res.Replica = &status.Replica
+	}
+	if !status.HeartbeatAt.IsZero() {
+		res.HeartbeatAt = &status.HeartbeatAt
+	}
+	if status.StatsReplica != "" {
+		res.StatsReplica = &status.StatsReplica
+	}
+	if status.Mode != "" {
+		mode := UpdateMode(status.Mode)
+		res.Mode = &mode
+	}
+	if status.TelegramID != 0 {
+		res.Telegram = &TelegramBot{
+			Id:       status.TelegramID,
+			Username: status.Username,
+		}
+	}
+	if !status.StartedAt.IsZero() {
+		res.StartedAt = &status.StartedAt
+	}
+	if !status.LastUpdateAt.IsZero() {
+		res.LastUpdateAt = &status.LastUpdateAt
+	}
+	if status.LastError != "" {
+		res.LastError = &status.LastError
+		res.LastErrorAt = &status.LastErrorAt
+	}
+	return res
+}
+
+func batchCollaboratorsFromApp(cs []dto.Collaborator) []Collaborator {
+	res := make([]Collaborator, len(cs))
+	for i, c := range cs {
+		res[i] = Collaborator{
+			AccountId: c.AccountID,
+			Role:      Role(c.Role),
+			AddedAt:   c.AddedAt,
+		}
+	}
+	return res
+}
+
+func batchApiKeysFromApp(ks []dto.APIKey) []ApiKey {
+	res := make([]ApiKey, len(ks))
+	for i, k := range ks {
+		res[i] = apiKeyFromApp(k)
+	}
+	return res
+}
+
+func apiKeyFromApp(k dto.APIKey) ApiKey {
+	actions := make([]ApiKeyAction, len(k.Scope.Actions))
+	for i, a := range k.Scope.Actions {
+		actions[i] = ApiKeyAction(a)
+	}
+	return ApiKey{
+		Id:         k.ID,
+		Name:       k.Name,
+		Bots:       k.Scope.Bots,
+		Actions:    actions,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func apiKeyScopeToApp(req PostApiKey) dto.APIKeyScope {
+	actions := make([]string, len(req.Actions))
+	for i, a := range req.Actions {
+		actions[i] = string(a)
+	}
+	return dto.APIKeyScope{
+		Bots:    emptyOnNil(req.Bots),
+		Actions: actions,
+	}
+}
+
+func batchWebhooksFromApp(ws []dto.Webhook) []Webhook {
+	res := make([]Webhook, len(ws))
+	for i, w := range ws {
+		res[i] = webhookFromApp(w)
+	}
+	return res
+}
+
+func webhookFromApp(w dto.Webhook) Webhook {
+	return Webhook{
+		Id:        w.ID,
+		Url:       w.URL,
+		Events:    w.Events,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+func batchWebhookDeliveriesFromApp(ds []dto.WebhookDelivery) []WebhookDelivery {
+	res := make([]WebhookDelivery, len(ds))
+	for i, d := range ds {
+		res[i] = webhookDeliveryFromApp(d)
+	}
+	return res
+}
+
+func webhookDeliveryFromApp(d dto.WebhookDelivery) WebhookDelivery {
+	res := WebhookDelivery{
+		Id:        d.ID,
+		Event:     d.Event,
+		Status:    WebhookDeliveryStatus(d.Status),
+		Attempts:  d.Attempts,
+		CreatedAt: d.CreatedAt,
+	}
+	// Тело запроса формируется сервисом и всегда является JSON-объектом
+	_ = json.Unmarshal(d.Payload, &res.Payload)
+	if d.ResponseCode != 0 {
+		res.ResponseCode = &d.ResponseCode
+	}
+	if d.LastError != "" {
+		res.LastError = &d.LastError
+	}
+	if d.Status == string(Pending) {
+		res.NextAttemptAt = &d.NextAttemptAt
+	}
+	if !d.DeliveredAt.IsZero() {
+		res.DeliveredAt = &d.DeliveredAt
+	}
+	return res
+}
+
+func batchAuditEntriesFromApp(es []dto.AuditEntry) []AuditEntry {
+	res := make([]AuditEntry, len(es))
+	for i, e := range es {
+		res[i] = auditEntryFromApp(e)
+	}
+	return res
+}
+
+func auditEntryFromApp(e dto.AuditEntry) AuditEntry {
+	res := AuditEntry{
+		Id:        e.ID,
+		Actor:     e.Actor,
+		Action:    e.Action,
+		CreatedAt: e.At,
+	}
+	// Параметры команды сохраняются декоратором аудита и всегда являются JSON-объектом
+	_ = json.Unmarshal(e.Payload, &res.Payload)
+	if e.Error != "" {
+		res.Error = &e.Error
+	}
+	return res
+}
+
+func threadsPageFromApp(page response.GetBotThreadsResponse) ThreadsPage {
+	threads := make([]Thread, len(page.Threads))
+	for i, thread := range page.Threads {
+		threads[i] = threadFromApp(thread)
+	}
+	res := ThreadsPage{Threads: threads}
+	if page.NextCursor != "" {
+		res.NextCursor = &page.NextCursor
+	}
+	return res
+}
+
+func threadFromApp(thread dto.Thread) Thread {
+	answers := make(map[string]string, len(thread.Answers))
+	for state, msg := range thread.Answers {
+		answers[strconv.Itoa(state)] = msg.Text
+	}
+	return Thread{
+		Id:        thread.ID,
+		Key:       thread.Key,
+		UserId:    thread.UserID,
+		Username:  thread.Username,
+		State:     thread.State,
+		StartedAt: thread.StartedAt,
+		Completed: thread.Completed,
+		Answers:   answers,
+	}
+}
+
+func batchParticipantDataFromApp(ds []dto.ParticipantData) []ParticipantData {
+	res := make([]ParticipantData, len(ds))
+	for i, d := range ds {
+		res[i] = participantDataFromApp(d)
+	}
+	return res
+}
+
+func participantDataFromApp(d dto.ParticipantData) ParticipantData {
+	threads := make([]ParticipantThread, len(d.Threads))
+	for i, thread := range d.Threads {
+		threads[i] = participantThreadFromApp(thread)
+	}
+	records := make([]ParticipantRecord, len(d.Records))
+	for i, r := range d.Records {
+		records[i] = ParticipantRecord{Source: r.Source, Payload: json.RawMessage(r.Payload), CreatedAt: r.CreatedAt}
+	}
+	res := ParticipantData{
+		BotId:   d.BotID,
+		ChatId:  d.ChatID,
+		UserId:  d.UserID,
+		Threads: threads,
+		Records: records,
+	}
+	if !d.BlockedAt.IsZero() {
+		res.BlockedAt = &d.BlockedAt
+	}
+	if d.Profile.Username != "" {
+		res.Username = &d.Profile.Username
+	}
+	if d.Profile.FirstName != "" {
+		res.FirstName = &d.Profile.FirstName
+	}
+	if d.Profile.LastName != "" {
+		res.LastName = &d.Profile.LastName
+	}
+	if d.Profile.LanguageCode != "" {
+		res.LanguageCode = &d.Profile.LanguageCode
+	}
+	return res
+}
+
+func participantThreadFromApp(thread dto.ParticipantThread) ParticipantThread {
+	answers := make(map[string]string, len(thread.Answers))
+	for state, msg := range thread.Answers {
+		answers[strconv.Itoa(state)] = msg.Text
+	}
+	vars := thread.Vars
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	return ParticipantThread{
+		Id:        thread.ID,
+		Key:       thread.Key,
+		State:     thread.State,
+		StartedAt: thread.StartedAt,
+		Answers:   answers,
+		Vars:      vars,
+	}
+}
+
+// chatPolicyToApp возвращает пустую строку, если политика не указана; тогда используется политика по умолчанию.
+func chatPolicyToApp(p *ChatPolicy) string {
+	if p == nil {
+		return ""
+	}
+	return string(*p)
 }
 
 func scriptToApp(bot Script) (dto.Script, error) {
@@ -85,6 +371,7 @@ func nodeToApp(node Node) (dto.Node, error) {
 		Edges:    edges,
 		Messages: batchMessageToApp(node.Messages),
 		Options:  emptyOnNil(node.Options),
+		Request:  nodeRequestToApp(node.Request),
 	}, nil
 }
 
@@ -95,9 +382,50 @@ func nodeFromApp(node dto.Node) Node {
 		State:    node.State,
 		Title:    node.Title,
 		Options:  nilOnEmpty(node.Options),
+		Request:  nodeRequestFromApp(node.Request),
 	}
 }
 
+func nodeRequestToApp(req *NodeRequest) *dto.NodeRequest {
+	if req == nil {
+		return nil
+	}
+	res := &dto.NodeRequest{
+		Method:    req.Method,
+		URL:       req.Url,
+		OnSuccess: req.OnSuccess,
+		OnFailure: req.OnFailure,
+	}
+	if req.Headers != nil {
+		res.Headers = *req.Headers
+	}
+	if req.Body != nil {
+		res.Body = *req.Body
+	}
+	if req.Save != nil {
+		res.Save = *req.Save
+	}
+	return res
+}
+
+func nodeRequestFromApp(req *dto.NodeRequest) *NodeRequest {
+	if req == nil {
+		return nil
+	}
+	res := &NodeRequest{
+		Method:    req.Method,
+		Url:       req.URL,
+		Headers:   nilOnEmptyMap(req.Headers),
+		Save:      nilOnEmptyMap(req.Save),
+		OnSuccess: req.OnSuccess,
+		OnFailure: req.OnFailure,
+	}
+	if req.Body != "" {
+		res.Body = &req.Body
+	}
+	return res
+}
+
 func batchNodeToApp(nodes []Node) ([]dto.Node, error) {
 	res := make([]dto.Node, len(nodes))
 	for i, node := range nodes {
@@ -6,11 +6,13 @@ package http
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/oapi-codegen/runtime"
 )
 
 const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
@@ -19,6 +21,20 @@ const (
 	Always AlwaysPredicateType = "always"
 )
 
+// Defines values for ApiKeyAction.
+const (
+	Edit   ApiKeyAction = "edit"
+	Manage ApiKeyAction = "manage"
+	View   ApiKeyAction = "view"
+)
+
+// Defines values for ChatPolicy.
+const (
+	Groups   ChatPolicy = "groups"
+	Mentions ChatPolicy = "mentions"
+	Private  ChatPolicy = "private"
+)
+
 // Defines values for EdgeOperation.
 const (
 	Append EdgeOperation = "append"
@@ -36,6 +52,13 @@ const (
 	Regex RegexPredicateType = "regex"
 )
 
+// Defines values for Role.
+const (
+	Editor Role = "editor"
+	Owner  Role = "owner"
+	Viewer Role = "viewer"
+)
+
 // Defines values for Status.
 const (
 	Dead    Status = "dead"
@@ -43,6 +66,25 @@ const (
 	Running Status = "running"
 )
 
+// Defines values for UpdateMode.
+const (
+	UpdateModePolling UpdateMode = "polling"
+	UpdateModeWebhook UpdateMode = "webhook"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	Delivered WebhookDeliveryStatus = "delivered"
+	Failed    WebhookDeliveryStatus = "failed"
+	Pending   WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for GetBotThreadsParamsSort.
+const (
+	GetBotThreadsParamsSortMinusStartedAt GetBotThreadsParamsSort = "-startedAt"
+	GetBotThreadsParamsSortStartedAt      GetBotThreadsParamsSort = "startedAt"
+)
+
 // AlwaysPredicate Переход по ребру осуществляется на любое сообщение пользователя.
 type AlwaysPredicate struct {
 	Type AlwaysPredicateType `json:"type"`
@@ -51,10 +93,61 @@ type AlwaysPredicate struct {
 // AlwaysPredicateType defines model for AlwaysPredicate.Type.
 type AlwaysPredicateType string
 
+// ApiKey API-ключ пользователя. Доступ по ключу не превышает роли пользователя в боте.
+type ApiKey struct {
+	// Actions Действия, разрешённые по ключу.
+	Actions []ApiKeyAction `json:"actions"`
+
+	// Bots ID ботов, для которых действует ключ. Пустой список означает все боты пользователя.
+	Bots []string `json:"bots"`
+
+	// CreatedAt Время создания ключа.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt Время, после которого ключ перестаёт действовать.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Id ID API-ключа.
+	Id string `json:"id"`
+
+	// LastUsedAt Время последнего использования ключа. Отсутствует, если ключ не использовался.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	// Name Название ключа, например, имя CI-пайплайна.
+	Name string `json:"name"`
+}
+
+// ApiKeyAction Действие над ботом, разрешённое по API-ключу: view - просмотр бота и ответов участников; edit - изменение, запуск и остановка бота, рассылки и управление Webhook; manage - удаление бота и управление соавторами. Действия не включают друг друга.
+type ApiKeyAction string
+
+// AuditEntry Запись журнала аудита о команде пользователя.
+type AuditEntry struct {
+	// Action Действие, например CreateBot или DeleteBot.
+	Action string `json:"action"`
+
+	// Actor Пользователь, выполнивший команду (субъект JWT-токена или владелец API-ключа).
+	Actor string `json:"actor"`
+
+	// CreatedAt Время выполнения команды.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Error Ошибка, с которой была отклонена команда. Отсутствует, если команда выполнена успешно.
+	Error *string `json:"error,omitempty"`
+
+	// Id Уникальный ID записи.
+	Id int64 `json:"id"`
+
+	// Payload Параметры команды; токены и секреты скрыты.
+	Payload map[string]interface{} `json:"payload"`
+}
+
 // Bot defines model for Bot.
 type Bot struct {
-	// Author ID пользователя - автора бота.
-	Author int64 `json:"author"`
+	// Author Идентификатор пользователя - автора бота (субъект JWT-токена).
+	Author string `json:"author"`
+
+	// ChatPolicy В каких чатах бот отвечает на сообщения. - private. Только в личных чатах, сообщения из групп игнорируются. - mentions. В личных чатах и группах; в группах только на команды, упоминания бота и ответы на его сообщения. - groups. В личных чатах и группах на любые сообщения.
+	ChatPolicy ChatPolicy `json:"chatPolicy"`
 
 	// Enabled Автозапуск бота
 	Enabled bool `json:"enabled"`
@@ -65,10 +158,78 @@ type Bot struct {
 	// Script Сценарий бота.
 	Script Script `json:"script"`
 
-	// Token Телеграм токен для бота, полученный в @BotFather.
+	// Telegram Телеграм-аккаунт бота, полученный методом getMe.
+	Telegram *TelegramBot `json:"telegram,omitempty"`
+
+	// Token Телеграм токен бота со скрытой секретной частью, например `123456:***`.
 	Token string `json:"token"`
 }
 
+// BotStatus Подробный статус инстанса бота. Счётчики ведутся репликой, опрашивающей бота, и сбрасываются при её перезапуске. Если бот опрашивается другой репликой, возвращаются только status, replica и mode.
+type BotStatus struct {
+	// HeartbeatAt Время последнего продления аренды бота репликой replica.
+	HeartbeatAt *time.Time `json:"heartbeatAt,omitempty"`
+
+	// LastError Последняя ошибка опроса Telegram. Отсутствует, если ошибок не было.
+	LastError *string `json:"lastError,omitempty"`
+
+	// LastErrorAt Время последней ошибки опроса Telegram.
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+
+	// LastUpdateAt Время получения последнего обновления от Telegram.
+	LastUpdateAt *time.Time `json:"lastUpdateAt,omitempty"`
+
+	// MessagesSent Количество отправленных ботом сообщений.
+	MessagesSent int `json:"messagesSent"`
+
+	// Mode Способ получения обновлений от Telegram.
+	Mode *UpdateMode `json:"mode,omitempty"`
+
+	// Replica Реплика сервиса, опрашивающая бота. Отсутствует, если бот не запущен.
+	Replica *string `json:"replica,omitempty"`
+
+	// Restarts Количество автоматических перезапусков инстанса после сбоев опроса Telegram.
+	Restarts int `json:"restarts"`
+
+	// StartedAt Время запуска инстанса.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// StatsReplica Реплика, счётчики которой приведены в ответе (startedAt, lastUpdateAt, updatesProcessed, messagesSent, restarts, lastError). Отсутствует, если статус построен не репликой, опрашивающей бота: тогда счётчики не заполнены.
+	StatsReplica *string `json:"statsReplica,omitempty"`
+
+	// Status Статус инстанса бота.
+	Status Status `json:"status"`
+
+	// Telegram Телеграм-аккаунт бота, полученный методом getMe.
+	Telegram *TelegramBot `json:"telegram,omitempty"`
+
+	// UpdatesProcessed Количество обработанных обновлений.
+	UpdatesProcessed int `json:"updatesProcessed"`
+}
+
+// ChatPolicy В каких чатах бот отвечает на сообщения. - private. Только в личных чатах, сообщения из групп игнорируются. - mentions. В личных чатах и группах; в группах только на команды, упоминания бота и ответы на его сообщения. - groups. В личных чатах и группах на любые сообщения.
+type ChatPolicy string
+
+// Collaborator Пользователь, имеющий доступ к боту.
+type Collaborator struct {
+	// AccountId Идентификатор пользователя (субъект JWT-токена).
+	AccountId string `json:"accountId"`
+
+	// AddedAt Время предоставления доступа.
+	AddedAt time.Time `json:"addedAt"`
+
+	// Role Роль пользователя в боте: owner может всё, в том числе удалять бота и управлять соавторами; editor - изменять, запускать и останавливать бота, делать рассылки и управлять Webhook; viewer - только просматривать бота и ответы участников.
+	Role Role `json:"role"`
+}
+
+// DeletedBot Удалённый бот. Его ID нельзя занять, пока бот не будет восстановлен или удалён окончательно.
+type DeletedBot struct {
+	Bot Bot `json:"bot"`
+
+	// DeletedAt Время удаления бота.
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
 // Edge Обозначают связь между узлами как переход в результате ответа пользователя.
 type Edge struct {
 	// Operation Действие, которое выполнится в результате перехода пользователя по ребру. - noop. Ничего не происходит. Подходит для использования в меню и промежуточных узлах. - save. Сохраняет ответ или перезаписывает предыдущий. Подходит в большинстве ситуаций. - append. Добавляет ответ к предыдущему. Подходит для вопросов с множественным выбором.
@@ -127,17 +288,29 @@ type Message struct {
 	Text string `json:"text"`
 }
 
+// NewApiKey Созданный API-ключ.
+type NewApiKey struct {
+	// ApiKey API-ключ пользователя. Доступ по ключу не превышает роли пользователя в боте.
+	ApiKey ApiKey `json:"apiKey"`
+
+	// Key API-ключ для заголовка X-API-Key. Показывается только один раз.
+	Key string `json:"key"`
+}
+
 // Node Минимальная структурная единица сценария бота. Представляет собой сообщение (сообщения), которые отправляются пользователю. Ожидается ответ пользователя для перехода к следующему узлу.
 type Node struct {
 	// Edges Массив исходящих рёбер узла.
 	Edges *[]Edge `json:"edges,omitempty"`
 
-	// Messages Массив отправляемых ботом сообщений при вхождении в узел.
+	// Messages Массив отправляемых ботом сообщений при вхождении в узел. Может быть пустым только у узла-запроса. Подстановки {{answers.N}} и {{vars.name}} заменяются ответом на узел N и переменной треда name.
 	Messages []Message `json:"messages"`
 
 	// Options Массив кнопок (опций) ответа для пользователя.
 	Options *[]string `json:"options,omitempty"`
 
+	// Request HTTP-запрос узла-запроса. Узел-запрос не ждёт ответа пользователя и не имеет рёбер и опций: после отправки сообщений выполняется запрос, и по его результату тред переходит в onSuccess или onFailure. В url, значениях headers и body подстановки {{answers.N}} и {{vars.name}} заменяются ответом на узел N и переменной треда name.
+	Request *NodeRequest `json:"request,omitempty"`
+
 	// State Уникальный номер узла в сценарии бота.
 	State int `json:"state"`
 
@@ -145,11 +318,134 @@ type Node struct {
 	Title string `json:"title"`
 }
 
+// NodeRequest HTTP-запрос узла-запроса. Узел-запрос не ждёт ответа пользователя и не имеет рёбер и опций: после отправки сообщений выполняется запрос, и по его результату тред переходит в onSuccess или onFailure. В url, значениях headers и body подстановки {{answers.N}} и {{vars.name}} заменяются ответом на узел N и переменной треда name.
+type NodeRequest struct {
+	// Body Шаблон JSON-тела запроса.
+	Body *string `json:"body,omitempty"`
+
+	// Headers Заголовки запроса. Пользователю, который не может изменять бота, значения заголовков возвращаются скрытыми (***).
+	Headers *map[string]string `json:"headers,omitempty"`
+
+	// Method HTTP-метод - GET, POST, PUT, PATCH или DELETE.
+	Method string `json:"method"`
+
+	// OnFailure Узел, в который переходит тред в остальных случаях.
+	OnFailure int `json:"onFailure"`
+
+	// OnSuccess Узел, в который переходит тред, если получен ответ 2xx и найдены все поля save.
+	OnSuccess int `json:"onSuccess"`
+
+	// Save Сохраняемые в переменные треда поля JSON-ответа: имя переменной -> путь к полю через точку (индексы массивов - числами).
+	Save *map[string]string `json:"save,omitempty"`
+
+	// Url Шаблон абсолютного http или https URL.
+	Url string `json:"url"`
+}
+
+// ParticipantData Персональные данные участника бота: профиль Telegram и все его треды с ответами.
+type ParticipantData struct {
+	// BlockedAt Время блокировки бота участником. Отсутствует, если участник не блокировал бота.
+	BlockedAt *time.Time `json:"blockedAt,omitempty"`
+
+	// BotId ID бота.
+	BotId string `json:"botId"`
+
+	// ChatId ID чата Telegram, в котором участник общается с ботом.
+	ChatId int64 `json:"chatId"`
+
+	// FirstName Имя пользователя в Telegram.
+	FirstName *string `json:"firstName,omitempty"`
+
+	// LanguageCode Код языка клиента Telegram.
+	LanguageCode *string `json:"languageCode,omitempty"`
+
+	// LastName Фамилия пользователя в Telegram.
+	LastName *string `json:"lastName,omitempty"`
+
+	// Records Копии данных участника вне тредов, начиная с самой ранней в каждой таблице.
+	Records []ParticipantRecord `json:"records"`
+
+	// Threads Треды участника, начиная с самого раннего.
+	Threads []ParticipantThread `json:"threads"`
+
+	// UserId ID пользователя Telegram.
+	UserId int64 `json:"userId"`
+
+	// Username Имя пользователя (username) в Telegram.
+	Username *string `json:"username,omitempty"`
+}
+
+// ParticipantRecord Копия данных участника вне тредов: событие, тело Webhook в очереди доставки или запись журнала аудита.
+type ParticipantRecord struct {
+	// CreatedAt Время создания записи.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Payload JSON записи.
+	Payload interface{} `json:"payload"`
+
+	// Source Таблица, в которой хранится запись.
+	Source string `json:"source"`
+}
+
+// ParticipantStats Статистика участников бота.
+type ParticipantStats struct {
+	// Blocked Количество участников, заблокировавших бота. Рассылки им не отправляются.
+	Blocked int `json:"blocked"`
+
+	// Total Количество всех участников бота.
+	Total int `json:"total"`
+}
+
+// ParticipantThread Тред участника - одно прохождение сценария бота.
+type ParticipantThread struct {
+	// Answers Ответы участника: State узла -> текст ответа.
+	Answers map[string]string `json:"answers"`
+
+	// Id ID треда.
+	Id string `json:"id"`
+
+	// Key Ключ точки входа, с которой начат тред.
+	Key string `json:"key"`
+
+	// StartedAt Время начала треда.
+	StartedAt time.Time `json:"startedAt"`
+
+	// State State узла, в котором находится тред.
+	State int `json:"state"`
+
+	// Vars Переменные треда.
+	Vars map[string]string `json:"vars"`
+}
+
 // PlainError defines model for PlainError.
 type PlainError struct {
 	Message string `json:"message"`
 }
 
+// PostApiKey defines model for PostApiKey.
+type PostApiKey struct {
+	// Actions Действия, разрешённые по ключу.
+	Actions []ApiKeyAction `json:"actions"`
+
+	// Bots ID ботов, для которых действует ключ. Если не указаны, ключ действует для всех ботов пользователя.
+	Bots *[]string `json:"bots,omitempty"`
+
+	// ExpiresAt Время, после которого ключ перестаёт действовать.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Name Название ключа, например, имя CI-пайплайна.
+	Name string `json:"name"`
+}
+
+// PostCollaborator defines model for PostCollaborator.
+type PostCollaborator struct {
+	// AccountId Идентификатор пользователя (субъект JWT-токена).
+	AccountId string `json:"accountId"`
+
+	// Role Роль пользователя в боте: owner может всё, в том числе удалять бота и управлять соавторами; editor - изменять, запускать и останавливать бота, делать рассылки и управлять Webhook; viewer - только просматривать бота и ответы участников.
+	Role Role `json:"role"`
+}
+
 // PostMailing defines model for PostMailing.
 type PostMailing struct {
 	// EntryKey Ключ точки входа, которая будет выполнена для списка пользователей.
@@ -166,16 +462,37 @@ type Predicate struct {
 
 // PutBots defines model for PutBots.
 type PutBots struct {
+	// ChatPolicy В каких чатах бот отвечает на сообщения. - private. Только в личных чатах, сообщения из групп игнорируются. - mentions. В личных чатах и группах; в группах только на команды, упоминания бота и ответы на его сообщения. - groups. В личных чатах и группах на любые сообщения.
+	ChatPolicy *ChatPolicy `json:"chatPolicy,omitempty"`
+
 	// Id Уникальный ID бота.
 	Id string `json:"id"`
 
 	// Script Сценарий бота.
 	Script Script `json:"script"`
 
-	// Token Телеграм токен для бота, полученный в @BotFather.
+	// Token Телеграм токен для бота, полученный в @BotFather. Токен со скрытой секретной частью, полученный вместе с ботом, оставляет текущий токен без изменений.
 	Token string `json:"token"`
 }
 
+// PutCollaborator defines model for PutCollaborator.
+type PutCollaborator struct {
+	// Role Роль пользователя в боте: owner может всё, в том числе удалять бота и управлять соавторами; editor - изменять, запускать и останавливать бота, делать рассылки и управлять Webhook; viewer - только просматривать бота и ответы участников.
+	Role Role `json:"role"`
+}
+
+// PutWebhook defines model for PutWebhook.
+type PutWebhook struct {
+	// Events События, на которые подписывается Webhook: thread_started, answer_saved, thread_completed, bot_enabled, bot_disabled, mailing_finished.
+	Events []string `json:"events"`
+
+	// Secret Секрет не короче 16 символов, которым подписывается тело запроса.
+	Secret string `json:"secret"`
+
+	// Url Абсолютный http или https URL, на который отправляются события.
+	Url string `json:"url"`
+}
+
 // RegexPredicate Переход по ребру осуществляется при совпадении с регулярным выражением pattern.
 type RegexPredicate struct {
 	Pattern string             `json:"pattern"`
@@ -185,6 +502,9 @@ type RegexPredicate struct {
 // RegexPredicateType defines model for RegexPredicate.Type.
 type RegexPredicateType string
 
+// Role Роль пользователя в боте: owner может всё, в том числе удалять бота и управлять соавторами; editor - изменять, запускать и останавливать бота, делать рассылки и управлять Webhook; viewer - только просматривать бота и ответы участников.
+type Role string
+
 // Script Сценарий бота.
 type Script struct {
 	Entries []Entry `json:"entries"`
@@ -194,12 +514,180 @@ type Script struct {
 // Status Статус инстанса бота.
 type Status string
 
+// TelegramBot Телеграм-аккаунт бота, полученный методом getMe.
+type TelegramBot struct {
+	// Id ID бота в Telegram.
+	Id int64 `json:"id"`
+
+	// Username Username бота в Telegram.
+	Username string `json:"username"`
+}
+
+// Thread Тред - одно прохождение пользователем сценария бота, начиная с точки входа.
+type Thread struct {
+	// Answers Ответы пользователя: State узла -> текст ответа.
+	Answers map[string]string `json:"answers"`
+
+	// Completed Тред достиг узла без исходящих рёбер.
+	Completed bool `json:"completed"`
+
+	// Id ID треда.
+	Id string `json:"id"`
+
+	// Key Ключ точки входа, с которой начат тред.
+	Key string `json:"key"`
+
+	// StartedAt Время начала треда.
+	StartedAt time.Time `json:"startedAt"`
+
+	// State State узла, в котором находится тред.
+	State int `json:"state"`
+
+	// UserId ID пользователя Telegram.
+	UserId int64 `json:"userId"`
+
+	// Username Имя пользователя в Telegram или, если его нет, ID пользователя в виде id123.
+	Username string `json:"username"`
+}
+
+// ThreadsPage Страница тредов бота.
+type ThreadsPage struct {
+	// NextCursor Курсор следующей страницы. Отсутствует, если страница последняя.
+	NextCursor *string `json:"nextCursor,omitempty"`
+
+	// Threads Треды страницы.
+	Threads []Thread `json:"threads"`
+}
+
+// UpdateMode Способ получения обновлений от Telegram.
+type UpdateMode string
+
+// Webhook Подписка внешней системы на события бота. Секрет не возвращается.
+type Webhook struct {
+	// CreatedAt Время создания Webhook.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Events События, на которые подписан Webhook.
+	Events []string `json:"events"`
+
+	// Id Уникальный в пределах бота ID Webhook.
+	Id string `json:"id"`
+
+	// Url URL, на который отправляются события.
+	Url string `json:"url"`
+}
+
+// WebhookDelivery Запись журнала доставки события на Webhook.
+type WebhookDelivery struct {
+	// Attempts Количество совершённых попыток доставки.
+	Attempts int `json:"attempts"`
+
+	// CreatedAt Время возникновения события.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// DeliveredAt Время успешной доставки.
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+
+	// Event Имя события.
+	Event string `json:"event"`
+
+	// Id ID доставки, передаётся в заголовке X-ItsReg-Delivery.
+	Id int64 `json:"id"`
+
+	// LastError Ошибка последней попытки. Отсутствует, если ошибки не было.
+	LastError *string `json:"lastError,omitempty"`
+
+	// NextAttemptAt Время следующей попытки. Отсутствует, если попыток больше не будет.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+
+	// Payload Отправленное тело запроса.
+	Payload map[string]interface{} `json:"payload"`
+
+	// ResponseCode HTTP-код ответа на последнюю попытку. Отсутствует, если ответа не было.
+	ResponseCode *int `json:"responseCode,omitempty"`
+
+	// Status Статус доставки. - pending. Доставка ожидает очередной попытки. - delivered. Получатель ответил кодом 2xx. - failed. Попытки доставки исчерпаны.
+	Status WebhookDeliveryStatus `json:"status"`
+}
+
+// WebhookDeliveryStatus Статус доставки. - pending. Доставка ожидает очередной попытки. - delivered. Получатель ответил кодом 2xx. - failed. Попытки доставки исчерпаны.
+type WebhookDeliveryStatus string
+
+// EraseParticipantDataParams defines parameters for EraseParticipantData.
+type EraseParticipantDataParams struct {
+	// Anonymize Оставить треды пользователя, отвязав их от него и заменив тексты ответов, вместо удаления.
+	Anonymize *bool `form:"anonymize,omitempty" json:"anonymize,omitempty"`
+}
+
+// GetAuditLogParams defines parameters for GetAuditLog.
+type GetAuditLogParams struct {
+	// Action Вернуть только записи о данном действии, например DeleteBot.
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// Before Вернуть записи старше записи с данным ID (ID последней записи предыдущей страницы).
+	Before *int64 `form:"before,omitempty" json:"before,omitempty"`
+
+	// Limit Максимальное количество записей в ответе.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetBotThreadsParams defines parameters for GetBotThreads.
+type GetBotThreadsParams struct {
+	// Entry Вернуть только треды, начатые с данной точки входа.
+	Entry *string `form:"entry,omitempty" json:"entry,omitempty"`
+
+	// StartedAfter Вернуть только треды, начатые не раньше данного времени.
+	StartedAfter *time.Time `form:"startedAfter,omitempty" json:"startedAfter,omitempty"`
+
+	// StartedBefore Вернуть только треды, начатые раньше данного времени.
+	StartedBefore *time.Time `form:"startedBefore,omitempty" json:"startedBefore,omitempty"`
+
+	// Completed Вернуть только завершённые (true) или незавершённые (false) треды. Тред завершён, если достиг узла без исходящих рёбер.
+	Completed *bool `form:"completed,omitempty" json:"completed,omitempty"`
+
+	// Contains Вернуть только треды, хотя бы один ответ которых содержит данную строку без учёта регистра.
+	Contains *string `form:"contains,omitempty" json:"contains,omitempty"`
+
+	// Sort Порядок тредов по времени начала: startedAt - от ранних к поздним, -startedAt - от поздних к ранним.
+	Sort *GetBotThreadsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor Курсор следующей страницы из ответа на предыдущий запрос.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Максимальное количество тредов в ответе.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetBotThreadsParamsSort defines parameters for GetBotThreads.
+type GetBotThreadsParamsSort string
+
+// GetWebhookDeliveriesParams defines parameters for GetWebhookDeliveries.
+type GetWebhookDeliveriesParams struct {
+	// Limit Максимальное количество доставок в ответе.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // CreateBotJSONRequestBody defines body for CreateBot for application/json ContentType.
 type CreateBotJSONRequestBody = PutBots
 
 // MailingJSONRequestBody defines body for Mailing for application/json ContentType.
 type MailingJSONRequestBody = PostMailing
 
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = PutWebhook
+
+// UpdateWebhookJSONRequestBody defines body for UpdateWebhook for application/json ContentType.
+type UpdateWebhookJSONRequestBody = PutWebhook
+
+// InviteCollaboratorJSONRequestBody defines body for InviteCollaborator for application/json ContentType.
+type InviteCollaboratorJSONRequestBody = PostCollaborator
+
+// UpdateCollaboratorJSONRequestBody defines body for UpdateCollaborator for application/json ContentType.
+type UpdateCollaboratorJSONRequestBody = PutCollaborator
+
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = PostApiKey
+
 // AsPlainError returns the union data inside the Error as a PlainError
 func (t Error) AsPlainError() (PlainError, error) {
 	var body PlainError
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"crypto/rand"
 	"encoding/csv"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/go-chi/render"
 
@@ -13,7 +16,8 @@ import (
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
-	"github.com/bmstu-itstech/itsreg-bots/pkg/salad"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/jwtauth"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/uuid"
 )
 
 type Server struct {
@@ -24,9 +28,26 @@ func NewHTTPServer(app *app.Application) *Server {
 	return &Server{app: app}
 }
 
+// accountID возвращает пользователя, от имени которого выполняется запрос. Его наличие
+// гарантируется jwtauth.NewHTTPMiddleware.
+func accountID(r *http.Request) string {
+	subject, _ := jwtauth.SubjectFromContext(r.Context())
+	return subject
+}
+
+// keyScope возвращает ограничения API-ключа, если запрос выполнен по нему, или nil для запросов с JWT.
+func keyScope(r *http.Request) *dto.APIKeyScope {
+	scope, ok := jwtauth.ScopeFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return &dto.APIKeyScope{Bots: scope.Bots, Actions: scope.Actions}
+}
+
 func (s *Server) GetBots(w http.ResponseWriter, r *http.Request) {
 	bs, err := s.app.Queries.GetUserBots.Handle(r.Context(), request.GetUserBotsQuery{
-		UserID: 1,
+		Author: accountID(r),
+		Scope:  keyScope(r),
 	})
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
@@ -50,10 +71,12 @@ func (s *Server) CreateBot(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = s.app.Commands.CreateBot.Handle(r.Context(), request.CreateBotCommand{
-		BotID:  req.Id,
-		Token:  req.Token,
-		Author: 1,
-		Script: script,
+		BotID:      req.Id,
+		Token:      req.Token,
+		Author:     accountID(r),
+		Scope:      keyScope(r),
+		ChatPolicy: chatPolicyToApp(req.ChatPolicy),
+		Script:     script,
 	})
 
 	var iiErr bots.InvalidInputError
@@ -68,6 +91,16 @@ func (s *Server) CreateBot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+
+	if errors.Is(err, port.ErrBotIDTaken) {
+		renderPlainError(w, r, err, http.StatusConflict)
+		return
+	}
+
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -78,11 +111,19 @@ func (s *Server) CreateBot(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) DeleteBot(w http.ResponseWriter, r *http.Request, id string) {
-	err := s.app.Commands.DeleteBot.Handle(r.Context(), request.DeleteBotCommand{BotID: id})
+	err := s.app.Commands.DeleteBot.Handle(r.Context(), request.DeleteBotCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -91,35 +132,115 @@ func (s *Server) DeleteBot(w http.ResponseWriter, r *http.Request, id string) {
 }
 
 func (s *Server) GetAnswers(w http.ResponseWriter, r *http.Request, id string) {
-	bot, err := s.app.Queries.GetBot.Handle(r.Context(), request.GetBotQuery{ID: id})
+	table := newCsvAnswersWriter(w)
+	_, err := s.app.Queries.StreamThreads.Handle(r.Context(), request.StreamThreadsQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		Header:    table.WriteHeader,
+		Row:       table.WriteRow,
+	})
+	if err == nil {
+		err = table.Flush()
+	}
+	if err != nil && table.Started() {
+		// Статус и часть таблицы уже отправлены: соединение обрывается, чтобы клиент не принял
+		// обрезанную таблицу за полную.
+		panic(http.ErrAbortHandler)
+	}
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err != nil {
-		renderPlainError(w, r, err, http.StatusInternalServerError)
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
 		return
 	}
-
-	threads, err := s.app.Queries.GetThreads.Handle(r.Context(), request.GetThreadsQuery{BotID: id})
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
 	}
+}
 
-	err = renderCsvAnswers(w, bot.Script.Nodes, threads)
+func (s *Server) GetBotThreads(w http.ResponseWriter, r *http.Request, id string, params GetBotThreadsParams) {
+	q := request.GetBotThreadsQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		Completed: params.Completed,
+	}
+	if params.Entry != nil {
+		q.EntryKey = *params.Entry
+	}
+	if params.StartedAfter != nil {
+		q.StartedAfter = *params.StartedAfter
+	}
+	if params.StartedBefore != nil {
+		q.StartedBefore = *params.StartedBefore
+	}
+	if params.Contains != nil {
+		q.Contains = *params.Contains
+	}
+	if params.Sort != nil {
+		switch *params.Sort {
+		case GetBotThreadsParamsSortStartedAt:
+			q.Ascending = true
+		case GetBotThreadsParamsSortMinusStartedAt:
+		default:
+			renderInvalidInputError(w, r, bots.NewInvalidInputError(
+				"threads-invalid-sort",
+				fmt.Sprintf("expected sort one of ['startedAt', '-startedAt'], got '%s'", *params.Sort),
+				"field", "sort",
+			), http.StatusBadRequest)
+			return
+		}
+	}
+	if params.Cursor != nil {
+		q.Cursor = *params.Cursor
+	}
+	if params.Limit != nil {
+		q.Limit = *params.Limit
+	}
+	page, err := s.app.Queries.GetBotThreads.Handle(r.Context(), q)
+	var iiErr bots.InvalidInputError
+	if errors.As(err, &iiErr) {
+		renderInvalidInputError(w, r, iiErr, http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, port.ErrBotNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
 	}
+
+	render.JSON(w, r, threadsPageFromApp(page))
 }
 
 func (s *Server) StartBot(w http.ResponseWriter, r *http.Request, id string) {
-	err := s.app.Commands.Start.Handle(r.Context(), request.StartCommand{BotID: id})
+	err := s.app.Commands.Start.Handle(r.Context(), request.StartCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, port.ErrInstanceChangeTimeout) {
+		renderPlainError(w, r, err, http.StatusGatewayTimeout)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -128,15 +249,27 @@ func (s *Server) StartBot(w http.ResponseWriter, r *http.Request, id string) {
 }
 
 func (s *Server) StopBot(w http.ResponseWriter, r *http.Request, id string) {
-	err := s.app.Commands.Stop.Handle(r.Context(), request.StopCommand{BotID: id})
+	err := s.app.Commands.Stop.Handle(r.Context(), request.StopCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if errors.Is(err, port.ErrRunningInstanceNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, port.ErrInstanceChangeTimeout) {
+		renderPlainError(w, r, err, http.StatusGatewayTimeout)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -145,11 +278,19 @@ func (s *Server) StopBot(w http.ResponseWriter, r *http.Request, id string) {
 }
 
 func (s *Server) DisableBot(w http.ResponseWriter, r *http.Request, botID string) {
-	err := s.app.Commands.DisableBot.Handle(r.Context(), request.DisableBotCommand{BotID: botID})
+	err := s.app.Commands.DisableBot.Handle(r.Context(), request.DisableBotCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     botID,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -158,11 +299,19 @@ func (s *Server) DisableBot(w http.ResponseWriter, r *http.Request, botID string
 }
 
 func (s *Server) EnableBot(w http.ResponseWriter, r *http.Request, botID string) {
-	err := s.app.Commands.EnableBot.Handle(r.Context(), request.EnableBotCommand{BotID: botID})
+	err := s.app.Commands.EnableBot.Handle(r.Context(), request.EnableBotCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     botID,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -171,11 +320,19 @@ func (s *Server) EnableBot(w http.ResponseWriter, r *http.Request, botID string)
 }
 
 func (s *Server) GetBot(w http.ResponseWriter, r *http.Request, id string) {
-	bot, err := s.app.Queries.GetBot.Handle(r.Context(), request.GetBotQuery{ID: id})
+	bot, err := s.app.Queries.GetBot.Handle(r.Context(), request.GetBotQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		ID:        id,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -185,17 +342,96 @@ func (s *Server) GetBot(w http.ResponseWriter, r *http.Request, id string) {
 }
 
 func (s *Server) GetStatus(w http.ResponseWriter, r *http.Request, id string) {
-	status, err := s.app.Queries.GetStatus.Handle(r.Context(), request.GetStatusQuery{BotID: id})
+	status, err := s.app.Queries.GetStatus.Handle(r.Context(), request.GetStatusQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
+	if errors.Is(err, port.ErrBotNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, botStatusFromApp(status))
+}
+
+func (s *Server) GetParticipantStats(w http.ResponseWriter, r *http.Request, id string) {
+	stats, err := s.app.Queries.GetParticipantStats.Handle(r.Context(), request.GetParticipantStatsQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
 	if errors.Is(err, port.ErrBotNotFound) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, participantStatsFromApp(stats))
+}
+
+func (s *Server) GetParticipantData(w http.ResponseWriter, r *http.Request, id string, userId int64) {
+	data, err := s.app.Queries.GetParticipantData.Handle(r.Context(), request.GetParticipantDataQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		UserID:    userId,
+	})
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrParticipantNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchParticipantDataFromApp(data))
+}
+
+func (s *Server) EraseParticipantData(
+	w http.ResponseWriter, r *http.Request, id string, userId int64, params EraseParticipantDataParams,
+) {
+	err := s.app.Commands.EraseParticipantData.Handle(r.Context(), request.EraseParticipantDataCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		UserID:    userId,
+		Anonymize: params.Anonymize != nil && *params.Anonymize,
+	})
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrParticipantNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	render.JSON(w, r, Status(status))
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (s *Server) Mailing(w http.ResponseWriter, r *http.Request, botID string) {
@@ -206,9 +442,11 @@ func (s *Server) Mailing(w http.ResponseWriter, r *http.Request, botID string) {
 	}
 
 	err := s.app.Commands.Mailing.Handle(r.Context(), request.MailingCommand{
-		BotID:    botID,
-		EntryKey: req.EntryKey,
-		Users:    req.Users,
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     botID,
+		EntryKey:  req.EntryKey,
+		Users:     req.Users,
 	})
 	var mErr *bots.MultiError
 	if errors.As(err, &mErr) {
@@ -219,6 +457,10 @@ func (s *Server) Mailing(w http.ResponseWriter, r *http.Request, botID string) {
 		renderPlainError(w, r, err, http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		renderPlainError(w, r, err, http.StatusInternalServerError)
 		return
@@ -227,65 +469,71 @@ func (s *Server) Mailing(w http.ResponseWriter, r *http.Request, botID string) {
 
 const offset = 3
 
-func renderCsvAnswers(w http.ResponseWriter, nodes []dto.Node, threads []dto.Thread) error {
-	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+const answerThreadIDHeadName = "#"
+const answerTimestampHeadName = "Отметка времени"
+const answerUsernameHeadName = "Никнейм"
+
+// csvAnswersWriter пишет таблицу ответов в ответ на HTTP-запрос построчно по мере чтения тредов.
+type csvAnswersWriter struct {
+	w            http.ResponseWriter
+	writer       *csv.Writer
+	stateToIndex map[int]int
+}
 
-	utf8bom := []byte{0xEF, 0xBB, 0xBF}
-	_, _ = w.Write(utf8bom)
+func newCsvAnswersWriter(w http.ResponseWriter) *csvAnswersWriter {
+	return &csvAnswersWriter{w: w}
+}
 
-	writer := csv.NewWriter(w)
+// Started сообщает, отправлены ли уже заголовки ответа.
+func (t *csvAnswersWriter) Started() bool {
+	return t.writer != nil
+}
 
-	stateToIndex := makeMapStateToIndex(threads)
-	thead := makeAnswersTHead(nodes, stateToIndex)
-	tbody := makeAnswersTBody(threads, stateToIndex)
+func (t *csvAnswersWriter) WriteHeader(columns []dto.AnswerColumn) error {
+	t.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
 
-	if err := writer.Write(thead); err != nil {
-		return fmt.Errorf("failed to write CSV answers table: %w", err)
+	utf8bom := []byte{0xEF, 0xBB, 0xBF}
+	_, _ = t.w.Write(utf8bom)
+
+	t.writer = csv.NewWriter(t.w)
+	t.stateToIndex = make(map[int]int, len(columns))
+	for idx, column := range columns {
+		t.stateToIndex[column.State] = idx
 	}
-	if err := writer.WriteAll(tbody); err != nil {
+
+	if err := t.writer.Write(makeAnswersTHead(columns)); err != nil {
 		return fmt.Errorf("failed to write CSV answers table: %w", err)
 	}
-
 	return nil
 }
 
-func makeMapStateToIndex(threads []dto.Thread) map[int]int {
-	states := make(map[int]bool)
-	for _, thread := range threads {
-		for state := range thread.Answers {
-			states[state] = true
-		}
+func (t *csvAnswersWriter) WriteRow(thread dto.Thread) error {
+	if err := t.writer.Write(makeAnswersTRow(thread, t.stateToIndex)); err != nil {
+		return fmt.Errorf("failed to write CSV answers table: %w", err)
 	}
+	return nil
+}
 
-	sortedStates := make([]int, 0)
-	for state := range states {
-		sortedStates = salad.InsertSorted(sortedStates, state, func(x, y int) bool { return x < y })
+func (t *csvAnswersWriter) Flush() error {
+	if t.writer == nil {
+		return nil
 	}
-
-	m := make(map[int]int, len(sortedStates))
-	for idx, state := range sortedStates {
-		m[state] = idx
+	t.writer.Flush()
+	if err := t.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV answers table: %w", err)
 	}
-
-	return m
+	return nil
 }
 
-const answerThreadIDHeadName = "#"
-const answerTimestampHeadName = "Отметка времени"
-const answerUsernameHeadName = "Никнейм"
-
-func makeAnswersTHead(nodes []dto.Node, stateToIndex map[int]int) []string {
-	head := make([]string, len(stateToIndex)+offset)
+func makeAnswersTHead(columns []dto.AnswerColumn) []string {
+	head := make([]string, len(columns)+offset)
 
 	head[0] = answerThreadIDHeadName
 	head[1] = answerTimestampHeadName
 	head[2] = answerUsernameHeadName
 
-	for _, node := range nodes {
-		idx, ok := stateToIndex[node.State]
-		if ok {
-			head[idx+offset] = node.Title
-		}
+	for idx, column := range columns {
+		head[idx+offset] = column.Title
 	}
 
 	return head
@@ -308,10 +556,481 @@ func makeAnswersTRow(thread dto.Thread, stateToIndex map[int]int) []string {
 	return row
 }
 
-func makeAnswersTBody(threads []dto.Thread, stateToIndex map[int]int) [][]string {
-	body := make([][]string, len(threads))
-	for i, thread := range threads {
-		body[i] = makeAnswersTRow(thread, stateToIndex)
+func (s *Server) GetWebhooks(w http.ResponseWriter, r *http.Request, id string) {
+	ws, err := s.app.Queries.GetWebhooks.Handle(r.Context(), request.GetWebhooksQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
+	if errors.Is(err, port.ErrBotNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchWebhooksFromApp(ws))
+}
+
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request, id string) {
+	req := PutWebhook{}
+	if err := render.Decode(r, &req); err != nil {
+		renderPlainError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	webhookID := uuid.Generate()
+	err := s.app.Commands.CreateWebhook.Handle(r.Context(), request.CreateWebhookCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		WebhookID: webhookID,
+		URL:       req.Url,
+		Secret:    req.Secret,
+		Events:    req.Events,
+	})
+	if !renderWebhookCommandError(w, r, err) {
+		return
+	}
+
+	webhook, err := s.app.Queries.GetWebhook.Handle(r.Context(), request.GetWebhookQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		WebhookID: webhookID,
+	})
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/bots/%s/webhooks/%s", id, webhookID))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, webhookFromApp(webhook))
+}
+
+func (s *Server) GetWebhook(w http.ResponseWriter, r *http.Request, id string, webhookId string) {
+	webhook, err := s.app.Queries.GetWebhook.Handle(r.Context(), request.GetWebhookQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		WebhookID: webhookId,
+	})
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrWebhookNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, webhookFromApp(webhook))
+}
+
+func (s *Server) UpdateWebhook(w http.ResponseWriter, r *http.Request, id string, webhookId string) {
+	req := PutWebhook{}
+	if err := render.Decode(r, &req); err != nil {
+		renderPlainError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	err := s.app.Commands.UpdateWebhook.Handle(r.Context(), request.UpdateWebhookCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		WebhookID: webhookId,
+		URL:       req.Url,
+		Secret:    req.Secret,
+		Events:    req.Events,
+	})
+	if !renderWebhookCommandError(w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request, id string, webhookId string) {
+	err := s.app.Commands.DeleteWebhook.Handle(r.Context(), request.DeleteWebhookCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		WebhookID: webhookId,
+	})
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrWebhookNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) GetWebhookDeliveries(
+	w http.ResponseWriter,
+	r *http.Request,
+	id string,
+	webhookId string,
+	params GetWebhookDeliveriesParams,
+) {
+	q := request.GetWebhookDeliveriesQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+		WebhookID: webhookId,
+	}
+	if params.Limit != nil {
+		q.Limit = *params.Limit
+	}
+	ds, err := s.app.Queries.GetWebhookDeliveries.Handle(r.Context(), q)
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrWebhookNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchWebhookDeliveriesFromApp(ds))
+}
+
+func (s *Server) GetAuditLog(w http.ResponseWriter, r *http.Request, id string, params GetAuditLogParams) {
+	q := request.GetAuditLogQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	}
+	if params.Action != nil {
+		q.Action = *params.Action
+	}
+	if params.Before != nil {
+		q.Before = *params.Before
+	}
+	if params.Limit != nil {
+		q.Limit = *params.Limit
+	}
+	es, err := s.app.Queries.GetAuditLog.Handle(r.Context(), q)
+	if errors.Is(err, port.ErrBotNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchAuditEntriesFromApp(es))
+}
+
+func (s *Server) GetCollaborators(w http.ResponseWriter, r *http.Request, id string) {
+	cs, err := s.app.Queries.GetCollaborators.Handle(r.Context(), request.GetCollaboratorsQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
+	if errors.Is(err, port.ErrBotNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchCollaboratorsFromApp(cs))
+}
+
+func (s *Server) InviteCollaborator(w http.ResponseWriter, r *http.Request, id string) {
+	req := PostCollaborator{}
+	if err := render.Decode(r, &req); err != nil {
+		renderPlainError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	err := s.app.Commands.InviteCollaborator.Handle(r.Context(), request.InviteCollaboratorCommand{
+		AccountID:      accountID(r),
+		Scope:          keyScope(r),
+		BotID:          id,
+		CollaboratorID: req.AccountId,
+		Role:           string(req.Role),
+	})
+	if errors.Is(err, port.ErrCollaboratorAlreadyExists) {
+		renderPlainError(w, r, err, http.StatusConflict)
+		return
+	}
+	if !renderCollaboratorCommandError(w, r, err) {
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/bots/%s/collaborators/%s", id, req.AccountId))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) UpdateCollaborator(w http.ResponseWriter, r *http.Request, id string, accountId string) {
+	req := PutCollaborator{}
+	if err := render.Decode(r, &req); err != nil {
+		renderPlainError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	err := s.app.Commands.UpdateCollaborator.Handle(r.Context(), request.UpdateCollaboratorCommand{
+		AccountID:      accountID(r),
+		Scope:          keyScope(r),
+		BotID:          id,
+		CollaboratorID: accountId,
+		Role:           string(req.Role),
+	})
+	if !renderCollaboratorCommandError(w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) RemoveCollaborator(w http.ResponseWriter, r *http.Request, id string, accountId string) {
+	err := s.app.Commands.RemoveCollaborator.Handle(r.Context(), request.RemoveCollaboratorCommand{
+		AccountID:      accountID(r),
+		Scope:          keyScope(r),
+		BotID:          id,
+		CollaboratorID: accountId,
+	})
+	if !renderCollaboratorCommandError(w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderCollaboratorCommandError отображает ошибку команды над соавтором бота и сообщает, можно
+// ли продолжать обработку запроса.
+func renderCollaboratorCommandError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var iiErr bots.InvalidInputError
+	if errors.As(err, &iiErr) {
+		renderInvalidInputError(w, r, iiErr, http.StatusBadRequest)
+		return false
+	}
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrCollaboratorNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return false
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return false
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// renderWebhookCommandError отображает ошибку команды над Webhook и сообщает, можно ли продолжать
+// обработку запроса.
+func renderWebhookCommandError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var iiErr bots.InvalidInputError
+	if errors.As(err, &iiErr) {
+		renderInvalidInputError(w, r, iiErr, http.StatusBadRequest)
+		return false
+	}
+	if errors.Is(err, port.ErrBotNotFound) || errors.Is(err, port.ErrWebhookNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return false
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return false
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// apiKeySecretBytes есть количество случайных байт в секретной части API-ключа.
+const apiKeySecretBytes = 32
+
+func (s *Server) GetApiKeys(w http.ResponseWriter, r *http.Request) {
+	ks, err := s.app.Queries.GetAPIKeys.Handle(r.Context(), request.GetAPIKeysQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+	})
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchApiKeysFromApp(ks))
+}
+
+func (s *Server) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	req := PostApiKey{}
+	if err := render.Decode(r, &req); err != nil {
+		renderPlainError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	b := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	secret := hex.EncodeToString(b)
+
+	keyID := uuid.Generate()
+	err := s.app.Commands.CreateAPIKey.Handle(r.Context(), request.CreateAPIKeyCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		KeyID:     keyID,
+		Secret:    secret,
+		Name:      req.Name,
+		KeyScope:  apiKeyScopeToApp(req),
+		ExpiresAt: req.ExpiresAt,
+	})
+	if !renderAPIKeyCommandError(w, r, err) {
+		return
+	}
+
+	ks, err := s.app.Queries.GetAPIKeys.Handle(r.Context(), request.GetAPIKeysQuery{AccountID: accountID(r)})
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	i := slices.IndexFunc(ks, func(k dto.APIKey) bool { return k.ID == keyID })
+	if i < 0 {
+		renderPlainError(w, r, port.ErrAPIKeyNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, NewApiKey{
+		Key:    bots.FormatAPIKey(bots.APIKeyID(keyID), secret),
+		ApiKey: apiKeyFromApp(ks[i]),
+	})
+}
+
+func (s *Server) RevokeApiKey(w http.ResponseWriter, r *http.Request, keyId string) {
+	err := s.app.Commands.RevokeAPIKey.Handle(r.Context(), request.RevokeAPIKeyCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		KeyID:     keyId,
+	})
+	if !renderAPIKeyCommandError(w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderAPIKeyCommandError отображает ошибку команды над API-ключом и сообщает, можно ли
+// продолжать обработку запроса.
+func renderAPIKeyCommandError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var iiErr bots.InvalidInputError
+	if errors.As(err, &iiErr) {
+		renderInvalidInputError(w, r, iiErr, http.StatusBadRequest)
+		return false
+	}
+	if errors.Is(err, port.ErrAPIKeyNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return false
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return false
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+func (s *Server) GetDeletedBots(w http.ResponseWriter, r *http.Request) {
+	bs, err := s.app.Queries.GetDeletedBots.Handle(r.Context(), request.GetDeletedBotsQuery{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+	})
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, batchDeletedBotsFromApp(bs))
+}
+
+func (s *Server) RestoreBot(w http.ResponseWriter, r *http.Request, id string) {
+	err := s.app.Commands.RestoreBot.Handle(r.Context(), request.RestoreBotCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
+	if !renderDeletedBotCommandError(w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) PurgeBot(w http.ResponseWriter, r *http.Request, id string) {
+	err := s.app.Commands.PurgeBot.Handle(r.Context(), request.PurgeBotCommand{
+		AccountID: accountID(r),
+		Scope:     keyScope(r),
+		BotID:     id,
+	})
+	if !renderDeletedBotCommandError(w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderDeletedBotCommandError отображает ошибку команды над удалённым ботом и сообщает, можно ли
+// продолжать обработку запроса.
+func renderDeletedBotCommandError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var iiErr bots.InvalidInputError
+	if errors.As(err, &iiErr) {
+		renderInvalidInputError(w, r, iiErr, http.StatusBadRequest)
+		return false
+	}
+	if errors.Is(err, port.ErrBotNotFound) {
+		renderPlainError(w, r, err, http.StatusNotFound)
+		return false
+	}
+	if errors.Is(err, bots.ErrBotAccessDenied) {
+		renderPlainError(w, r, err, http.StatusForbidden)
+		return false
+	}
+	if err != nil {
+		renderPlainError(w, r, err, http.StatusInternalServerError)
+		return false
 	}
-	return body
+	return true
 }
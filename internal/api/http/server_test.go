@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+)
+
+// failingStream выгружает больше тредов, чем помещается в буфер ответа, и завершается ошибкой, как при
+// обрыве соединения с базой данных.
+type failingStream struct{}
+
+func (failingStream) Handle(
+	_ context.Context, q request.StreamThreadsQuery,
+) (response.StreamThreadsResponse, error) {
+	if err := q.Header([]dto.AnswerColumn{{State: 1, Title: "Name"}}); err != nil {
+		return response.StreamThreadsResponse{}, err
+	}
+	const threads = 1000
+	for range threads {
+		if err := q.Row(dto.Thread{ID: "thread", Key: "start", State: 1}); err != nil {
+			return response.StreamThreadsResponse{}, err
+		}
+	}
+	return response.StreamThreadsResponse{Threads: threads}, errors.New("connection reset")
+}
+
+func TestServer_GetAnswers_AbortsOnStreamError(t *testing.T) {
+	s := NewHTTPServer(&app.Application{Queries: app.Queries{StreamThreads: failingStream{}}})
+	srv := httptest.NewServer(middleware.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.GetAnswers(w, r, "bot")
+	})))
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Таблица не завершается корректно: клиент видит обрыв, а не конец файла
+	_, err = io.ReadAll(resp.Body)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
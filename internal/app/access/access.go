@@ -0,0 +1,124 @@
+// Package access проверяет права пользователя и ограничения API-ключа при выполнении команд и запросов.
+package access
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// AccessibleBot возвращает бота, если роль пользователя accountID в нём и ограничения API-ключа
+// scope, если запрос выполнен по ключу, разрешают действие perm; иначе возвращает
+// port.ErrBotNotFound или bots.ErrBotAccessDenied.
+func AccessibleBot(
+	ctx context.Context,
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	botID string,
+	accountID string,
+	scope *dto.APIKeyScope,
+	perm bots.Permission,
+) (*bots.Bot, error) {
+	bot, err := bp.Bot(ctx, bots.BotID(botID))
+	if err != nil {
+		return nil, err
+	}
+	role, err := RoleOf(ctx, cp, bot, bots.AccountID(accountID))
+	if err != nil {
+		return nil, err
+	}
+	if err = role.Check(perm); err != nil {
+		return nil, err
+	}
+	if err = CheckScope(scope, bot.ID(), perm); err != nil {
+		return nil, err
+	}
+	return bot, nil
+}
+
+// AccessibleDeletedBot аналогична AccessibleBot, но ищет бота среди удалённых.
+func AccessibleDeletedBot(
+	ctx context.Context,
+	dr port.DeletedBotRepository,
+	cp port.CollaboratorProvider,
+	botID string,
+	accountID string,
+	scope *dto.APIKeyScope,
+	perm bots.Permission,
+) (*bots.Bot, error) {
+	deleted, err := dr.DeletedBot(ctx, bots.BotID(botID))
+	if err != nil {
+		return nil, err
+	}
+	role, err := RoleOf(ctx, cp, deleted.Bot, bots.AccountID(accountID))
+	if err != nil {
+		return nil, err
+	}
+	if err = role.Check(perm); err != nil {
+		return nil, err
+	}
+	if err = CheckScope(scope, deleted.Bot.ID(), perm); err != nil {
+		return nil, err
+	}
+	return deleted.Bot, nil
+}
+
+// CheckScope проверяет, что API-ключ разрешает действие perm над ботом botID. Запросы с JWT
+// (scope равен nil) ограничены только ролью пользователя.
+func CheckScope(scope *dto.APIKeyScope, botID bots.BotID, perm bots.Permission) error {
+	if scope == nil {
+		return nil
+	}
+	s, err := dto.APIKeyScopeFromDTO(*scope)
+	if err != nil {
+		return err
+	}
+	return s.Check(botID, perm)
+}
+
+// RoleOf возвращает роль пользователя account в боте.
+func RoleOf(
+	ctx context.Context,
+	cp port.CollaboratorProvider,
+	bot *bots.Bot,
+	account bots.AccountID,
+) (bots.Role, error) {
+	if bot.Author() == account {
+		return bots.Owner, nil
+	}
+	c, err := cp.Collaborator(ctx, bot.ID(), account)
+	if errors.Is(err, port.ErrCollaboratorNotFound) {
+		return bots.Role{}, nil
+	} else if err != nil {
+		return bots.Role{}, err
+	}
+	return bot.RoleOf(account, c), nil
+}
+
+// Allows сообщает, разрешает ли роль пользователя account и API-ключ scope действие perm над ботом.
+func Allows(
+	ctx context.Context,
+	cp port.CollaboratorProvider,
+	bot *bots.Bot,
+	account bots.AccountID,
+	scope *dto.APIKeyScope,
+	perm bots.Permission,
+) (bool, error) {
+	role, err := RoleOf(ctx, cp, bot, account)
+	if err != nil {
+		return false, err
+	}
+	return role.Check(perm) == nil && CheckScope(scope, bot.ID(), perm) == nil, nil
+}
+
+// RequireJWT запрещает управлять API-ключами по API-ключу: иначе утёкший ключ позволил бы выпустить
+// себе новый, не ограниченный прежним сроком действия.
+func RequireJWT(scope *dto.APIKeyScope) error {
+	if scope != nil {
+		return bots.ErrOutOfAPIKeyScope
+	}
+	return nil
+}
@@ -6,24 +6,51 @@ import (
 )
 
 type Commands struct {
-	CreateBot    command.CreateBotHandler
-	DeleteBot    command.DeleteBotHandler
-	DisableBot   command.DisableBotHandler
-	EnableBot    command.EnableBotHandler
-	Entry        command.EntryHandler
-	Mailing      command.MailingHandler
-	Process      command.ProcessHandler
-	Start        command.StartHandler
-	StartEnabled command.StartEnabledHandler
-	Stop         command.StopHandler
-	UpdateBot    command.UpdateBotHandler
+	CreateAPIKey         command.CreateAPIKeyHandler
+	CreateBot            command.CreateBotHandler
+	CreateWebhook        command.CreateWebhookHandler
+	DeleteBot            command.DeleteBotHandler
+	DeleteWebhook        command.DeleteWebhookHandler
+	DeliverMessage       command.DeliverMessageHandler
+	DeliverWebhook       command.DeliverWebhookHandler
+	DisableBot           command.DisableBotHandler
+	EnableBot            command.EnableBotHandler
+	EnqueueWebhookEvent  command.EnqueueWebhookEventHandler
+	Entry                command.EntryHandler
+	EraseParticipantData command.EraseParticipantDataHandler
+	InviteCollaborator   command.InviteCollaboratorHandler
+	Mailing              command.MailingHandler
+	Process              command.ProcessHandler
+	PurgeBot             command.PurgeBotHandler
+	PurgeDeletedBots     command.PurgeDeletedBotsHandler
+	RemoveCollaborator   command.RemoveCollaboratorHandler
+	RestoreBot           command.RestoreBotHandler
+	RevokeAPIKey         command.RevokeAPIKeyHandler
+	Start                command.StartHandler
+	StartEnabled         command.StartEnabledHandler
+	Stop                 command.StopHandler
+	TouchAPIKey          command.TouchAPIKeyHandler
+	UpdateBot            command.UpdateBotHandler
+	UpdateCollaborator   command.UpdateCollaboratorHandler
+	UpdateWebhook        command.UpdateWebhookHandler
 }
 
 type Queries struct {
-	GetBot      query.GetBotHandler
-	GetStatus   query.GetStatusHandler
-	GetThreads  query.GetThreadsHandler
-	GetUserBots query.GetUserBotsHandler
+	AuthenticateAPIKey   query.AuthenticateAPIKeyHandler
+	GetAPIKeys           query.GetAPIKeysHandler
+	GetAuditLog          query.GetAuditLogHandler
+	GetBot               query.GetBotHandler
+	GetBotThreads        query.GetBotThreadsHandler
+	GetCollaborators     query.GetCollaboratorsHandler
+	GetDeletedBots       query.GetDeletedBotsHandler
+	GetParticipantData   query.GetParticipantDataHandler
+	GetParticipantStats  query.GetParticipantStatsHandler
+	GetStatus            query.GetStatusHandler
+	GetUserBots          query.GetUserBotsHandler
+	GetWebhook           query.GetWebhookHandler
+	GetWebhookDeliveries query.GetWebhookDeliveriesHandler
+	GetWebhooks          query.GetWebhooksHandler
+	StreamThreads        query.StreamThreadsHandler
 }
 
 type Application struct {
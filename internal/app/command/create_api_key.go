@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type CreateAPIKeyHandler decorator.CommandHandler[request.CreateAPIKeyCommand]
+
+type createAPIKeyHandler struct {
+	kr port.APIKeyRepository
+}
+
+func (h createAPIKeyHandler) Handle(ctx context.Context, cmd request.CreateAPIKeyCommand) error {
+	if err := access.RequireJWT(cmd.Scope); err != nil {
+		return err
+	}
+
+	scope, err := dto.APIKeyScopeFromDTO(cmd.KeyScope)
+	if err != nil {
+		return err
+	}
+	k, err := bots.NewAPIKey(
+		bots.APIKeyID(cmd.KeyID), bots.AccountID(cmd.AccountID), cmd.Name, cmd.Secret, scope, cmd.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+	return h.kr.CreateAPIKey(ctx, k)
+}
+
+func NewCreateAPIKeyHandler(
+	kr port.APIKeyRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) CreateAPIKeyHandler {
+	return decorator.ApplyCommandDecorators(createAPIKeyHandler{kr}, l, mc, ar)
+}
@@ -2,10 +2,14 @@ package command
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
 )
 
@@ -13,6 +17,10 @@ type CreateBotHandler decorator.CommandHandler[request.CreateBotCommand]
 
 type createBotHandler struct {
 	br port.BotRepository
+	cp port.CollaboratorProvider
+	cc port.ClientCache
+	tv port.TokenVerifier
+	im port.InstanceManager
 }
 
 func (h createBotHandler) Handle(ctx context.Context, cmd request.CreateBotCommand) error {
@@ -20,13 +28,99 @@ func (h createBotHandler) Handle(ctx context.Context, cmd request.CreateBotComma
 	if err != nil {
 		return err
 	}
-	return h.br.UpsertBot(ctx, bot)
+	return upsertBotInvalidatingToken(ctx, h.br, h.cp, h.cc, h.tv, h.im, bot, cmd.Scope)
 }
 
 func NewCreateBotHandler(
 	bm port.BotRepository,
+	cp port.CollaboratorProvider,
+	cc port.ClientCache,
+	tv port.TokenVerifier,
+	im port.InstanceManager,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) CreateBotHandler {
-	return decorator.ApplyCommandDecorators(createBotHandler{bm}, l, mc)
+	return decorator.ApplyCommandDecorators(createBotHandler{bm, cp, cc, tv, im}, l, mc, ar)
+}
+
+// upsertBotInvalidatingToken создаёт или заменяет бота от имени автора команды. Существующего бота
+// может заменить только его редактор или владелец, а по API-ключу - только если ключ разрешает
+// изменение бота. Перед сохранением токен проверяется (см. verifyBotToken); если токен
+// существующего бота изменился, клиент Telegram для прежнего токена сбрасывается, а запущенный
+// экземпляр перезагружается с новым.
+func upsertBotInvalidatingToken(
+	ctx context.Context,
+	br port.BotRepository,
+	cp port.CollaboratorProvider,
+	cc port.ClientCache,
+	tv port.TokenVerifier,
+	im port.InstanceManager,
+	bot *bots.Bot,
+	scope *dto.APIKeyScope,
+) error {
+	if err := access.CheckScope(scope, bot.ID(), bots.PermEdit); err != nil {
+		return err
+	}
+
+	prev, err := br.Bot(ctx, bot.ID())
+	if err != nil && !errors.Is(err, port.ErrBotNotFound) {
+		return err
+	}
+	if prev != nil {
+		role, err2 := access.RoleOf(ctx, cp, prev, bot.Author())
+		if err2 != nil {
+			return err2
+		}
+		if err2 = role.Check(bots.PermEdit); err2 != nil {
+			return err2
+		}
+		bot.Replaces(prev)
+	}
+
+	if err = verifyBotToken(ctx, br, tv, bot); err != nil {
+		return err
+	}
+
+	err = br.UpsertBot(ctx, bot)
+	if err != nil {
+		return err
+	}
+
+	if prev != nil && prev.Token() != bot.Token() {
+		cc.Invalidate(ctx, prev.Token())
+		return im.Reload(ctx, bot.ID(), bot.Token())
+	}
+	return nil
+}
+
+// verifyBotToken проверяет токен бота методом getMe и запоминает аккаунт Telegram, которому он
+// принадлежит. Неизменённый токен с известным аккаунтом повторно не проверяется. Токен аккаунта,
+// который уже использует другой бот, отклоняется: Telegram отдаёт обновления только одному получателю.
+func verifyBotToken(ctx context.Context, bp port.BotProvider, tv port.TokenVerifier, bot *bots.Bot) error {
+	if bot.Telegram().IsZero() {
+		account, err := tv.VerifyToken(ctx, bot.Token())
+		if errors.Is(err, port.ErrTokenRejected) {
+			return bots.NewInvalidInputError(
+				"bot-invalid-token", "telegram rejected bot token", "field", "token",
+			)
+		} else if err != nil {
+			return err
+		}
+		bot.SetTelegram(account)
+	}
+
+	other, err := bp.BotByTelegramID(ctx, bot.Telegram().ID())
+	if errors.Is(err, port.ErrBotNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if other.ID() != bot.ID() {
+		return bots.NewInvalidInputError(
+			"bot-token-conflict", "bot token is already used by another bot",
+			"field", "token", "username", bot.Telegram().Username(),
+		)
+	}
+	return nil
 }
@@ -0,0 +1,174 @@
+package command_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/command"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+type memBots map[bots.BotID]*bots.Bot
+
+func (m memBots) UpsertBot(_ context.Context, bot *bots.Bot) error {
+	m[bot.ID()] = bot
+	return nil
+}
+
+func (m memBots) DeleteBot(_ context.Context, id bots.BotID) error {
+	delete(m, id)
+	return nil
+}
+
+func (m memBots) Bot(_ context.Context, id bots.BotID) (*bots.Bot, error) {
+	if bot, ok := m[id]; ok {
+		return bot, nil
+	}
+	return nil, fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+}
+
+func (m memBots) UserBots(context.Context, bots.AccountID) ([]*bots.Bot, error) {
+	return nil, nil
+}
+
+func (m memBots) EnabledBots(context.Context) ([]*bots.Bot, error) {
+	return nil, nil
+}
+
+func (m memBots) BotByTelegramID(_ context.Context, telegramID int64) (*bots.Bot, error) {
+	for _, bot := range m {
+		if bot.Telegram().ID() == telegramID {
+			return bot, nil
+		}
+	}
+	return nil, port.ErrBotNotFound
+}
+
+type noCollaborators struct{}
+
+func (noCollaborators) Collaborator(context.Context, bots.BotID, bots.AccountID) (*bots.Collaborator, error) {
+	return nil, port.ErrCollaboratorNotFound
+}
+
+func (noCollaborators) BotCollaborators(context.Context, bots.BotID) ([]*bots.Collaborator, error) {
+	return nil, nil
+}
+
+type nopClientCache struct{}
+
+func (nopClientCache) Invalidate(context.Context, bots.Token) {}
+
+// memInstances запоминает токены, с которыми перезапускались боты.
+type memInstances map[bots.BotID]bots.Token
+
+func (m memInstances) Start(_ context.Context, id bots.BotID, token bots.Token) error {
+	m[id] = token
+	return nil
+}
+
+func (m memInstances) Stop(_ context.Context, id bots.BotID) error {
+	delete(m, id)
+	return nil
+}
+
+func (m memInstances) Reload(_ context.Context, id bots.BotID, token bots.Token) error {
+	if _, ok := m[id]; ok {
+		m[id] = token
+	}
+	return nil
+}
+
+type memAudit []decorator.AuditEntry
+
+func (m *memAudit) RecordAudit(_ context.Context, entry decorator.AuditEntry) error {
+	*m = append(*m, entry)
+	return nil
+}
+
+// fakeTelegram знает аккаунты ботов по токенам и считает запросы getMe.
+type fakeTelegram struct {
+	accounts map[bots.Token]bots.TelegramAccount
+	calls    int
+}
+
+func (f *fakeTelegram) VerifyToken(_ context.Context, token bots.Token) (bots.TelegramAccount, error) {
+	f.calls++
+	a, ok := f.accounts[token]
+	if !ok {
+		return bots.TelegramAccount{}, port.ErrTokenRejected
+	}
+	return a, nil
+}
+
+func createBotCommand(id string, token string) request.CreateBotCommand {
+	return request.CreateBotCommand{
+		BotID:  id,
+		Token:  token,
+		Author: "author",
+		Script: dto.Script{
+			Nodes: []dto.Node{
+				{State: 1, Title: "Greeting", Messages: []dto.Message{{Text: "Hello, world!"}}},
+			},
+			Entries: []dto.Entry{{Key: "start", Start: 1}},
+		},
+	}
+}
+
+func TestCreateBot_VerifiesToken(t *testing.T) {
+	ctx := context.Background()
+	repo := memBots{}
+	tg := &fakeTelegram{accounts: map[bots.Token]bots.TelegramAccount{
+		"1:a": bots.MustNewTelegramAccount(1, "first_bot"),
+		"1:b": bots.MustNewTelegramAccount(1, "first_bot"),
+	}}
+	audit := &memAudit{}
+	instances := memInstances{}
+	h := command.NewCreateBotHandler(
+		repo, noCollaborators{}, nopClientCache{}, tg, instances, audit, logs.DefaultLogger(), metrics.NoOp{},
+	)
+
+	var iiErr bots.InvalidInputError
+	err := h.Handle(ctx, createBotCommand("bot", "1:typo"))
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "bot-invalid-token", iiErr.Code)
+
+	require.NoError(t, h.Handle(ctx, createBotCommand("bot", "1:a")))
+	require.Equal(t, "first_bot", repo["bot"].Telegram().Username())
+
+	// Другой токен того же аккаунта Telegram занят ботом "bot"
+	err = h.Handle(ctx, createBotCommand("other", "1:b"))
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "bot-token-conflict", iiErr.Code)
+
+	// Замена бота со скрытым токеном не требует повторной проверки
+	calls := tg.calls
+	require.NoError(t, h.Handle(ctx, createBotCommand("bot", bots.Token("1:a").Redacted())))
+	require.Equal(t, calls, tg.calls)
+	require.Equal(t, bots.Token("1:a"), repo["bot"].Token())
+
+	// Новый токен того же аккаунта для того же бота допустим; запущенный бот перезапускается с ним
+	require.NoError(t, instances.Start(ctx, "bot", "1:a"))
+	require.NoError(t, h.Handle(ctx, createBotCommand("bot", "1:b")))
+	require.Equal(t, bots.Token("1:b"), instances["bot"])
+
+	// Все попытки, в том числе отклонённые, записаны в журнал аудита без токенов
+	require.Len(t, *audit, 5)
+	for _, e := range *audit {
+		require.Equal(t, "author", e.Actor)
+		require.Equal(t, "CreateBot", e.Action)
+		require.NotContains(t, string(e.Payload), "1:a")
+		require.NotContains(t, string(e.Payload), "1:b")
+	}
+	require.Equal(t, "other", (*audit)[2].BotID)
+	require.NotEmpty(t, (*audit)[2].Error)
+	require.Empty(t, (*audit)[4].Error)
+}
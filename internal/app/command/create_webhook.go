@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type CreateWebhookHandler decorator.CommandHandler[request.CreateWebhookCommand]
+
+type createWebhookHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	wr port.WebhookRepository
+}
+
+func (h createWebhookHandler) Handle(ctx context.Context, cmd request.CreateWebhookCommand) error {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit); err != nil {
+		return err
+	}
+	w, err := bots.NewWebhook(
+		bots.WebhookID(cmd.WebhookID), bots.BotID(cmd.BotID), cmd.URL, cmd.Secret, cmd.Events,
+	)
+	if err != nil {
+		return err
+	}
+	return h.wr.UpsertWebhook(ctx, w)
+}
+
+func NewCreateWebhookHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	wr port.WebhookRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) CreateWebhookHandler {
+	return decorator.ApplyCommandDecorators(createWebhookHandler{bp, cp, wr}, l, mc, ar)
+}
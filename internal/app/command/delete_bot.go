@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -14,16 +15,30 @@ type DeleteBotHandler decorator.CommandHandler[request.DeleteBotCommand]
 
 type deleteBotHandler struct {
 	br port.BotRepository
+	cp port.CollaboratorProvider
+	cc port.ClientCache
 }
 
 func (h deleteBotHandler) Handle(ctx context.Context, command request.DeleteBotCommand) error {
-	return h.br.DeleteBot(ctx, bots.BotID(command.BotID))
+	bot, err := access.AccessibleBot(ctx, h.br, h.cp, command.BotID, command.AccountID, command.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+	err = h.br.DeleteBot(ctx, bot.ID())
+	if err != nil {
+		return err
+	}
+	h.cc.Invalidate(ctx, bot.Token())
+	return nil
 }
 
 func NewDeleteBotHandler(
 	br port.BotRepository,
+	cp port.CollaboratorProvider,
+	cc port.ClientCache,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) DeleteBotHandler {
-	return decorator.ApplyCommandDecorators(deleteBotHandler{br}, l, mc)
+	return decorator.ApplyCommandDecorators(deleteBotHandler{br, cp, cc}, l, mc, ar)
 }
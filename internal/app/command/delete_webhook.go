@@ -0,0 +1,38 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type DeleteWebhookHandler decorator.CommandHandler[request.DeleteWebhookCommand]
+
+type deleteWebhookHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	wr port.WebhookRepository
+}
+
+func (h deleteWebhookHandler) Handle(ctx context.Context, cmd request.DeleteWebhookCommand) error {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit); err != nil {
+		return err
+	}
+	return h.wr.DeleteWebhook(ctx, bots.BotID(cmd.BotID), bots.WebhookID(cmd.WebhookID))
+}
+
+func NewDeleteWebhookHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	wr port.WebhookRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) DeleteWebhookHandler {
+	return decorator.ApplyCommandDecorators(deleteWebhookHandler{bp, cp, wr}, l, mc, ar)
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+const (
+	// maxDeliveryAttempts ограничивает количество попыток отправки одного сообщения.
+	maxDeliveryAttempts = 10
+	deliveryBackoffMin  = 5 * time.Second
+	deliveryBackoffMax  = time.Hour
+)
+
+type DeliverMessageHandler decorator.CommandHandler[request.DeliverMessageCommand]
+
+type deliverMessageHandler struct {
+	bp port.BotProvider
+	pr port.ParticipantRepository
+	or port.OutboxRepository
+	ms port.MessageSender
+}
+
+func (h deliverMessageHandler) Handle(ctx context.Context, cmd request.DeliverMessageCommand) error {
+	prtID := bots.NewChatParticipantID(
+		bots.ChatID(cmd.ChatID), bots.UserID(cmd.UserID), bots.BotID(cmd.BotID),
+	)
+
+	msg, err := botMessageFromCommand(cmd)
+	if err != nil {
+		// Сообщение не может быть отправлено ни при какой попытке
+		return errors.Join(err, h.or.FailOutboxMessage(ctx, cmd.OutboxID, err.Error()))
+	}
+
+	bot, err := h.bp.Bot(ctx, prtID.BotID())
+	if errors.Is(err, port.ErrBotNotFound) {
+		return errors.Join(err, h.or.FailOutboxMessage(ctx, cmd.OutboxID, err.Error()))
+	} else if err != nil {
+		return errors.Join(err, h.retry(ctx, cmd, err))
+	}
+
+	sendErr := h.ms.Send(ctx, bot.Token(), prtID.ChatID(), msg)
+	switch {
+	case sendErr == nil:
+		return h.or.DeleteOutboxMessage(ctx, cmd.OutboxID)
+	case errors.Is(sendErr, port.ErrUserBlockedBot):
+		// Остальные сообщения участнику также не будут доставлены
+		err = h.or.FailChatOutboxMessages(ctx, prtID, sendErr.Error())
+		return errors.Join(blockOnUserBlockedBot(ctx, h.pr, prtID, sendErr), err)
+	default:
+		return errors.Join(sendErr, h.retry(ctx, cmd, sendErr))
+	}
+}
+
+// retry откладывает следующую попытку отправки с экспоненциально растущей задержкой или
+// окончательно отмечает сообщение неотправленным, если попытки исчерпаны.
+func (h deliverMessageHandler) retry(ctx context.Context, cmd request.DeliverMessageCommand, cause error) error {
+	attempts := cmd.Attempts + 1
+	if attempts >= maxDeliveryAttempts {
+		return h.or.FailOutboxMessage(ctx, cmd.OutboxID, cause.Error())
+	}
+	return h.or.RetryOutboxMessage(ctx, cmd.OutboxID, time.Now().Add(deliveryBackoff(attempts)), cause.Error())
+}
+
+func deliveryBackoff(attempts int) time.Duration {
+	backoff := deliveryBackoffMin
+	for i := 1; i < attempts && backoff < deliveryBackoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, deliveryBackoffMax)
+}
+
+func botMessageFromCommand(cmd request.DeliverMessageCommand) (bots.BotMessage, error) {
+	msg, err := bots.NewMessage(cmd.Text)
+	if err != nil {
+		return bots.BotMessage{}, err
+	}
+	opts := make([]bots.Option, len(cmd.Options))
+	for i, s := range cmd.Options {
+		opts[i], err = bots.NewOption(s)
+		if err != nil {
+			return bots.BotMessage{}, err
+		}
+	}
+	return msg.PromoteToBotMessage(opts), nil
+}
+
+func NewDeliverMessageHandler(
+	bp port.BotProvider,
+	pr port.ParticipantRepository,
+	or port.OutboxRepository,
+	ms port.MessageSender,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) DeliverMessageHandler {
+	return decorator.ApplyCommandDecorators(deliverMessageHandler{bp, pr, or, ms}, l, mc, ar)
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// maxWebhookAttempts ограничивает количество попыток доставки одного события. При задержках
+// от deliveryBackoffMin до deliveryBackoffMax попытки растягиваются примерно на сутки.
+const maxWebhookAttempts = 15
+
+type DeliverWebhookHandler decorator.CommandHandler[request.DeliverWebhookCommand]
+
+type deliverWebhookHandler struct {
+	wr port.WebhookRepository
+	ws port.WebhookSender
+}
+
+func (h deliverWebhookHandler) Handle(ctx context.Context, cmd request.DeliverWebhookCommand) error {
+	w, err := h.wr.Webhook(ctx, bots.BotID(cmd.BotID), bots.WebhookID(cmd.WebhookID))
+	if errors.Is(err, port.ErrWebhookNotFound) {
+		// Webhook удалён вместе с журналом, отмечать доставку уже некуда
+		return nil
+	} else if err != nil {
+		return errors.Join(err, h.retry(ctx, cmd, 0, err))
+	}
+
+	code, sendErr := h.ws.Send(ctx, w.URL(), w.Secret(), cmd.Event, cmd.DeliveryID, cmd.Payload)
+	if sendErr == nil {
+		return h.wr.MarkWebhookDelivered(ctx, cmd.DeliveryID, code)
+	}
+	return errors.Join(sendErr, h.retry(ctx, cmd, code, sendErr))
+}
+
+// retry откладывает следующую попытку доставки с экспоненциально растущей задержкой или
+// окончательно отмечает доставку неудавшейся, если попытки исчерпаны.
+func (h deliverWebhookHandler) retry(
+	ctx context.Context, cmd request.DeliverWebhookCommand, code int, cause error,
+) error {
+	attempts := cmd.Attempts + 1
+	if attempts >= maxWebhookAttempts {
+		return h.wr.FailWebhookDelivery(ctx, cmd.DeliveryID, code, cause.Error())
+	}
+	at := time.Now().Add(deliveryBackoff(attempts))
+	return h.wr.RetryWebhookDelivery(ctx, cmd.DeliveryID, at, code, cause.Error())
+}
+
+func NewDeliverWebhookHandler(
+	wr port.WebhookRepository,
+	ws port.WebhookSender,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) DeliverWebhookHandler {
+	return decorator.ApplyCommandDecorators(deliverWebhookHandler{wr, ws}, l, mc, ar)
+}
@@ -3,7 +3,9 @@ package command
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -14,21 +16,30 @@ type DisableBotHandler decorator.CommandHandler[request.DisableBotCommand]
 
 type disableBotHandler struct {
 	br port.BotRepository
+	cp port.CollaboratorProvider
+	ep port.EventPublisher
 }
 
 func (h disableBotHandler) Handle(ctx context.Context, cmd request.DisableBotCommand) error {
-	bot, err := h.br.Bot(ctx, bots.BotID(cmd.BotID))
+	bot, err := access.AccessibleBot(ctx, h.br, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit)
 	if err != nil {
 		return err
 	}
 	bot.Disable()
-	return h.br.UpsertBot(ctx, bot)
+	err = h.br.UpsertBot(ctx, bot)
+	if err != nil {
+		return err
+	}
+	return h.ep.Publish(ctx, bots.BotDisabled{BotID: bot.ID(), DisabledAt: time.Now()})
 }
 
 func NewDisableBotHandler(
 	bm port.BotRepository,
+	cp port.CollaboratorProvider,
+	ep port.EventPublisher,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) DisableBotHandler {
-	return decorator.ApplyCommandDecorators(disableBotHandler{bm}, l, mc)
+	return decorator.ApplyCommandDecorators(disableBotHandler{bm, cp, ep}, l, mc, ar)
 }
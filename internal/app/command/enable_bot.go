@@ -3,7 +3,9 @@ package command
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -14,11 +16,13 @@ type EnableBotHandler decorator.CommandHandler[request.EnableBotCommand]
 
 type enableBotHandler struct {
 	br port.BotRepository
+	cp port.CollaboratorProvider
 	im port.InstanceManager
+	ep port.EventPublisher
 }
 
 func (h enableBotHandler) Handle(ctx context.Context, cmd request.EnableBotCommand) error {
-	bot, err := h.br.Bot(ctx, bots.BotID(cmd.BotID))
+	bot, err := access.AccessibleBot(ctx, h.br, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit)
 	if err != nil {
 		return err
 	}
@@ -32,15 +36,19 @@ func (h enableBotHandler) Handle(ctx context.Context, cmd request.EnableBotComma
 		// Бот может быть включён в автозапуск, но не запущен. Проведение компенсирующей транзакции
 		bot.Disable()
 		_ = h.br.UpsertBot(ctx, bot)
+		return err
 	}
-	return err
+	return h.ep.Publish(ctx, bots.BotEnabled{BotID: bot.ID(), EnabledAt: time.Now()})
 }
 
 func NewEnableBotHandler(
 	bm port.BotRepository,
+	cp port.CollaboratorProvider,
 	im port.InstanceManager,
+	ep port.EventPublisher,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) EnableBotHandler {
-	return decorator.ApplyCommandDecorators(enableBotHandler{bm, im}, l, mc)
+	return decorator.ApplyCommandDecorators(enableBotHandler{bm, cp, im, ep}, l, mc, ar)
 }
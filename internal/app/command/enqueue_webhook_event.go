@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type EnqueueWebhookEventHandler decorator.CommandHandler[request.EnqueueWebhookEventCommand]
+
+type enqueueWebhookEventHandler struct {
+	wr port.WebhookRepository
+}
+
+func (h enqueueWebhookEventHandler) Handle(ctx context.Context, cmd request.EnqueueWebhookEventCommand) error {
+	ws, err := h.wr.BotWebhooks(ctx, bots.BotID(cmd.BotID))
+	if err != nil {
+		return err
+	}
+	subscribed := make([]*bots.Webhook, 0, len(ws))
+	for _, w := range ws {
+		if w.SubscribedTo(cmd.Event) {
+			subscribed = append(subscribed, w)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+	return h.wr.EnqueueWebhookDeliveries(ctx, subscribed, cmd.EventUUID, cmd.Event, cmd.Payload)
+}
+
+func NewEnqueueWebhookEventHandler(
+	wr port.WebhookRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) EnqueueWebhookEventHandler {
+	return decorator.ApplyCommandDecorators(enqueueWebhookEventHandler{wr}, l, mc, ar)
+}
@@ -0,0 +1,66 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/command"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+// memWebhooks хранит Webhook в памяти и запоминает каждую постановку события в очередь.
+type memWebhooks struct {
+	port.WebhookRepository
+	webhooks []*bots.Webhook
+	enqueued [][]bots.WebhookID
+	uuids    []string
+}
+
+func (m *memWebhooks) BotWebhooks(context.Context, bots.BotID) ([]*bots.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *memWebhooks) EnqueueWebhookDeliveries(
+	_ context.Context, ws []*bots.Webhook, eventUUID string, _ string, _ []byte,
+) error {
+	ids := make([]bots.WebhookID, len(ws))
+	for i, w := range ws {
+		ids[i] = w.ID()
+	}
+	m.enqueued = append(m.enqueued, ids)
+	m.uuids = append(m.uuids, eventUUID)
+	return nil
+}
+
+func TestEnqueueWebhookEvent_EnqueuesSubscribedAtOnce(t *testing.T) {
+	hook := func(id bots.WebhookID, events ...string) *bots.Webhook {
+		return bots.MustNewWebhook(id, "bot", "https://example.com/"+string(id), "0123456789abcdef", events)
+	}
+	wr := &memWebhooks{webhooks: []*bots.Webhook{
+		hook("started", bots.EventThreadStarted),
+		hook("completed", bots.EventThreadCompleted),
+		hook("both", bots.EventThreadStarted, bots.EventThreadCompleted),
+	}}
+	h := command.NewEnqueueWebhookEventHandler(wr, &memAudit{}, logs.DefaultLogger(), metrics.NoOp{})
+
+	err := h.Handle(context.Background(), request.EnqueueWebhookEventCommand{
+		BotID: "bot", EventUUID: "event-uuid", Event: bots.EventThreadCompleted, Payload: []byte(`{}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]bots.WebhookID{{"completed", "both"}}, wr.enqueued)
+	require.Equal(t, []string{"event-uuid"}, wr.uuids)
+
+	// Событие без подписчиков в очередь не ставится
+	wr.webhooks = wr.webhooks[:1]
+	err = h.Handle(context.Background(), request.EnqueueWebhookEventCommand{
+		BotID: "bot", EventUUID: "other-uuid", Event: bots.EventThreadCompleted, Payload: []byte(`{}`),
+	})
+	require.NoError(t, err)
+	require.Len(t, wr.enqueued, 1)
+}
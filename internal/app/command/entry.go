@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -14,8 +15,8 @@ type EntryHandler decorator.CommandHandler[request.EntryCommand]
 
 type entryHandler struct {
 	bp port.BotProvider
-	pr port.ParticipantRepository
-	ms port.MessageSender
+	or port.OutboxRepository
+	re port.RequestExecutor
 }
 
 func (h entryHandler) Handle(ctx context.Context, cmd request.EntryCommand) error {
@@ -24,36 +25,52 @@ func (h entryHandler) Handle(ctx context.Context, cmd request.EntryCommand) erro
 		return err
 	}
 
+	if !bot.ChatPolicy().Accepts(cmd.Group, cmd.Addressed) {
+		// Сообщение из группы, в которой бот не должен на него отвечать
+		return nil
+	}
+
 	script := bot.Script()
-	prtID := bots.NewParticipantID(bots.UserID(cmd.UserID), bots.BotID(cmd.BotID))
+	prtID := bots.NewChatParticipantID(
+		bots.ChatID(cmd.ChatID), bots.UserID(cmd.UserID), bots.BotID(cmd.BotID),
+	)
+	profile := dto.ProfileFromDTO(cmd.Profile)
 
-	var response []bots.BotMessage
-	err = h.pr.UpdateOrCreateParticipant(ctx, prtID, func(
-		_ context.Context, prt *bots.Participant,
-	) error {
-		response, err = script.Entry(prt, bots.EntryKey(cmd.Key))
-		return err
+	// Ответ бота и события участника сохраняются в outbox в одной транзакции с участником
+	// и доставляются в фоне, поэтому ошибка отправки не оставляет участника без следующего вопроса
+	var pending *pendingRequest
+	err = h.or.UpdateOrCreateParticipantWithOutbox(ctx, prtID, func(
+		ctx context.Context, prt *bots.Participant,
+	) ([]bots.BotMessage, error) {
+		applied, err2 := applyUpdate(prt, cmd.UpdateID)
+		if err2 != nil || !applied {
+			// Повторно полученное обновление уже продвинуло тред участника
+			return nil, err2
+		}
+		// Пользователь написал боту, значит, бот им более не заблокирован
+		prt.Unblock()
+		prt.UpdateProfile(profile)
+		response, err2 := script.Entry(prt, bots.EntryKey(cmd.Key))
+		if err2 != nil {
+			return nil, err2
+		}
+		pending = takePendingRequest(script, prt)
+		return response, nil
 	})
 	if err != nil {
 		return err
 	}
 
-	for _, msg := range response {
-		err = h.ms.Send(ctx, bot.Token(), prtID.UserID(), msg)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return runRequests(ctx, h.or, h.re, script, prtID, pending)
 }
 
 func NewEntryHandler(
 	bp port.BotProvider,
-	pr port.ParticipantRepository,
-	ms port.MessageSender,
+	or port.OutboxRepository,
+	re port.RequestExecutor,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) EntryHandler {
-	return decorator.ApplyCommandDecorators(entryHandler{bp, pr, ms}, l, mc)
+	return decorator.ApplyCommandDecorators(entryHandler{bp, or, re}, l, mc, ar)
 }
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type EraseParticipantDataHandler decorator.CommandHandler[request.EraseParticipantDataCommand]
+
+type eraseParticipantDataHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	dr port.ParticipantDataRepository
+}
+
+func (h eraseParticipantDataHandler) Handle(ctx context.Context, cmd request.EraseParticipantDataCommand) error {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+	return eraseParticipantData(ctx, h.dr, bots.UserID(cmd.UserID), bot.ID(), cmd.Anonymize)
+}
+
+func NewEraseParticipantDataHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	dr port.ParticipantDataRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) EraseParticipantDataHandler {
+	return decorator.ApplyCommandDecorators(eraseParticipantDataHandler{bp, cp, dr}, l, mc, ar)
+}
+
+// eraseParticipantData удаляет или обезличивает данные пользователя в боте botID или, если botID пуст, во
+// всех ботах. Возвращает port.ErrParticipantNotFound, если данных не было.
+func eraseParticipantData(
+	ctx context.Context,
+	dr port.ParticipantDataRepository,
+	userID bots.UserID,
+	botID bots.BotID,
+	anonymize bool,
+) error {
+	var (
+		n   int
+		err error
+	)
+	if anonymize {
+		n, err = dr.AnonymizeParticipantData(ctx, userID, botID)
+	} else {
+		n, err = dr.EraseParticipantData(ctx, userID, botID)
+	}
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: user %d", port.ErrParticipantNotFound, userID)
+	}
+	return nil
+}
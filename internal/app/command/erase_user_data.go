@@ -0,0 +1,32 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// EraseUserDataHandler стирает данные пользователя Telegram во всех ботах без проверки доступа, поэтому
+// его строит только команда оператора participant-data, а не app.Application HTTP-сервера.
+type EraseUserDataHandler decorator.CommandHandler[request.EraseUserDataCommand]
+
+type eraseUserDataHandler struct {
+	dr port.ParticipantDataRepository
+}
+
+func (h eraseUserDataHandler) Handle(ctx context.Context, cmd request.EraseUserDataCommand) error {
+	return eraseParticipantData(ctx, h.dr, bots.UserID(cmd.UserID), bots.BotID(cmd.BotID), cmd.Anonymize)
+}
+
+func NewEraseUserDataHandler(
+	dr port.ParticipantDataRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) EraseUserDataHandler {
+	return decorator.ApplyCommandDecorators(eraseUserDataHandler{dr}, l, mc, ar)
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type InviteCollaboratorHandler decorator.CommandHandler[request.InviteCollaboratorCommand]
+
+type inviteCollaboratorHandler struct {
+	bp port.BotProvider
+	cr port.CollaboratorRepository
+}
+
+func (h inviteCollaboratorHandler) Handle(ctx context.Context, cmd request.InviteCollaboratorCommand) error {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cr, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+
+	account := bots.AccountID(cmd.CollaboratorID)
+	if err = checkNotAuthor(bot, account); err != nil {
+		return err
+	}
+
+	_, err = h.cr.Collaborator(ctx, bot.ID(), account)
+	if err == nil {
+		return fmt.Errorf("%w: %s", port.ErrCollaboratorAlreadyExists, cmd.CollaboratorID)
+	} else if !errors.Is(err, port.ErrCollaboratorNotFound) {
+		return err
+	}
+
+	role, err := bots.NewRole(cmd.Role)
+	if err != nil {
+		return err
+	}
+	c, err := bots.NewCollaborator(bot.ID(), account, role)
+	if err != nil {
+		return err
+	}
+	return h.cr.UpsertCollaborator(ctx, c)
+}
+
+func NewInviteCollaboratorHandler(
+	bp port.BotProvider,
+	cr port.CollaboratorRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) InviteCollaboratorHandler {
+	return decorator.ApplyCommandDecorators(inviteCollaboratorHandler{bp, cr}, l, mc, ar)
+}
+
+// checkNotAuthor запрещает изменять доступ автора бота: он всегда является владельцем.
+func checkNotAuthor(bot *bots.Bot, account bots.AccountID) error {
+	if bot.Author() == account {
+		return bots.NewInvalidInputError(
+			"collaborator-is-author", "bot author is always its owner", "field", "accountId",
+		)
+	}
+	return nil
+}
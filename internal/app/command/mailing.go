@@ -3,7 +3,9 @@ package command
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -14,58 +16,72 @@ type MailingHandler decorator.CommandHandler[request.MailingCommand]
 
 type mailingHandler struct {
 	bp port.BotProvider
-	pr port.ParticipantRepository
-	ms port.MessageSender
+	cp port.CollaboratorProvider
+	or port.OutboxRepository
+	re port.RequestExecutor
+	ep port.EventPublisher
 }
 
 func (h mailingHandler) Handle(ctx context.Context, cmd request.MailingCommand) error {
 	botID := bots.BotID(cmd.BotID)
 	entryKey := bots.EntryKey(cmd.EntryKey)
 
-	bot, err := h.bp.Bot(ctx, botID)
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit)
 	if err != nil {
 		return err
 	}
 
 	script := bot.Script()
 
-	var errs bots.MultiError
+	recipients, skipped := 0, 0
 	for _, user := range cmd.Users {
 		prtID := bots.NewParticipantID(bots.UserID(user), botID)
 
-		var response []bots.BotMessage
-		err = h.pr.UpdateOrCreateParticipant(ctx, prtID, func(
-			_ context.Context, prt *bots.Participant,
-		) error {
-			response, err = script.Entry(prt, entryKey)
-			return err
+		var pending *pendingRequest
+		err = h.or.UpdateOrCreateParticipantWithOutbox(ctx, prtID, func(
+			ctx context.Context, prt *bots.Participant,
+		) ([]bots.BotMessage, error) {
+			if prt.IsBlocked() {
+				// Пользователь заблокировал бота: сообщения всё равно не будут доставлены
+				skipped++
+				return nil, nil
+			}
+			response, err2 := script.Entry(prt, entryKey)
+			if err2 != nil {
+				return nil, err2
+			}
+			pending = takePendingRequest(script, prt)
+			recipients++
+			return response, nil
 		})
+		if err == nil {
+			err = runRequests(ctx, h.or, h.re, script, prtID, pending)
+		}
 		if err != nil {
 			// Ошибка в операции над участником критична, возвращаем ошибку сразу
 			return err
 		}
-
-		for _, msg := range response {
-			err = h.ms.Send(ctx, bot.Token(), prtID.UserID(), msg)
-			if err != nil {
-				// Если ошибка отправки конкретному пользователю, это не должно повлиять на ход рассылки
-				errs.Append(err)
-			}
-		}
 	}
 
-	if errs.HasError() {
-		return &errs
-	}
-	return nil
+	// События участников сохранены вместе с ними, остаётся итог рассылки
+	return h.ep.Publish(ctx, bots.MailingFinished{
+		BotID:      botID,
+		Key:        entryKey,
+		Recipients: recipients,
+		Skipped:    skipped,
+		FinishedAt: time.Now(),
+	})
 }
 
 func NewMailingHandler(
 	bp port.BotProvider,
-	pr port.ParticipantRepository,
-	ms port.MessageSender,
+	cp port.CollaboratorProvider,
+	or port.OutboxRepository,
+	re port.RequestExecutor,
+	ep port.EventPublisher,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) MailingHandler {
-	return decorator.ApplyCommandDecorators(mailingHandler{bp, pr, ms}, l, mc)
+	return decorator.ApplyCommandDecorators(mailingHandler{bp, cp, or, re, ep}, l, mc, ar)
 }
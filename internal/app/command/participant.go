@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// blockOnUserBlockedBot отмечает участника заблокировавшим бота, если ошибка отправки
+// сообщения вызвана блокировкой. Возвращает исходную ошибку отправки.
+func blockOnUserBlockedBot(
+	ctx context.Context, pr port.ParticipantRepository, prtID bots.ParticipantID, sendErr error,
+) error {
+	if !errors.Is(sendErr, port.ErrUserBlockedBot) {
+		return sendErr
+	}
+
+	err := pr.UpdateOrCreateParticipant(ctx, prtID, func(_ context.Context, prt *bots.Participant) error {
+		prt.Block()
+		return nil
+	})
+	if err != nil {
+		return errors.Join(sendErr, err)
+	}
+
+	return sendErr
+}
+
+// applyUpdate отмечает обновление Telegram применённым к участнику. Возвращает false, если
+// обновление уже было применено и его следует пропустить. Нулевой updateID (например, у
+// рассылок) не проверяется.
+func applyUpdate(prt *bots.Participant, updateID int64) (bool, error) {
+	if updateID == 0 {
+		return true, nil
+	}
+	err := prt.ApplyUpdate(bots.UpdateID(updateID))
+	if errors.Is(err, bots.ErrUpdateAlreadyApplied) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -15,8 +15,8 @@ type ProcessHandler decorator.CommandHandler[request.ProcessCommand]
 
 type processHandler struct {
 	bp port.BotProvider
-	pr port.ParticipantRepository
-	ms port.MessageSender
+	or port.OutboxRepository
+	re port.RequestExecutor
 }
 
 func (h processHandler) Handle(ctx context.Context, cmd request.ProcessCommand) error {
@@ -25,40 +25,56 @@ func (h processHandler) Handle(ctx context.Context, cmd request.ProcessCommand)
 		return err
 	}
 
+	if !bot.ChatPolicy().Accepts(cmd.Group, cmd.Addressed) {
+		// Сообщение из группы, в которой бот не должен на него отвечать
+		return nil
+	}
+
 	script := bot.Script()
-	prtID := bots.NewParticipantID(bots.UserID(cmd.UserID), bots.BotID(cmd.BotID))
+	prtID := bots.NewChatParticipantID(
+		bots.ChatID(cmd.ChatID), bots.UserID(cmd.UserID), bots.BotID(cmd.BotID),
+	)
+	profile := dto.ProfileFromDTO(cmd.Profile)
 	message, err := dto.MessageFromDTO(cmd.Message)
 	if err != nil {
 		return err
 	}
 
-	var response []bots.BotMessage
-	err = h.pr.UpdateOrCreateParticipant(ctx, prtID, func(
-		_ context.Context, prt *bots.Participant,
-	) error {
-		response, err = script.Process(prt, message)
-		return err
+	// Ответ бота и события участника сохраняются в outbox в одной транзакции с участником
+	// и доставляются в фоне, поэтому ошибка отправки не оставляет участника без следующего вопроса
+	var pending *pendingRequest
+	err = h.or.UpdateOrCreateParticipantWithOutbox(ctx, prtID, func(
+		ctx context.Context, prt *bots.Participant,
+	) ([]bots.BotMessage, error) {
+		applied, err2 := applyUpdate(prt, cmd.UpdateID)
+		if err2 != nil || !applied {
+			// Повторно полученное обновление уже продвинуло тред участника
+			return nil, err2
+		}
+		// Пользователь написал боту, значит, бот им более не заблокирован
+		prt.Unblock()
+		prt.UpdateProfile(profile)
+		response, err2 := script.Process(prt, message)
+		if err2 != nil {
+			return nil, err2
+		}
+		pending = takePendingRequest(script, prt)
+		return response, nil
 	})
 	if err != nil {
 		return err
 	}
 
-	for _, msg := range response {
-		err = h.ms.Send(ctx, bot.Token(), prtID.UserID(), msg)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return runRequests(ctx, h.or, h.re, script, prtID, pending)
 }
 
 func NewProcessHandler(
 	bp port.BotProvider,
-	pr port.ParticipantRepository,
-	ms port.MessageSender,
+	or port.OutboxRepository,
+	re port.RequestExecutor,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) ProcessHandler {
-	return decorator.ApplyCommandDecorators(processHandler{bp, pr, ms}, l, mc)
+	return decorator.ApplyCommandDecorators(processHandler{bp, or, re}, l, mc, ar)
 }
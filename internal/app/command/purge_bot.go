@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type PurgeBotHandler decorator.CommandHandler[request.PurgeBotCommand]
+
+type purgeBotHandler struct {
+	dr port.DeletedBotRepository
+	cp port.CollaboratorProvider
+}
+
+func (h purgeBotHandler) Handle(ctx context.Context, cmd request.PurgeBotCommand) error {
+	bot, err := access.AccessibleDeletedBot(ctx, h.dr, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+	return h.dr.PurgeBot(ctx, bot.ID())
+}
+
+func NewPurgeBotHandler(
+	dr port.DeletedBotRepository,
+	cp port.CollaboratorProvider,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) PurgeBotHandler {
+	return decorator.ApplyCommandDecorators(purgeBotHandler{dr, cp}, l, mc, ar)
+}
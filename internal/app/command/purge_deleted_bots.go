@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type PurgeDeletedBotsHandler decorator.CommandHandler[request.PurgeDeletedBotsCommand]
+
+type purgeDeletedBotsHandler struct {
+	dr port.DeletedBotRepository
+}
+
+func (h purgeDeletedBotsHandler) Handle(ctx context.Context, cmd request.PurgeDeletedBotsCommand) error {
+	ids, err := h.dr.BotsDeletedBefore(ctx, cmd.DeletedBefore)
+	if err != nil {
+		return err
+	}
+	var errs bots.MultiError
+	for _, id := range ids {
+		err = h.dr.PurgeBot(ctx, id)
+		// Бота могли восстановить или удалить окончательно после выборки, например, на другой реплике
+		if err != nil && !errors.Is(err, port.ErrBotNotFound) {
+			errs.ExtendOrAppend(err)
+		}
+	}
+	if errs.HasError() {
+		return &errs
+	}
+	return nil
+}
+
+func NewPurgeDeletedBotsHandler(
+	dr port.DeletedBotRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) PurgeDeletedBotsHandler {
+	return decorator.ApplyCommandDecorators(purgeDeletedBotsHandler{dr}, l, mc, ar)
+}
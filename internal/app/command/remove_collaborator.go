@@ -0,0 +1,42 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type RemoveCollaboratorHandler decorator.CommandHandler[request.RemoveCollaboratorCommand]
+
+type removeCollaboratorHandler struct {
+	bp port.BotProvider
+	cr port.CollaboratorRepository
+}
+
+func (h removeCollaboratorHandler) Handle(ctx context.Context, cmd request.RemoveCollaboratorCommand) error {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cr, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+
+	account := bots.AccountID(cmd.CollaboratorID)
+	if err = checkNotAuthor(bot, account); err != nil {
+		return err
+	}
+	return h.cr.DeleteCollaborator(ctx, bot.ID(), account)
+}
+
+func NewRemoveCollaboratorHandler(
+	bp port.BotProvider,
+	cr port.CollaboratorRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) RemoveCollaboratorHandler {
+	return decorator.ApplyCommandDecorators(removeCollaboratorHandler{bp, cr}, l, mc, ar)
+}
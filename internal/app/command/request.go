@@ -0,0 +1,78 @@
+package command
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// maxChainedRequests ограничивает количество узлов-запросов, выполняемых подряд в ответ на одно
+// сообщение. Так сценарий с циклом из узлов-запросов не заблокирует обработку обновлений: тред
+// остаётся в узле-запросе, и запрос будет повторён при следующем сообщении участника.
+const maxChainedRequests = 10
+
+// pendingRequest есть запрос узла-запроса, в котором остановился активный тред участника.
+type pendingRequest struct {
+	threadID bots.ThreadID
+	state    bots.State
+	req      bots.RenderedRequest
+}
+
+// takePendingRequest возвращает запрос узла-запроса, в котором находится активный тред участника,
+// или nil, если тред не ждёт запроса.
+func takePendingRequest(script bots.Script, prt *bots.Participant) *pendingRequest {
+	req, ok := script.PendingRequest(prt)
+	if !ok {
+		return nil
+	}
+	thread := prt.ActiveThread()
+	return &pendingRequest{
+		threadID: thread.ID(),
+		state:    thread.State(),
+		req:      req,
+	}
+}
+
+// runRequests выполняет запрос pending и запросы узлов-запросов, в которые тред переходит по
+// результатам. Запросы выполняются вне транзакции, чтобы медленный внешний сервис не удерживал
+// соединение с базой данных и блокировку участника; результат каждого запроса применяется
+// в отдельной транзакции вместе с сообщениями узла, в который перешёл тред. Если ответ не
+// получен, запрос считается неудавшимся.
+func runRequests(
+	ctx context.Context,
+	or port.OutboxRepository,
+	re port.RequestExecutor,
+	script bots.Script,
+	id bots.ParticipantID,
+	pending *pendingRequest,
+) error {
+	for i := 0; pending != nil && i < maxChainedRequests; i++ {
+		result, err := re.Execute(ctx, pending.req)
+		if err != nil {
+			result = bots.RequestResult{}
+		}
+
+		executed := *pending
+		pending = nil
+		err = or.UpdateOrCreateParticipantWithOutbox(ctx, id, func(
+			_ context.Context, prt *bots.Participant,
+		) ([]bots.BotMessage, error) {
+			thread := prt.ActiveThread()
+			if thread == nil || thread.ID() != executed.threadID || thread.State() != executed.state {
+				// Пока выполнялся запрос, тред продвинуло другое обновление: результат устарел
+				return nil, nil
+			}
+			msgs, err2 := script.CompleteRequest(prt, result)
+			if err2 != nil {
+				return nil, err2
+			}
+			pending = takePendingRequest(script, prt)
+			return msgs, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
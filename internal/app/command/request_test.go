@@ -0,0 +1,119 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/command"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+// memOutbox хранит участников в памяти и отмечает, выполняется ли сейчас транзакция.
+type memOutbox struct {
+	port.OutboxRepository
+	participants map[bots.ParticipantID]*bots.Participant
+	sent         []bots.BotMessage
+	inTx         bool
+}
+
+func (m *memOutbox) UpdateOrCreateParticipantWithOutbox(
+	ctx context.Context,
+	id bots.ParticipantID,
+	updateFn func(context.Context, *bots.Participant) ([]bots.BotMessage, error),
+) error {
+	prt, ok := m.participants[id]
+	if !ok {
+		var err error
+		prt, err = bots.NewParticipant(id)
+		if err != nil {
+			return err
+		}
+		m.participants[id] = prt
+	}
+	m.inTx = true
+	defer func() { m.inTx = false }()
+	msgs, err := updateFn(ctx, prt)
+	if err != nil {
+		return err
+	}
+	m.sent = append(m.sent, msgs...)
+	return nil
+}
+
+// txCheckingExecutor проверяет, что запрос выполняется вне транзакции, и перед ответом вызывает during.
+type txCheckingExecutor struct {
+	t      *testing.T
+	or     *memOutbox
+	during func()
+	calls  int
+}
+
+func (e *txCheckingExecutor) Execute(context.Context, bots.RenderedRequest) (bots.RequestResult, error) {
+	require.False(e.t, e.or.inTx, "request executed inside transaction")
+	e.calls++
+	if e.during != nil {
+		e.during()
+	}
+	return bots.RequestResult{StatusCode: 200}, nil
+}
+
+func requestTestBot() *bots.Bot {
+	return bots.MustNewBot("bot", "123456:secret", "author", bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewRequestNode(bots.MustNewState(1), "Lookup", nil, bots.MustNewRequest(
+				"GET", "https://crm.example.com/students", nil, "", nil, bots.MustNewState(2), bots.MustNewState(3),
+			)),
+			bots.MustNewNode(bots.MustNewState(2), "Found", nil, []bots.Message{bots.MustNewMessage("Found")}, nil),
+			bots.MustNewNode(bots.MustNewState(3), "Failed", nil, []bots.Message{bots.MustNewMessage("Failed")}, nil),
+		},
+		[]bots.Entry{bots.MustNewEntry("start", bots.MustNewState(1))},
+	))
+}
+
+func TestEntry_RunsRequestsOutsideTransaction(t *testing.T) {
+	bot := requestTestBot()
+	or := &memOutbox{participants: make(map[bots.ParticipantID]*bots.Participant)}
+	re := &txCheckingExecutor{t: t, or: or}
+	h := command.NewEntryHandler(memBots{bot.ID(): bot}, or, re, &memAudit{}, logs.DefaultLogger(), metrics.NoOp{})
+
+	err := h.Handle(context.Background(), request.EntryCommand{BotID: "bot", ChatID: 42, UserID: 42, Key: "start"})
+	require.NoError(t, err)
+	require.Equal(t, 1, re.calls)
+
+	prt := or.participants[bots.NewChatParticipantID(42, 42, "bot")]
+	require.Equal(t, bots.MustNewState(2), prt.ActiveThread().State())
+	require.Len(t, or.sent, 1)
+	require.Equal(t, "Found", or.sent[0].Text())
+}
+
+func TestEntry_DropsStaleRequestResult(t *testing.T) {
+	bot := requestTestBot()
+	or := &memOutbox{participants: make(map[bots.ParticipantID]*bots.Participant)}
+	re := &txCheckingExecutor{t: t, or: or}
+	prtID := bots.NewChatParticipantID(42, 42, "bot")
+	// Пока выполняется запрос, участник начинает тред заново
+	re.during = func() {
+		re.during = nil
+		err := or.UpdateOrCreateParticipantWithOutbox(context.Background(), prtID, func(
+			_ context.Context, prt *bots.Participant,
+		) ([]bots.BotMessage, error) {
+			_, err := prt.StartThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+			return nil, err
+		})
+		require.NoError(t, err)
+	}
+	h := command.NewEntryHandler(memBots{bot.ID(): bot}, or, re, &memAudit{}, logs.DefaultLogger(), metrics.NoOp{})
+
+	err := h.Handle(context.Background(), request.EntryCommand{BotID: "bot", ChatID: 42, UserID: 42, Key: "start"})
+	require.NoError(t, err)
+
+	// Результат запроса прежнего треда не продвигает новый тред
+	require.Equal(t, bots.MustNewState(1), or.participants[prtID].ActiveThread().State())
+	require.Empty(t, or.sent)
+}
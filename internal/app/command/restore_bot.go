@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type RestoreBotHandler decorator.CommandHandler[request.RestoreBotCommand]
+
+type restoreBotHandler struct {
+	br port.BotRepository
+	dr port.DeletedBotRepository
+	cp port.CollaboratorProvider
+	tv port.TokenVerifier
+}
+
+// Handle восстанавливает удалённого бота выключенным. ID удалённого бота не может занять другой бот
+// (см. port.ErrBotIDTaken), а его токен - может, поэтому перед восстановлением токен проверяется так
+// же, как при сохранении бота.
+func (h restoreBotHandler) Handle(ctx context.Context, cmd request.RestoreBotCommand) error {
+	bot, err := access.AccessibleDeletedBot(ctx, h.dr, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+	unverified := bot.Telegram().IsZero()
+	if err = verifyBotToken(ctx, h.br, h.tv, bot); err != nil {
+		return err
+	}
+	if err = h.dr.RestoreBot(ctx, bot.ID()); err != nil {
+		return err
+	}
+	if unverified {
+		// Сохраняем аккаунт Telegram бота, удалённого до появления проверки токенов
+		bot.Disable()
+		return h.br.UpsertBot(ctx, bot)
+	}
+	return nil
+}
+
+func NewRestoreBotHandler(
+	br port.BotRepository,
+	dr port.DeletedBotRepository,
+	cp port.CollaboratorProvider,
+	tv port.TokenVerifier,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) RestoreBotHandler {
+	return decorator.ApplyCommandDecorators(restoreBotHandler{br, dr, cp, tv}, l, mc, ar)
+}
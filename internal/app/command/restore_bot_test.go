@@ -0,0 +1,100 @@
+package command_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/command"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+// memDeletedBots хранит удалённых ботов отдельно от неудалённых ботов active.
+type memDeletedBots struct {
+	active  memBots
+	deleted map[bots.BotID]port.DeletedBot
+}
+
+func (m memDeletedBots) delete(id bots.BotID) {
+	m.deleted[id] = port.DeletedBot{Bot: m.active[id], DeletedAt: time.Now()}
+	delete(m.active, id)
+}
+
+func (m memDeletedBots) DeletedBot(_ context.Context, id bots.BotID) (port.DeletedBot, error) {
+	if d, ok := m.deleted[id]; ok {
+		return d, nil
+	}
+	return port.DeletedBot{}, fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+}
+
+func (m memDeletedBots) UserDeletedBots(context.Context, bots.AccountID) ([]port.DeletedBot, error) {
+	return nil, nil
+}
+
+func (m memDeletedBots) BotsDeletedBefore(context.Context, time.Time) ([]bots.BotID, error) {
+	return nil, nil
+}
+
+func (m memDeletedBots) RestoreBot(_ context.Context, id bots.BotID) error {
+	d, ok := m.deleted[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+	}
+	delete(m.deleted, id)
+	m.active[id] = d.Bot
+	return nil
+}
+
+func (m memDeletedBots) PurgeBot(_ context.Context, id bots.BotID) error {
+	if _, ok := m.deleted[id]; !ok {
+		return fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+	}
+	delete(m.deleted, id)
+	return nil
+}
+
+func TestRestoreBot_ChecksTokenConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := memBots{}
+	deleted := memDeletedBots{active: repo, deleted: map[bots.BotID]port.DeletedBot{}}
+	tg := &fakeTelegram{accounts: map[bots.Token]bots.TelegramAccount{
+		"1:a": bots.MustNewTelegramAccount(1, "first_bot"),
+		"1:b": bots.MustNewTelegramAccount(1, "first_bot"),
+	}}
+	create := command.NewCreateBotHandler(
+		repo, noCollaborators{}, nopClientCache{}, tg, memInstances{}, &memAudit{},
+		logs.DefaultLogger(), metrics.NoOp{},
+	)
+	restore := command.NewRestoreBotHandler(
+		repo, deleted, noCollaborators{}, tg, &memAudit{}, logs.DefaultLogger(), metrics.NoOp{},
+	)
+
+	require.NoError(t, create.Handle(ctx, createBotCommand("bot", "1:a")))
+	deleted.delete("bot")
+
+	// Пока бот удалён, токен его аккаунта Telegram может занять другой бот
+	require.NoError(t, create.Handle(ctx, createBotCommand("other", "1:b")))
+
+	err := restore.Handle(ctx, request.RestoreBotCommand{AccountID: "stranger", BotID: "bot"})
+	require.ErrorIs(t, err, bots.ErrBotAccessDenied)
+
+	var iiErr bots.InvalidInputError
+	err = restore.Handle(ctx, request.RestoreBotCommand{AccountID: "author", BotID: "bot"})
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "bot-token-conflict", iiErr.Code)
+	require.Contains(t, deleted.deleted, bots.BotID("bot"))
+
+	deleted.delete("other")
+	require.NoError(t, restore.Handle(ctx, request.RestoreBotCommand{AccountID: "author", BotID: "bot"}))
+	require.Contains(t, repo, bots.BotID("bot"))
+
+	err = restore.Handle(ctx, request.RestoreBotCommand{AccountID: "author", BotID: "bot"})
+	require.ErrorIs(t, err, port.ErrBotNotFound)
+}
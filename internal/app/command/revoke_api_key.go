@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type RevokeAPIKeyHandler decorator.CommandHandler[request.RevokeAPIKeyCommand]
+
+type revokeAPIKeyHandler struct {
+	kr port.APIKeyRepository
+}
+
+func (h revokeAPIKeyHandler) Handle(ctx context.Context, cmd request.RevokeAPIKeyCommand) error {
+	if err := access.RequireJWT(cmd.Scope); err != nil {
+		return err
+	}
+
+	k, err := h.kr.APIKey(ctx, bots.APIKeyID(cmd.KeyID))
+	if err != nil {
+		return err
+	}
+	// Чужой ключ неотличим от несуществующего
+	if k.AccountID() != bots.AccountID(cmd.AccountID) {
+		return fmt.Errorf("%w: %s", port.ErrAPIKeyNotFound, cmd.KeyID)
+	}
+	return h.kr.DeleteAPIKey(ctx, k.ID())
+}
+
+func NewRevokeAPIKeyHandler(
+	kr port.APIKeyRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) RevokeAPIKeyHandler {
+	return decorator.ApplyCommandDecorators(revokeAPIKeyHandler{kr}, l, mc, ar)
+}
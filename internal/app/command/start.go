@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -15,10 +16,11 @@ type StartHandler decorator.CommandHandler[request.StartCommand]
 type startHandler struct {
 	im port.InstanceManager
 	bp port.BotProvider
+	cp port.CollaboratorProvider
 }
 
 func (h startHandler) Handle(ctx context.Context, cmd request.StartCommand) error {
-	bot, err := h.bp.Bot(ctx, bots.BotID(cmd.BotID))
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit)
 	if err != nil {
 		return err
 	}
@@ -28,8 +30,10 @@ func (h startHandler) Handle(ctx context.Context, cmd request.StartCommand) erro
 func NewStartHandler(
 	im port.InstanceManager,
 	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) StartHandler {
-	return decorator.ApplyCommandDecorators(startHandler{im, bp}, l, mc)
+	return decorator.ApplyCommandDecorators(startHandler{im, bp, cp}, l, mc, ar)
 }
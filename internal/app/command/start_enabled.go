@@ -38,8 +38,9 @@ func (h startEnabledHandler) Handle(ctx context.Context, _ request.StartEnabledB
 func NewStartEnabledHandler(
 	im port.InstanceManager,
 	bp port.BotProvider,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) StartEnabledHandler {
-	return decorator.ApplyCommandDecorators(startEnabledHandler{im, bp}, l, mc)
+	return decorator.ApplyCommandDecorators(startEnabledHandler{im, bp}, l, mc, ar)
 }
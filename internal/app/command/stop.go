@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
@@ -14,16 +15,25 @@ type StopHandler decorator.CommandHandler[request.StopCommand]
 
 type stopHandler struct {
 	im port.InstanceManager
+	bp port.BotProvider
+	cp port.CollaboratorProvider
 }
 
 func (h stopHandler) Handle(ctx context.Context, cmd request.StopCommand) error {
-	return h.im.Stop(ctx, bots.BotID(cmd.BotID))
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit)
+	if err != nil {
+		return err
+	}
+	return h.im.Stop(ctx, bot.ID())
 }
 
 func NewStopHandler(
 	im port.InstanceManager,
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) StopHandler {
-	return decorator.ApplyCommandDecorators(stopHandler{im}, l, mc)
+	return decorator.ApplyCommandDecorators(stopHandler{im, bp, cp}, l, mc, ar)
 }
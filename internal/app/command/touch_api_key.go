@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// TouchAPIKeyHandler запоминает время использования API-ключа, прошедшего проверку
+// query.AuthenticateAPIKeyHandler.
+type TouchAPIKeyHandler decorator.CommandHandler[request.TouchAPIKeyCommand]
+
+type touchAPIKeyHandler struct {
+	kr port.APIKeyRepository
+}
+
+func (h touchAPIKeyHandler) Handle(ctx context.Context, cmd request.TouchAPIKeyCommand) error {
+	k, err := h.kr.APIKey(ctx, bots.APIKeyID(cmd.KeyID))
+	if err != nil {
+		return err
+	}
+	k.MarkUsed(cmd.UsedAt)
+	return h.kr.TouchAPIKey(ctx, k.ID(), k.LastUsedAt())
+}
+
+func NewTouchAPIKeyHandler(
+	kr port.APIKeyRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) TouchAPIKeyHandler {
+	return decorator.ApplyCommandDecorators(touchAPIKeyHandler{kr}, l, mc, ar)
+}
@@ -13,6 +13,10 @@ type UpdateBotHandler decorator.CommandHandler[request.UpdateBotCommand]
 
 type updateBotHandler struct {
 	br port.BotRepository
+	cp port.CollaboratorProvider
+	cc port.ClientCache
+	tv port.TokenVerifier
+	im port.InstanceManager
 }
 
 func (h updateBotHandler) Handle(ctx context.Context, cmd request.UpdateBotCommand) error {
@@ -20,13 +24,18 @@ func (h updateBotHandler) Handle(ctx context.Context, cmd request.UpdateBotComma
 	if err != nil {
 		return err
 	}
-	return h.br.UpsertBot(ctx, bot)
+	return upsertBotInvalidatingToken(ctx, h.br, h.cp, h.cc, h.tv, h.im, bot, cmd.Scope)
 }
 
 func NewUpdateBotHandler(
 	br port.BotRepository,
+	cp port.CollaboratorProvider,
+	cc port.ClientCache,
+	tv port.TokenVerifier,
+	im port.InstanceManager,
+	ar decorator.AuditRecorder,
 	l *slog.Logger,
 	mc decorator.MetricsClient,
 ) UpdateBotHandler {
-	return decorator.ApplyCommandDecorators(updateBotHandler{br}, l, mc)
+	return decorator.ApplyCommandDecorators(updateBotHandler{br, cp, cc, tv, im}, l, mc, ar)
 }
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type UpdateCollaboratorHandler decorator.CommandHandler[request.UpdateCollaboratorCommand]
+
+type updateCollaboratorHandler struct {
+	bp port.BotProvider
+	cr port.CollaboratorRepository
+}
+
+func (h updateCollaboratorHandler) Handle(ctx context.Context, cmd request.UpdateCollaboratorCommand) error {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cr, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermManage)
+	if err != nil {
+		return err
+	}
+
+	account := bots.AccountID(cmd.CollaboratorID)
+	if err = checkNotAuthor(bot, account); err != nil {
+		return err
+	}
+
+	c, err := h.cr.Collaborator(ctx, bot.ID(), account)
+	if err != nil {
+		return err
+	}
+	role, err := bots.NewRole(cmd.Role)
+	if err != nil {
+		return err
+	}
+	if err = c.SetRole(role); err != nil {
+		return err
+	}
+	return h.cr.UpsertCollaborator(ctx, c)
+}
+
+func NewUpdateCollaboratorHandler(
+	bp port.BotProvider,
+	cr port.CollaboratorRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) UpdateCollaboratorHandler {
+	return decorator.ApplyCommandDecorators(updateCollaboratorHandler{bp, cr}, l, mc, ar)
+}
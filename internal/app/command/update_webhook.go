@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type UpdateWebhookHandler decorator.CommandHandler[request.UpdateWebhookCommand]
+
+type updateWebhookHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	wr port.WebhookRepository
+}
+
+func (h updateWebhookHandler) Handle(ctx context.Context, cmd request.UpdateWebhookCommand) error {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, cmd.BotID, cmd.AccountID, cmd.Scope, bots.PermEdit); err != nil {
+		return err
+	}
+	w, err := h.wr.Webhook(ctx, bots.BotID(cmd.BotID), bots.WebhookID(cmd.WebhookID))
+	if err != nil {
+		return err
+	}
+	if err = w.Update(cmd.URL, cmd.Secret, cmd.Events); err != nil {
+		return err
+	}
+	return h.wr.UpsertWebhook(ctx, w)
+}
+
+func NewUpdateWebhookHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	wr port.WebhookRepository,
+	ar decorator.AuditRecorder,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) UpdateWebhookHandler {
+	return decorator.ApplyCommandDecorators(updateWebhookHandler{bp, cp, wr}, l, mc, ar)
+}
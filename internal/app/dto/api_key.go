@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// APIKeyScope описывает ограничения API-ключа. В командах и запросах равен nil, если пользователь
+// аутентифицирован JWT, а не API-ключом.
+type APIKeyScope struct {
+	Bots    []string // Пустой список означает все боты пользователя
+	Actions []string
+}
+
+func APIKeyScopeFromDTO(s APIKeyScope) (bots.APIKeyScope, error) {
+	bs := make([]bots.BotID, len(s.Bots))
+	for i, b := range s.Bots {
+		bs[i] = bots.BotID(b)
+	}
+	ps := make([]bots.Permission, len(s.Actions))
+	for i, a := range s.Actions {
+		p, err := bots.NewPermission(a)
+		if err != nil {
+			return bots.APIKeyScope{}, err
+		}
+		ps[i] = p
+	}
+	return bots.NewAPIKeyScope(bs, ps)
+}
+
+func APIKeyScopeToDto(s bots.APIKeyScope) APIKeyScope {
+	bs := make([]string, len(s.Bots()))
+	for i, b := range s.Bots() {
+		bs[i] = string(b)
+	}
+	as := make([]string, len(s.Permissions()))
+	for i, p := range s.Permissions() {
+		as[i] = p.String()
+	}
+	return APIKeyScope{Bots: bs, Actions: as}
+}
+
+type APIKey struct {
+	ID         string
+	AccountID  string
+	Name       string
+	Scope      APIKeyScope
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func APIKeyToDto(k *bots.APIKey) APIKey {
+	res := APIKey{
+		ID:        string(k.ID()),
+		AccountID: string(k.AccountID()),
+		Name:      k.Name(),
+		Scope:     APIKeyScopeToDto(k.Scope()),
+		ExpiresAt: k.ExpiresAt(),
+		CreatedAt: k.CreatedAt(),
+	}
+	if t := k.LastUsedAt(); !t.IsZero() {
+		res.LastUsedAt = &t
+	}
+	return res
+}
+
+func BatchAPIKeyToDto(ks []*bots.APIKey) []APIKey {
+	res := make([]APIKey, len(ks))
+	for i, k := range ks {
+		res[i] = APIKeyToDto(k)
+	}
+	return res
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+)
+
+type AuditEntry struct {
+	ID      int64
+	Actor   string
+	Action  string
+	Payload []byte
+	Error   string // Пустая строка означает, что команда выполнена успешно
+	At      time.Time
+}
+
+func AuditRecordToDto(r port.AuditRecord) AuditEntry {
+	return AuditEntry{
+		ID:      r.ID,
+		Actor:   string(r.Actor),
+		Action:  r.Action,
+		Payload: r.Payload,
+		Error:   r.Error,
+		At:      r.CreatedAt,
+	}
+}
+
+func BatchAuditRecordToDto(rs []port.AuditRecord) []AuditEntry {
+	res := make([]AuditEntry, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, AuditRecordToDto(r))
+	}
+	return res
+}
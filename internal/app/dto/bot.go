@@ -5,27 +5,27 @@ import (
 )
 
 type Bot struct {
-	ID      string
-	Token   string
-	Author  int64
-	Enabled bool
-	Script  Script
+	ID         string
+	Token      string
+	Author     string
+	Enabled    bool
+	ChatPolicy string
+	Script     Script
+	// TelegramID и TelegramUsername пусты, если токен бота ещё не проверялся.
+	TelegramID       int64
+	TelegramUsername string
 }
 
 func BotToDto(bot *bots.Bot) Bot {
 	return Bot{
-		ID:      string(bot.ID()),
-		Token:   string(bot.Token()),
-		Author:  int64(bot.Author()),
-		Enabled: bot.Enabled(),
-		Script:  scriptToDTO(bot.Script()),
+		ID:         string(bot.ID()),
+		Token:      bot.Token().Redacted(),
+		Author:     string(bot.Author()),
+		Enabled:    bot.Enabled(),
+		ChatPolicy: bot.ChatPolicy().String(),
+		Script:     scriptToDTO(bot.Script()),
+		// Нулевой аккаунт даёт пустые значения
+		TelegramID:       bot.Telegram().ID(),
+		TelegramUsername: bot.Telegram().Username(),
 	}
 }
-
-func BatchBotToDto(bs []*bots.Bot) []Bot {
-	res := make([]Bot, 0, len(bs))
-	for _, bot := range bs {
-		res = append(res, BotToDto(bot))
-	}
-	return res
-}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type Collaborator struct {
+	AccountID string
+	Role      string
+	AddedAt   time.Time
+}
+
+func CollaboratorToDto(c *bots.Collaborator) Collaborator {
+	return Collaborator{
+		AccountID: string(c.AccountID()),
+		Role:      c.Role().String(),
+		AddedAt:   c.AddedAt(),
+	}
+}
+
+// BatchCollaboratorToDto возвращает соавторов бота, первым из которых является его автор.
+func BatchCollaboratorToDto(bot *bots.Bot, cs []*bots.Collaborator) []Collaborator {
+	res := make([]Collaborator, 0, len(cs)+1)
+	res = append(res, Collaborator{
+		AccountID: string(bot.Author()),
+		Role:      bots.Owner.String(),
+		AddedAt:   bot.CreatedAt(),
+	})
+	for _, c := range cs {
+		res = append(res, CollaboratorToDto(c))
+	}
+	return res
+}
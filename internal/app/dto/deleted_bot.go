@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+)
+
+type DeletedBot struct {
+	Bot       Bot
+	DeletedAt time.Time
+}
+
+func DeletedBotToDto(b port.DeletedBot) DeletedBot {
+	return DeletedBot{
+		Bot:       BotToDto(b.Bot),
+		DeletedAt: b.DeletedAt,
+	}
+}
+
+func BatchDeletedBotToDto(bs []port.DeletedBot) []DeletedBot {
+	res := make([]DeletedBot, 0, len(bs))
+	for _, b := range bs {
+		res = append(res, DeletedBotToDto(b))
+	}
+	return res
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"strconv"
+
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
@@ -10,6 +12,7 @@ type Node struct {
 	Edges    []Edge
 	Messages []Message
 	Options  []string
+	Request  *NodeRequest // Запрос узла-запроса или nil для обычного узла
 }
 
 func nodeFromDTO(dto Node) (bots.Node, error) {
@@ -39,6 +42,23 @@ func nodeFromDTO(dto Node) (bots.Node, error) {
 		errs.ExtendOrAppend(err)
 	}
 
+	if dto.Request != nil {
+		if len(dto.Edges) > 0 || len(dto.Options) > 0 {
+			errs.Append(bots.NewInvalidInputError(
+				"request-node-with-edges", "expected request node without edges and options",
+				"field", "request", "state", strconv.Itoa(dto.State),
+			))
+		}
+		req, err2 := new(nodeRequestBuilder).WithState(dto.State).Build(*dto.Request)
+		if err2 != nil {
+			errs.ExtendOrAppend(err2)
+		}
+		if errs.HasError() {
+			return bots.Node{}, &errs
+		}
+		return bots.NewRequestNode(state, dto.Title, ms, req)
+	}
+
 	if errs.HasError() {
 		return bots.Node{}, &errs
 	}
@@ -69,6 +89,7 @@ func nodeToDTO(node bots.Node) Node {
 		Edges:    batchEdgesToDTO(node.Edges()),
 		Messages: batchMessagesToDTO(node.Messages()),
 		Options:  batchOptionsToDTO(node.Options()),
+		Request:  nodeRequestToDTO(node.Request()),
 	}
 }
 
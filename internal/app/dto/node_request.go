@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type NodeRequest struct {
+	Method    string
+	URL       string
+	Headers   map[string]string
+	Body      string
+	Save      map[string]string
+	OnSuccess int
+	OnFailure int
+}
+
+type nodeRequestBuilder struct {
+	state int
+}
+
+func (b *nodeRequestBuilder) WithState(state int) *nodeRequestBuilder {
+	b.state = state
+	return b
+}
+
+func (b *nodeRequestBuilder) Build(dto NodeRequest) (bots.Request, error) {
+	var errs bots.MultiError
+
+	onSuccess, err := bots.NewState(dto.OnSuccess)
+	if err != nil {
+		errs.Append(b.enrichError(err, "onSuccess"))
+	}
+	onFailure, err := bots.NewState(dto.OnFailure)
+	if err != nil {
+		errs.Append(b.enrichError(err, "onFailure"))
+	}
+	if errs.HasError() {
+		return bots.Request{}, &errs
+	}
+
+	req, err := bots.NewRequest(dto.Method, dto.URL, dto.Headers, dto.Body, dto.Save, onSuccess, onFailure)
+	if err != nil {
+		return bots.Request{}, b.enrichError(err, "")
+	}
+	return req, nil
+}
+
+func (b *nodeRequestBuilder) enrichError(err error, field string) error {
+	var iiErr bots.InvalidInputError
+	if errors.As(err, &iiErr) {
+		if b.state != 0 {
+			iiErr.Details["state"] = strconv.Itoa(b.state)
+		}
+		if field != "" {
+			iiErr.Details["field"] = field
+		}
+		return iiErr
+	}
+	return err
+}
+
+func nodeRequestToDTO(req bots.Request) *NodeRequest {
+	if req.IsZero() {
+		return nil
+	}
+	return &NodeRequest{
+		Method:    req.Method(),
+		URL:       req.URL(),
+		Headers:   req.Headers(),
+		Body:      req.Body(),
+		Save:      req.Save(),
+		OnSuccess: req.OnSuccess().Int(),
+		OnFailure: req.OnFailure().Int(),
+	}
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type ParticipantData struct {
+	BotID     string
+	ChatID    int64
+	UserID    int64
+	BlockedAt time.Time // Нулевое время означает, что пользователь не блокировал бота
+	Profile   Profile
+	Threads   []ParticipantThread
+	Records   []ParticipantRecord
+}
+
+// ParticipantRecord есть копия данных участника вне тредов, см. port.ParticipantRecord.
+type ParticipantRecord struct {
+	Source    string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type ParticipantThread struct {
+	ID        string
+	Key       string
+	State     int
+	StartedAt time.Time
+	Answers   map[int]Message
+	Vars      map[string]string
+}
+
+func ParticipantDataToDto(d port.ParticipantData) ParticipantData {
+	id := d.Participant.ID()
+	threads := make([]ParticipantThread, len(d.Threads))
+	for i, thread := range d.Threads {
+		threads[i] = participantThreadToDto(thread)
+	}
+	records := make([]ParticipantRecord, len(d.Records))
+	for i, r := range d.Records {
+		records[i] = ParticipantRecord(r)
+	}
+	return ParticipantData{
+		BotID:     string(id.BotID()),
+		ChatID:    int64(id.ChatID()),
+		UserID:    int64(id.UserID()),
+		BlockedAt: d.Participant.BlockedAt(),
+		Profile:   ProfileToDTO(d.Participant.Profile()),
+		Threads:   threads,
+		Records:   records,
+	}
+}
+
+func BatchParticipantDataToDto(ds []port.ParticipantData) []ParticipantData {
+	res := make([]ParticipantData, 0, len(ds))
+	for _, d := range ds {
+		res = append(res, ParticipantDataToDto(d))
+	}
+	return res
+}
+
+func participantThreadToDto(thread *bots.Thread) ParticipantThread {
+	answers := make(map[int]Message)
+	for state, msg := range thread.Answers() {
+		answers[state.Int()] = MessageToDTO(msg)
+	}
+	return ParticipantThread{
+		ID:        string(thread.ID()),
+		Key:       string(thread.Key()),
+		State:     thread.State().Int(),
+		StartedAt: thread.StartedAt(),
+		Answers:   answers,
+		Vars:      thread.Vars(),
+	}
+}
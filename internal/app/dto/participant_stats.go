@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+
+type ParticipantStats struct {
+	Total   int
+	Blocked int
+}
+
+func ParticipantStatsToDTO(stats bots.ParticipantStats) ParticipantStats {
+	return ParticipantStats{
+		Total:   stats.Total(),
+		Blocked: stats.Blocked(),
+	}
+}
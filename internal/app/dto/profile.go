@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+
+type Profile struct {
+	Username     string
+	FirstName    string
+	LastName     string
+	LanguageCode string
+}
+
+func ProfileFromDTO(p Profile) bots.Profile {
+	return bots.NewProfile(bots.Username(p.Username), p.FirstName, p.LastName, p.LanguageCode)
+}
+
+func ProfileToDTO(p bots.Profile) Profile {
+	return Profile{
+		Username:     string(p.Username()),
+		FirstName:    p.FirstName(),
+		LastName:     p.LastName(),
+		LanguageCode: p.LanguageCode(),
+	}
+}
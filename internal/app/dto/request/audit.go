@@ -0,0 +1,109 @@
+package request
+
+// Команды, выполняемые пользователями, реализуют decorator.Auditable и записываются в журнал аудита.
+// Остальные команды реализуют decorator.Unaudited (см. конец файла).
+
+func (cmd CreateAPIKeyCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, ""
+}
+
+func (cmd RevokeAPIKeyCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, ""
+}
+
+func (cmd CreateBotCommand) AuditTarget() (string, string) {
+	return cmd.Author, cmd.BotID
+}
+
+func (cmd UpdateBotCommand) AuditTarget() (string, string) {
+	return cmd.Author, cmd.BotID
+}
+
+func (cmd DeleteBotCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd RestoreBotCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd PurgeBotCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd EnableBotCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd DisableBotCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd StartCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd StopCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd MailingCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd CreateWebhookCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd UpdateWebhookCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd DeleteWebhookCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd InviteCollaboratorCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd UpdateCollaboratorCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd RemoveCollaboratorCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+func (cmd EraseParticipantDataCommand) AuditTarget() (string, string) {
+	return cmd.AccountID, cmd.BotID
+}
+
+// Обработку обновлений Telegram и доставку сообщений выполняет не пользователь, а сервис.
+
+func (EntryCommand) Unaudited() {}
+
+func (ProcessCommand) Unaudited() {}
+
+func (DeliverMessageCommand) Unaudited() {}
+
+// Доставка событий на Webhook не меняет их настроек: изменения Webhook записываются своими командами.
+
+func (EnqueueWebhookEventCommand) Unaudited() {}
+
+func (DeliverWebhookCommand) Unaudited() {}
+
+// Фоновые задачи сервиса.
+
+func (StartEnabledBotsCommand) Unaudited() {}
+
+func (PurgeDeletedBotsCommand) Unaudited() {}
+
+// Использование API-ключа отмечается при каждом запросе; сам запрос записывается своей командой.
+
+func (TouchAPIKeyCommand) Unaudited() {}
+
+// Стирание данных пользователя выполняет оператор из командной строки; журнал аудита при этом сам
+// очищается от ID пользователя, и запись о стирании его бы восстановила.
+
+func (EraseUserDataCommand) Unaudited() {}
@@ -0,0 +1,11 @@
+package request
+
+type AuthenticateAPIKeyQuery struct {
+	Key string
+}
+
+// Redact возвращает копию без секретов для записи в журнал: ключ скрыт.
+func (q AuthenticateAPIKeyQuery) Redact() any {
+	q.Key = redact(q.Key)
+	return q
+}
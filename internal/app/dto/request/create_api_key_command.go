@@ -0,0 +1,23 @@
+package request
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+)
+
+type CreateAPIKeyCommand struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	KeyID     string
+	Secret    string
+	Name      string
+	KeyScope  dto.APIKeyScope
+	ExpiresAt time.Time
+}
+
+// Redact возвращает копию без секретов для записи в журнал: секрет ключа скрыт.
+func (cmd CreateAPIKeyCommand) Redact() any {
+	cmd.Secret = redact(cmd.Secret)
+	return cmd
+}
@@ -6,10 +6,19 @@ import (
 )
 
 type CreateBotCommand struct {
-	BotID  string
-	Token  string
-	Author int64
-	Script dto.Script
+	BotID      string
+	Token      string
+	Author     string
+	Scope      *dto.APIKeyScope
+	ChatPolicy string // Пустая строка означает политику по умолчанию
+	Script     dto.Script
+}
+
+// Redact возвращает копию без секретов для записи в журнал: токен и заголовки узлов-запросов скрыты.
+func (cmd CreateBotCommand) Redact() any {
+	cmd.Token = bots.Token(cmd.Token).Redacted()
+	cmd.Script = cmd.Script.Redacted()
+	return cmd
 }
 
 func BotFromCommand(cmd CreateBotCommand) (*bots.Bot, error) {
@@ -17,5 +26,21 @@ func BotFromCommand(cmd CreateBotCommand) (*bots.Bot, error) {
 	if err != nil {
 		return nil, err
 	}
-	return bots.NewBot(bots.BotID(cmd.BotID), bots.Token(cmd.Token), bots.UserID(cmd.Author), script)
+	bot, err := bots.NewBot(bots.BotID(cmd.BotID), bots.Token(cmd.Token), bots.AccountID(cmd.Author), script)
+	if err != nil {
+		return nil, err
+	}
+	return bot, applyChatPolicy(bot, cmd.ChatPolicy)
+}
+
+func applyChatPolicy(bot *bots.Bot, s string) error {
+	if s == "" {
+		return nil
+	}
+	policy, err := bots.NewChatPolicy(s)
+	if err != nil {
+		return err
+	}
+	bot.SetChatPolicy(policy)
+	return nil
 }
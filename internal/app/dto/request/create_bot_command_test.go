@@ -0,0 +1,35 @@
+package request_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+)
+
+func TestCreateBotCommand_Redact(t *testing.T) {
+	cmd := request.CreateBotCommand{
+		BotID: "bot",
+		Token: "123456:secret",
+		Script: dto.Script{
+			Nodes: []dto.Node{
+				{State: 1, Title: "Greeting"},
+				{State: 2, Title: "Lookup", Request: &dto.NodeRequest{
+					Method:  "GET",
+					URL:     "https://crm.example.com/students",
+					Headers: map[string]string{"Authorization": "Bearer crm-key"},
+				}},
+			},
+		},
+	}
+
+	redacted, ok := cmd.Redact().(request.CreateBotCommand)
+	require.True(t, ok)
+	require.Equal(t, "123456:***", redacted.Token)
+	require.Equal(t, map[string]string{"Authorization": "***"}, redacted.Script.Nodes[1].Request.Headers)
+
+	// Исходная команда не изменяется: по ней создаётся бот
+	require.Equal(t, "Bearer crm-key", cmd.Script.Nodes[1].Request.Headers["Authorization"])
+}
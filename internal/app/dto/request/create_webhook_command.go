@@ -0,0 +1,19 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type CreateWebhookCommand struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	WebhookID string
+	URL       string
+	Secret    string
+	Events    []string
+}
+
+// Redact возвращает копию без секретов для записи в журнал: секрет подписи скрыт.
+func (cmd CreateWebhookCommand) Redact() any {
+	cmd.Secret = redact(cmd.Secret)
+	return cmd
+}
@@ -1,5 +1,9 @@
 package request
 
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
 type DeleteBotCommand struct {
-	BotID string
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
 }
@@ -0,0 +1,13 @@
+package request
+
+type DeliverMessageCommand struct {
+	// OutboxID есть идентификатор сообщения в очереди на отправку.
+	OutboxID int64
+	BotID    string
+	ChatID   int64
+	UserID   int64
+	Text     string
+	Options  []string
+	// Attempts есть количество уже совершённых неудачных попыток отправки.
+	Attempts int
+}
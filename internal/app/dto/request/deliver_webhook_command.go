@@ -0,0 +1,12 @@
+package request
+
+type DeliverWebhookCommand struct {
+	// DeliveryID есть идентификатор доставки в журнале.
+	DeliveryID int64
+	BotID      string
+	WebhookID  string
+	Event      string
+	Payload    []byte
+	// Attempts есть количество уже совершённых неудачных попыток доставки.
+	Attempts int
+}
@@ -0,0 +1,10 @@
+package request
+
+type EnqueueWebhookEventCommand struct {
+	BotID string
+	// EventUUID есть UUID сообщения о событии: повторная постановка того же события не создаёт доставок.
+	EventUUID string
+	Event     string
+	// Payload есть готовое к отправке JSON-тело запроса.
+	Payload []byte
+}
@@ -0,0 +1,20 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type EraseParticipantDataCommand struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	UserID    int64
+	// Anonymize оставляет треды и ответы пользователя, но отвязывает их от него и заменяет тексты
+	// ответов, вместо удаления.
+	Anonymize bool
+}
+
+// Redact возвращает копию для записи в журнал: ID пользователя, данные которого удаляются, не должен
+// оставаться в журнале аудита.
+func (cmd EraseParticipantDataCommand) Redact() any {
+	cmd.UserID = 0
+	return cmd
+}
@@ -0,0 +1,19 @@
+package request_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+)
+
+func TestEraseParticipantDataCommand_Redact(t *testing.T) {
+	cmd := request.EraseParticipantDataCommand{AccountID: "author", BotID: "bot", UserID: 123456789}
+
+	redacted, ok := cmd.Redact().(request.EraseParticipantDataCommand)
+	require.True(t, ok)
+	require.Zero(t, redacted.UserID)
+	require.Equal(t, "bot", redacted.BotID)
+	require.Equal(t, int64(123456789), cmd.UserID)
+}
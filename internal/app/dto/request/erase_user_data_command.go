@@ -0,0 +1,11 @@
+package request
+
+// EraseUserDataCommand выполняется администратором сервиса без проверки доступа к ботам. Пустой BotID
+// означает все боты, в том числе удалённые.
+type EraseUserDataCommand struct {
+	UserID int64
+	BotID  string
+	// Anonymize оставляет треды и ответы пользователя, но отвязывает их от него и заменяет тексты
+	// ответов, вместо удаления.
+	Anonymize bool
+}
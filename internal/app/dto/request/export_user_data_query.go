@@ -0,0 +1,8 @@
+package request
+
+// ExportUserDataQuery выполняется администратором сервиса без проверки доступа к ботам. Пустой BotID
+// означает все боты, в том числе удалённые.
+type ExportUserDataQuery struct {
+	UserID int64
+	BotID  string
+}
@@ -0,0 +1,8 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetAPIKeysQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+}
@@ -0,0 +1,12 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetAuditLogQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	Action    string // Пустая строка означает все действия
+	Before    int64  // ID записи, после которой продолжается выборка; 0 - с самой новой
+	Limit     int    // Неположительное значение означает ограничение по умолчанию
+}
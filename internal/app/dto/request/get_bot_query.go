@@ -1,5 +1,9 @@
 package request
 
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
 type GetBotQuery struct {
-	ID string
+	AccountID string
+	Scope     *dto.APIKeyScope
+	ID        string
 }
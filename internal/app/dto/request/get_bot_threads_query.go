@@ -0,0 +1,22 @@
+package request
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+)
+
+// GetBotThreadsQuery запрашивает страницу тредов бота. Нулевые значения фильтров не ограничивают выборку.
+type GetBotThreadsQuery struct {
+	AccountID     string
+	Scope         *dto.APIKeyScope
+	BotID         string
+	EntryKey      string
+	StartedAfter  time.Time
+	StartedBefore time.Time
+	Completed     *bool
+	Contains      string
+	Ascending     bool   // По умолчанию треды упорядочены от поздних к ранним
+	Cursor        string // Курсор из ответа на запрос предыдущей страницы; пустой - с первой страницы
+	Limit         int    // Неположительное значение означает ограничение по умолчанию
+}
@@ -0,0 +1,10 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetParticipantDataQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	UserID    int64
+}
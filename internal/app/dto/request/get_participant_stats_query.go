@@ -0,0 +1,9 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetParticipantStatsQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+}
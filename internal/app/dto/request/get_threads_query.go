@@ -1,5 +0,0 @@
-package request
-
-type GetThreadsQuery struct {
-	BotID string
-}
@@ -1,5 +1,8 @@
 package request
 
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
 type GetUserBotsQuery struct {
-	UserID int64
+	Author string
+	Scope  *dto.APIKeyScope
 }
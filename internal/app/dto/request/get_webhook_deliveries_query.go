@@ -0,0 +1,11 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetWebhookDeliveriesQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	WebhookID string
+	Limit     int // Неположительное значение означает ограничение по умолчанию
+}
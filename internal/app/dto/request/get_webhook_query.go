@@ -0,0 +1,10 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetWebhookQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	WebhookID string
+}
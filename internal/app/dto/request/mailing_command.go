@@ -1,7 +1,11 @@
 package request
 
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
 type MailingCommand struct {
-	BotID    string
-	EntryKey string
-	Users    []int64
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	EntryKey  string
+	Users     []int64
 }
@@ -3,7 +3,13 @@ package request
 import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 
 type ProcessCommand struct {
-	BotID   string
-	UserID  int64
-	Message dto.Message
+	BotID string
+	// UpdateID есть номер обновления Telegram; 0, если команда вызвана не обновлением.
+	UpdateID  int64
+	ChatID    int64
+	UserID    int64
+	Group     bool
+	Addressed bool
+	Profile   dto.Profile
+	Message   dto.Message
 }
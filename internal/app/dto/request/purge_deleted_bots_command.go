@@ -0,0 +1,8 @@
+package request
+
+import "time"
+
+// PurgeDeletedBotsCommand окончательно удаляет ботов, удалённых раньше момента DeletedBefore.
+type PurgeDeletedBotsCommand struct {
+	DeletedBefore time.Time
+}
@@ -0,0 +1,12 @@
+package request
+
+// redactedSecret заменяет секреты в командах и запросах при записи в журнал.
+const redactedSecret = "***"
+
+// redact скрывает непустой секрет; пустой остаётся пустым, чтобы в журнале было видно его отсутствие.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedSecret
+}
@@ -0,0 +1,14 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+// StreamThreadsQuery выгружает все треды бота, не загружая их в память: Header вызывается один раз со
+// столбцами ответов до первого треда, Row - для каждого треда от поздних к ранним. Ошибка Header или Row
+// прерывает выгрузку.
+type StreamThreadsQuery struct {
+	AccountID string
+	Scope     *dto.APIKeyScope
+	BotID     string
+	Header    func(columns []dto.AnswerColumn) error
+	Row       func(thread dto.Thread) error
+}
@@ -0,0 +1,8 @@
+package request
+
+import "time"
+
+type TouchAPIKeyCommand struct {
+	KeyID  string
+	UsedAt time.Time
+}
@@ -6,10 +6,19 @@ import (
 )
 
 type UpdateBotCommand struct {
-	BotID  string
-	Author int64
-	Token  string
-	Script dto.Script
+	BotID      string
+	Author     string
+	Scope      *dto.APIKeyScope
+	Token      string
+	ChatPolicy string // Пустая строка означает политику по умолчанию
+	Script     dto.Script
+}
+
+// Redact возвращает копию без секретов для записи в журнал: токен и заголовки узлов-запросов скрыты.
+func (cmd UpdateBotCommand) Redact() any {
+	cmd.Token = bots.Token(cmd.Token).Redacted()
+	cmd.Script = cmd.Script.Redacted()
+	return cmd
 }
 
 func BotFromUpdateCommand(cmd UpdateBotCommand) (*bots.Bot, error) {
@@ -17,5 +26,9 @@ func BotFromUpdateCommand(cmd UpdateBotCommand) (*bots.Bot, error) {
 	if err != nil {
 		return nil, err
 	}
-	return bots.NewBot(bots.BotID(cmd.BotID), bots.Token(cmd.Token), bots.UserID(cmd.Author), script)
+	bot, err := bots.NewBot(bots.BotID(cmd.BotID), bots.Token(cmd.Token), bots.AccountID(cmd.Author), script)
+	if err != nil {
+		return nil, err
+	}
+	return bot, applyChatPolicy(bot, cmd.ChatPolicy)
 }
@@ -0,0 +1,11 @@
+package request
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type UpdateCollaboratorCommand struct {
+	AccountID      string
+	Scope          *dto.APIKeyScope
+	BotID          string
+	CollaboratorID string
+	Role           string
+}
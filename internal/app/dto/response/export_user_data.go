@@ -0,0 +1,5 @@
+package response
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type ExportUserDataResponse = []dto.ParticipantData
@@ -2,4 +2,4 @@ package response
 
 import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 
-type GetThreadsResponse = []dto.Thread
+type GetAPIKeysResponse = []dto.APIKey
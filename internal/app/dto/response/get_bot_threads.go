@@ -0,0 +1,9 @@
+package response
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+
+type GetBotThreadsResponse struct {
+	Threads []dto.Thread
+	// NextCursor пуст, если страница последняя.
+	NextCursor string
+}
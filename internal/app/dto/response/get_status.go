@@ -1,3 +1,28 @@
 package response
 
-type GetStatusResponse = string
+import "time"
+
+type GetStatusResponse struct {
+	Status  string
+	Replica string
+	Mode    string
+
+	// HeartbeatAt есть время последнего продления аренды бота репликой Replica.
+	HeartbeatAt time.Time
+	// StatsReplica пуст, если счётчики реплики Replica недоступны.
+	StatsReplica string
+
+	TelegramID int64
+	Username   string
+
+	// Нулевые отметки времени означают отсутствие события.
+	StartedAt        time.Time
+	LastUpdateAt     time.Time
+	UpdatesProcessed int
+	MessagesSent     int
+	Restarts         int
+
+	// LastError пуст, если ошибок опроса не было.
+	LastError   string
+	LastErrorAt time.Time
+}
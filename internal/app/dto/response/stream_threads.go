@@ -0,0 +1,6 @@
+package response
+
+type StreamThreadsResponse struct {
+	// Threads есть количество выгруженных тредов.
+	Threads int
+}
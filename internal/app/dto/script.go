@@ -7,6 +7,28 @@ type Script struct {
 	Entries []Entry
 }
 
+// redactedHeader заменяет скрытые значения заголовков узлов-запросов.
+const redactedHeader = "***"
+
+// Redacted возвращает копию сценария для записи в журнал или для пользователя, который не может изменять
+// бота: значения заголовков узлов-запросов, в которых передаются ключи внешних систем, скрыты.
+func (s Script) Redacted() Script {
+	nodes := make([]Node, len(s.Nodes))
+	for i, node := range s.Nodes {
+		if node.Request != nil && len(node.Request.Headers) > 0 {
+			req := *node.Request
+			req.Headers = make(map[string]string, len(node.Request.Headers))
+			for name := range node.Request.Headers {
+				req.Headers[name] = redactedHeader
+			}
+			node.Request = &req
+		}
+		nodes[i] = node
+	}
+	s.Nodes = nodes
+	return s
+}
+
 func ScriptFromDTO(dto Script) (bots.Script, error) {
 	nodes, err := batchNodesFromDTO(dto.Nodes)
 	if err != nil {
@@ -9,21 +9,33 @@ import (
 type Thread struct {
 	ID        string
 	Key       string
+	UserID    int64
+	State     int
 	StartedAt time.Time
 	Username  string
 	Answers   map[int]Message
+	Completed bool
 }
 
-func ThreadToDto(thread *bots.Thread, username string) Thread {
+// AnswerColumn есть столбец таблицы ответов: узел, на который есть хотя бы один ответ. Title пуст, если
+// узла уже нет в сценарии.
+type AnswerColumn struct {
+	State int
+	Title string
+}
+
+func ThreadToDto(thread bots.BotThread, username string) Thread {
 	answers := make(map[int]Message)
-	for state, msg := range thread.Answers() {
+	for state, msg := range thread.Thread().Answers() {
 		answers[state.Int()] = MessageToDTO(msg)
 	}
 
 	return Thread{
-		ID:        string(thread.ID()),
-		Key:       string(thread.Key()),
-		StartedAt: thread.StartedAt(),
+		ID:        string(thread.Thread().ID()),
+		Key:       string(thread.Thread().Key()),
+		UserID:    int64(thread.UserID()),
+		State:     thread.Thread().State().Int(),
+		StartedAt: thread.Thread().StartedAt(),
 		Username:  username,
 		Answers:   answers,
 	}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// Webhook не содержит секрета: он известен только создателю подписки.
+type Webhook struct {
+	ID        string
+	BotID     string
+	URL       string
+	Events    []string
+	CreatedAt time.Time
+}
+
+func WebhookToDto(w *bots.Webhook) Webhook {
+	return Webhook{
+		ID:        string(w.ID()),
+		BotID:     string(w.BotID()),
+		URL:       w.URL(),
+		Events:    w.Events(),
+		CreatedAt: w.CreatedAt(),
+	}
+}
+
+func BatchWebhookToDto(ws []*bots.Webhook) []Webhook {
+	res := make([]Webhook, 0, len(ws))
+	for _, w := range ws {
+		res = append(res, WebhookToDto(w))
+	}
+	return res
+}
+
+type WebhookDelivery struct {
+	ID            int64
+	Event         string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	ResponseCode  int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	DeliveredAt   time.Time // Нулевое время означает, что событие ещё не доставлено
+}
+
+func WebhookDeliveryToDto(d port.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:            d.ID,
+		Event:         d.Event,
+		Payload:       d.Payload,
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt,
+		NextAttemptAt: d.NextAttemptAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+}
+
+func BatchWebhookDeliveryToDto(ds []port.WebhookDelivery) []WebhookDelivery {
+	res := make([]WebhookDelivery, 0, len(ds))
+	for _, d := range ds {
+		res = append(res, WebhookDeliveryToDto(d))
+	}
+	return res
+}
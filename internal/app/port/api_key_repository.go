@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type APIKeyProvider interface {
+	// APIKey возвращает API-ключ или ошибку ErrAPIKeyNotFound.
+	APIKey(ctx context.Context, id bots.APIKeyID) (*bots.APIKey, error)
+}
+
+type APIKeyRepository interface {
+	// CreateAPIKey сохраняет новый API-ключ.
+	CreateAPIKey(ctx context.Context, k *bots.APIKey) error
+
+	// AccountAPIKeys возвращает возможно пустой список API-ключей пользователя.
+	AccountAPIKeys(ctx context.Context, account bots.AccountID) ([]*bots.APIKey, error)
+
+	// TouchAPIKey запоминает время последнего использования API-ключа.
+	TouchAPIKey(ctx context.Context, id bots.APIKeyID, usedAt time.Time) error
+
+	// DeleteAPIKey удаляет API-ключ или возвращает ErrAPIKeyNotFound.
+	DeleteAPIKey(ctx context.Context, id bots.APIKeyID) error
+
+	APIKeyProvider
+}
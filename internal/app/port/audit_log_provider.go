@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// AuditRecord есть сохранённая запись журнала аудита (см. decorator.AuditEntry).
+type AuditRecord struct {
+	ID      int64
+	Actor   bots.AccountID
+	Action  string
+	BotID   bots.BotID
+	Payload []byte
+	// Error пуст, если команда выполнена успешно.
+	Error     string
+	CreatedAt time.Time
+}
+
+// AuditLogFilter ограничивает выборку журнала аудита.
+type AuditLogFilter struct {
+	// Action пуст, если нужны записи о всех действиях.
+	Action string
+	// Before есть ID записи, после которой продолжается выборка; 0 - с самой новой записи.
+	Before int64
+	Limit  int
+}
+
+type AuditLogProvider interface {
+	// BotAuditLog возвращает записи журнала аудита бота от новых к старым.
+	BotAuditLog(ctx context.Context, botID bots.BotID, filter AuditLogFilter) ([]AuditRecord, error)
+}
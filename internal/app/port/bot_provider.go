@@ -13,9 +13,14 @@ type BotProvider interface {
 	// Bot возвращает найденного бота или ошибку ErrBotNotFound.
 	Bot(ctx context.Context, id bots.BotID) (*bots.Bot, error)
 
-	// UserBots возвращает возможно пустой список ботов, чьи авторы имеют UserID.
-	UserBots(ctx context.Context, userID bots.UserID) ([]*bots.Bot, error)
+	// UserBots возвращает возможно пустой список ботов, автором или соавтором которых является
+	// пользователь account.
+	UserBots(ctx context.Context, account bots.AccountID) ([]*bots.Bot, error)
 
 	// EnabledBots возвращает все боты, для которых enabled=true.
 	EnabledBots(ctx context.Context) ([]*bots.Bot, error)
+
+	// BotByTelegramID возвращает неудалённого бота, токен которого принадлежит аккаунту Telegram с
+	// данным ID, или ошибку ErrBotNotFound.
+	BotByTelegramID(ctx context.Context, telegramID int64) (*bots.Bot, error)
 }
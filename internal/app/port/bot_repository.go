@@ -2,12 +2,18 @@ package port
 
 import (
 	"context"
+	"errors"
 
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
+// ErrBotIDTaken возвращается при попытке сохранить бота с ID удалённого бота: ID остаётся за ним, пока
+// бот не будет восстановлен или окончательно удалён.
+var ErrBotIDTaken = errors.New("bot id is taken by a deleted bot")
+
 type BotRepository interface {
-	// UpsertBot создаёт нового бота или обновляет существующий с данным botID.
+	// UpsertBot создаёт нового бота или обновляет существующий с данным botID. Возвращает ErrBotIDTaken,
+	// если ID принадлежит удалённому боту.
 	UpsertBot(ctx context.Context, bot *bots.Bot) error
 	DeleteBot(ctx context.Context, id bots.BotID) error
 
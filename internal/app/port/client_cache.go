@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type ClientCache interface {
+	// Invalidate сбрасывает закэшированный клиент Telegram для токена.
+	Invalidate(ctx context.Context, token bots.Token)
+}
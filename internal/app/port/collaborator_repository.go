@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+var (
+	ErrCollaboratorNotFound      = errors.New("collaborator not found")
+	ErrCollaboratorAlreadyExists = errors.New("collaborator already exists")
+)
+
+type CollaboratorProvider interface {
+	// Collaborator возвращает соавтора бота или ошибку ErrCollaboratorNotFound.
+	Collaborator(ctx context.Context, botID bots.BotID, account bots.AccountID) (*bots.Collaborator, error)
+
+	// BotCollaborators возвращает возможно пустой список соавторов бота.
+	BotCollaborators(ctx context.Context, botID bots.BotID) ([]*bots.Collaborator, error)
+}
+
+type CollaboratorRepository interface {
+	// UpsertCollaborator добавляет соавтора бота или заменяет его роль.
+	UpsertCollaborator(ctx context.Context, c *bots.Collaborator) error
+
+	// DeleteCollaborator удаляет соавтора бота или возвращает ErrCollaboratorNotFound.
+	DeleteCollaborator(ctx context.Context, botID bots.BotID, account bots.AccountID) error
+
+	CollaboratorProvider
+}
@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// DeletedBot - бот, удалённый методом BotRepository.DeleteBot, но ещё не удалённый окончательно.
+type DeletedBot struct {
+	Bot       *bots.Bot
+	DeletedAt time.Time
+}
+
+type DeletedBotRepository interface {
+	// DeletedBot возвращает удалённого бота или ошибку ErrBotNotFound.
+	DeletedBot(ctx context.Context, id bots.BotID) (DeletedBot, error)
+
+	// UserDeletedBots возвращает возможно пустой список удалённых ботов, автором или соавтором которых
+	// является пользователь account, от недавно удалённых к давно удалённым.
+	UserDeletedBots(ctx context.Context, account bots.AccountID) ([]DeletedBot, error)
+
+	// BotsDeletedBefore возвращает ID ботов, удалённых раньше момента t.
+	BotsDeletedBefore(ctx context.Context, t time.Time) ([]bots.BotID, error)
+
+	// RestoreBot снимает с удалённого бота отметку об удалении и выключает его. Возвращает
+	// ErrBotNotFound, если удалённого бота с данным ID нет.
+	RestoreBot(ctx context.Context, id bots.BotID) error
+
+	// PurgeBot безвозвратно удаляет удалённого бота вместе со сценарием, участниками, тредами, ответами,
+	// Webhook и соавторами. Возвращает ErrBotNotFound, если удалённого бота с данным ID нет.
+	PurgeBot(ctx context.Context, id bots.BotID) error
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type EventPublisher interface {
+	// Publish публикует доменные события для внешних подписчиков. Вызывается после того,
+	// как изменения, породившие события, сохранены. События участников публикуются
+	// OutboxRepository вместе с участником.
+	Publish(ctx context.Context, events ...bots.Event) error
+}
@@ -7,9 +7,22 @@ import (
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
-var ErrRunningInstanceNotFound = errors.New("running instance not found")
+var (
+	ErrRunningInstanceNotFound = errors.New("running instance not found")
+	// ErrInstanceChangeTimeout возвращается, если реплика, опрашивающая бота, не применила запуск или
+	// остановку вовремя. Запрос сохранён и будет применён владельцем аренды или репликой, перехватившей её.
+	ErrInstanceChangeTimeout = errors.New("bot instance change is not confirmed by its replica")
+)
 
+// InstanceManager управляет экземплярами ботов на всех репликах сервиса: если бота опрашивает
+// другая реплика, запрос передаётся ей, и метод возвращается после его применения.
 type InstanceManager interface {
+	// Start запускает бота или перезапускает его с токеном token.
 	Start(ctx context.Context, id bots.BotID, token bots.Token) error
+
+	// Stop останавливает бота. Возвращает ErrRunningInstanceNotFound, если бот не запущен ни одной репликой.
 	Stop(ctx context.Context, id bots.BotID) error
+
+	// Reload перезапускает бота с токеном token, если он запущен; незапущенный бот не запускается.
+	Reload(ctx context.Context, id bots.BotID, token bots.Token) error
 }
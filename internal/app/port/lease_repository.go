@@ -0,0 +1,77 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+var (
+	ErrLeaseHeld     = errors.New("bot lease is held by another replica")
+	ErrLeaseNotFound = errors.New("bot lease not found")
+)
+
+// Lease есть аренда бота, в том числе истёкшая.
+type Lease struct {
+	Replica string
+	// RenewedAt есть время последнего продления аренды репликой-владельцем.
+	RenewedAt time.Time
+	ExpiresAt time.Time
+}
+
+// LeaseChange есть изменение экземпляра бота, запрошенное у реплики-владельца аренды.
+type LeaseChange struct {
+	BotID bots.BotID
+	// Stop требует остановить бота и освободить аренду.
+	Stop bool
+	// Generation есть номер запрошенного перезапуска; после перезапуска он подтверждается ConfirmLeaseChange.
+	Generation int64
+}
+
+// LeaseRepository распределяет ботов между репликами сервиса. Бот опрашивает только реплика,
+// владеющая его арендой (lease); аренда продлевается владельцем и по истечении ttl может быть
+// перехвачена другой репликой.
+type LeaseRepository interface {
+	// AcquireLease захватывает или продлевает аренду бота для реплики replica.
+	// Возвращает ErrLeaseHeld, если действующей арендой владеет другая реплика.
+	AcquireLease(ctx context.Context, id bots.BotID, replica string, ttl time.Duration) error
+
+	// RenewLeases продлевает все аренды реплики и возвращает ботов, которыми она всё ещё владеет.
+	RenewLeases(ctx context.Context, replica string, ttl time.Duration) ([]bots.BotID, error)
+
+	// TakeOverExpiredLeases захватывает истёкшие аренды других реплик и возвращает захваченных ботов.
+	TakeOverExpiredLeases(ctx context.Context, replica string, ttl time.Duration) ([]bots.BotID, error)
+
+	// ReleaseLease освобождает аренду бота, если ею владеет реплика replica.
+	ReleaseLease(ctx context.Context, id bots.BotID, replica string) error
+
+	// ExpireLease завершает аренду бота, если ею владеет реплика replica, оставляя её для перехвата:
+	// другие реплики запустят бота при следующем TakeOverExpiredLeases.
+	ExpireLease(ctx context.Context, id bots.BotID, replica string) error
+
+	// RequestLeaseRestart запрашивает у владельца действующей аренды перезапуск бота с токеном из хранилища
+	// и возвращает номер запроса. Возвращает ErrLeaseNotFound, если действующей аренды нет.
+	RequestLeaseRestart(ctx context.Context, id bots.BotID) (int64, error)
+
+	// RequestLeaseStop запрашивает у владельца действующей аренды остановку бота.
+	// Возвращает ErrLeaseNotFound, если действующей аренды нет.
+	RequestLeaseStop(ctx context.Context, id bots.BotID) error
+
+	// LeaseChanges возвращает изменения, запрошенные у реплики replica и ещё не применённые ею.
+	LeaseChanges(ctx context.Context, replica string) ([]LeaseChange, error)
+
+	// ConfirmLeaseChange подтверждает, что реплика replica перезапустила бота по запросу generation.
+	ConfirmLeaseChange(ctx context.Context, id bots.BotID, replica string, generation int64) error
+
+	// LeaseRestartApplied сообщает, перезапущен ли бот по запросу generation или после него.
+	// Возвращает ErrLeaseNotFound, если действующей аренды нет.
+	LeaseRestartApplied(ctx context.Context, id bots.BotID, generation int64) (bool, error)
+
+	// Lease возвращает аренду бота, даже если она истекла, или ErrLeaseNotFound, если её нет.
+	Lease(ctx context.Context, id bots.BotID) (Lease, error)
+
+	// LeaseOwner возвращает реплику, владеющую действующей арендой бота, или false, если таковой нет.
+	LeaseOwner(ctx context.Context, id bots.BotID) (string, bool, error)
+}
@@ -10,5 +10,6 @@ import (
 var ErrUserBlockedBot = errors.New("user blocked bot")
 
 type MessageSender interface {
-	Send(ctx context.Context, token bots.Token, userID bots.UserID, msg bots.BotMessage) error
+	// Send отправляет сообщение в чат chatID. Для личного чата chatID совпадает с UserID.
+	Send(ctx context.Context, token bots.Token, chatID bots.ChatID, msg bots.BotMessage) error
 }
@@ -0,0 +1,17 @@
+package port
+
+import "github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+
+// Origin описывает, откуда пришло сообщение пользователя.
+type Origin struct {
+	// UpdateID есть номер обновления Telegram, в котором пришло сообщение.
+	UpdateID bots.UpdateID
+	ChatID   bots.ChatID
+	UserID   bots.UserID
+	Profile  bots.Profile
+	// Group истинно для групп и супергрупп.
+	Group bool
+	// Addressed истинно, если сообщение в группе адресовано боту: является командой,
+	// упоминанием бота или ответом на его сообщение. В личных чатах не учитывается.
+	Addressed bool
+}
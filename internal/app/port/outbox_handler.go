@@ -0,0 +1,8 @@
+package port
+
+import "context"
+
+type OutboxHandler interface {
+	// Deliver отправляет захваченное сообщение из очереди и фиксирует результат попытки.
+	Deliver(ctx context.Context, msg OutboxMessage) error
+}
@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// OutboxMessage есть сообщение участнику, ожидающее отправки.
+type OutboxMessage struct {
+	ID            int64
+	ParticipantID bots.ParticipantID
+	Message       bots.BotMessage
+	// Attempts есть количество уже совершённых неудачных попыток отправки.
+	Attempts int
+}
+
+type OutboxRepository interface {
+	// UpdateOrCreateParticipantWithOutbox работает как ParticipantRepository.UpdateOrCreateParticipant,
+	// но в той же транзакции сохраняет возвращённые updateFn сообщения в очередь на отправку, а события
+	// участника (Participant.Events) - в очередь на публикацию.
+	UpdateOrCreateParticipantWithOutbox(
+		ctx context.Context,
+		id bots.ParticipantID,
+		updateFn func(context.Context, *bots.Participant) ([]bots.BotMessage, error),
+	) error
+
+	// ClaimOutboxMessages захватывает на время lockFor не более limit сообщений, готовых к отправке.
+	// Из каждого чата захватывается только самое раннее неотправленное сообщение, поэтому сообщения
+	// одного чата отправляются строго по порядку.
+	ClaimOutboxMessages(ctx context.Context, limit int, lockFor time.Duration) ([]OutboxMessage, error)
+
+	// DeleteOutboxMessage удаляет отправленное сообщение из очереди.
+	DeleteOutboxMessage(ctx context.Context, id int64) error
+
+	// RetryOutboxMessage откладывает повторную попытку отправки сообщения до момента at.
+	RetryOutboxMessage(ctx context.Context, id int64, at time.Time, reason string) error
+
+	// FailOutboxMessage окончательно отмечает сообщение неотправленным.
+	FailOutboxMessage(ctx context.Context, id int64, reason string) error
+
+	// FailChatOutboxMessages окончательно отмечает неотправленными все ожидающие сообщения
+	// участника, например, если он заблокировал бота.
+	FailChatOutboxMessages(ctx context.Context, id bots.ParticipantID, reason string) error
+}
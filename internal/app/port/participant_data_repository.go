@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+var ErrParticipantNotFound = errors.New("participant not found")
+
+// ParticipantData - всё, что хранится об участнике бота: профиль, отметка о блокировке, все треды с
+// ответами, от ранних к поздним, и копии его данных вне тредов.
+type ParticipantData struct {
+	Participant *bots.Participant
+	Threads     []*bots.Thread
+	Records     []ParticipantRecord
+}
+
+// ParticipantRecord есть копия данных участника вне его тредов: событие в таблице Watermill, тело
+// запроса в очереди доставки Webhook или запись журнала аудита.
+type ParticipantRecord struct {
+	// Source есть таблица, в которой хранится запись.
+	Source string
+	// Payload есть JSON записи.
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// ParticipantDataRepository отвечает на запросы пользователей Telegram о хранимых о них данных. Пустой
+// botID означает все боты, в том числе удалённые.
+type ParticipantDataRepository interface {
+	// ParticipantData возвращает возможно пустой список данных пользователя userID во всех чатах бота botID.
+	ParticipantData(ctx context.Context, userID bots.UserID, botID bots.BotID) ([]ParticipantData, error)
+
+	// EraseParticipantData удаляет участников - пользователя userID во всех чатах бота botID - вместе с
+	// профилями, тредами, ответами, неотправленными сообщениями, событиями и телами Webhook, а из журнала
+	// аудита удаляет ID пользователя. Возвращает количество удалённых участников.
+	EraseParticipantData(ctx context.Context, userID bots.UserID, botID bots.BotID) (int, error)
+
+	// AnonymizeParticipantData заменяет ID пользователя userID в участниках и тредах бота botID случайным
+	// отрицательным ID, не принадлежащим ни одному пользователю Telegram, заменяет тексты ответов, удаляет
+	// профили, переменные тредов, неотправленные сообщения, события и тела Webhook, а из журнала аудита
+	// удаляет ID пользователя. Сохраняются количество участников и тредов и состояния, в которых даны
+	// ответы. Возвращает количество обезличенных участников.
+	AnonymizeParticipantData(ctx context.Context, userID bots.UserID, botID bots.BotID) (int, error)
+}
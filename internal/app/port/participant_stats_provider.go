@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type ParticipantStatsProvider interface {
+	// ParticipantStats возвращает количество всех участников бота и заблокировавших его.
+	ParticipantStats(ctx context.Context, botID bots.BotID) (bots.ParticipantStats, error)
+}
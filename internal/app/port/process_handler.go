@@ -7,5 +7,5 @@ import (
 )
 
 type ProcessHandler interface {
-	Process(ctx context.Context, botID bots.BotID, userID bots.UserID, msg bots.Message) error
+	Process(ctx context.Context, botID bots.BotID, origin Origin, msg bots.Message) error
 }
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type ProfileProvider interface {
+	// ParticipantProfiles возвращает сохранённые профили участников бота по их UserID.
+	ParticipantProfiles(ctx context.Context, botID bots.BotID) (map[bots.UserID]bots.Profile, error)
+
+	// ParticipantProfilesByUserIDs возвращает одним запросом сохранённые профили заданных участников бота.
+	ParticipantProfilesByUserIDs(
+		ctx context.Context, botID bots.BotID, userIDs []bots.UserID,
+	) (map[bots.UserID]bots.Profile, error)
+}
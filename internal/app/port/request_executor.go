@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type RequestExecutor interface {
+	// Execute выполняет HTTP-запрос узла-запроса. Ответ с любым кодом не является ошибкой;
+	// ошибка возвращается, если ответ не получен.
+	Execute(ctx context.Context, req bots.RenderedRequest) (bots.RequestResult, error)
+}
@@ -2,10 +2,52 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
+// UpdateMode есть способ получения обновлений от Telegram.
+type UpdateMode string
+
+const (
+	ModePolling UpdateMode = "polling"
+	ModeWebhook UpdateMode = "webhook"
+)
+
+// InstanceStatus есть состояние экземпляра бота. Status, Replica и HeartbeatAt берутся из аренды бота;
+// счётчики известны только реплике, опрашивающей бота, и заполнены, если StatsReplica не пуст.
+type InstanceStatus struct {
+	Status bots.Status
+
+	// Replica есть реплика, опрашивающая бота; пусто, если бот не запущен.
+	Replica string
+
+	// HeartbeatAt есть время последнего продления аренды бота репликой Replica.
+	HeartbeatAt time.Time
+
+	// StatsReplica есть реплика, счётчики которой приведены; пусто, если статус построен другой репликой.
+	StatsReplica string
+
+	Mode UpdateMode
+
+	// TelegramID и Username получены из getMe при запуске экземпляра.
+	TelegramID int64
+	Username   string
+
+	StartedAt        time.Time
+	LastUpdateAt     time.Time
+	UpdatesProcessed int
+	MessagesSent     int
+
+	// Restarts есть количество автоматических перезапусков после сбоев опроса.
+	Restarts int
+
+	// LastError есть последняя ошибка опроса; пусто, если ошибок не было.
+	LastError   string
+	LastErrorAt time.Time
+}
+
 type StatusProvider interface {
-	Status(ctx context.Context, id bots.BotID) (bots.Status, error)
+	Status(ctx context.Context, id bots.BotID) (InstanceStatus, error)
 }
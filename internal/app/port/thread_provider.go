@@ -2,11 +2,43 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
+// ThreadCursor указывает на последний тред предыдущей страницы выборки.
+type ThreadCursor struct {
+	StartedAt time.Time
+	ThreadID  bots.ThreadID
+}
+
+// ThreadFilter ограничивает выборку тредов бота. Нулевые значения полей не ограничивают выборку.
+type ThreadFilter struct {
+	EntryKey      bots.EntryKey
+	StartedAfter  time.Time // Включительно
+	StartedBefore time.Time // Не включительно
+	// Completed оставляет только завершённые (true) или незавершённые (false) треды: тред завершён, если
+	// находится в одном из состояний FinalStates.
+	Completed   *bool
+	FinalStates []bots.State
+	// Contains оставляет треды, хотя бы один ответ которых содержит строку без учёта регистра.
+	Contains string
+	// Ascending упорядочивает треды от ранних к поздним; по умолчанию - от поздних к ранним.
+	Ascending bool
+	// After есть курсор, после которого продолжается выборка; nil - с начала.
+	After *ThreadCursor
+	Limit int
+}
+
 type ThreadProvider interface {
-	// BotThreads возвращает все цепочки ответов (треды) заданному боту.
-	BotThreads(ctx context.Context, botID bots.BotID) ([]bots.BotThread, error)
+	// StreamBotThreads читает цепочки ответов (треды) бота от поздних к ранним курсором базы данных и передаёт
+	// их fn пачками не более batchSize тредов, не загружая все треды в память. Ошибка fn прерывает чтение.
+	StreamBotThreads(ctx context.Context, botID bots.BotID, batchSize int, fn func([]bots.BotThread) error) error
+
+	// AnsweredStates возвращает по возрастанию состояния узлов, на которые в тредах бота есть хотя бы один ответ.
+	AnsweredStates(ctx context.Context, botID bots.BotID) ([]bots.State, error)
+
+	// FilteredBotThreads возвращает страницу тредов бота, упорядоченных по времени начала.
+	FilteredBotThreads(ctx context.Context, botID bots.BotID, filter ThreadFilter) ([]bots.BotThread, error)
 }
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// ErrTokenRejected возвращается, если Telegram не признал токен бота.
+var ErrTokenRejected = errors.New("telegram rejected bot token")
+
+type TokenVerifier interface {
+	// VerifyToken запрашивает у Telegram аккаунт бота по токену (getMe). Возвращает ErrTokenRejected,
+	// если токен неверен или отозван.
+	VerifyToken(ctx context.Context, token bots.Token) (bots.TelegramAccount, error)
+}
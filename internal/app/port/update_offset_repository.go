@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type UpdateOffsetRepository interface {
+	// UpdateOffset возвращает номер обновления, с которого следует продолжить опрос бота, или 0.
+	UpdateOffset(ctx context.Context, id bots.BotID) (bots.UpdateID, error)
+
+	// SaveUpdateOffset сохраняет номер обновления, с которого следует продолжить опрос бота.
+	// Меньший номер, чем уже сохранённый, игнорируется.
+	SaveUpdateOffset(ctx context.Context, id bots.BotID, offset bots.UpdateID) error
+}
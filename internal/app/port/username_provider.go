@@ -1,15 +0,0 @@
-package port
-
-import (
-	"context"
-	"errors"
-
-	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
-)
-
-var ErrUsernameNotFound = errors.New("username not found")
-
-type UsernameProvider interface {
-	// Username возвращает имя пользователя с UserID.
-	Username(ctx context.Context, prtID bots.ParticipantID) (bots.Username, error)
-}
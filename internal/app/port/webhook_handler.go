@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+type WebhookEventHandler interface {
+	// Enqueue ставит событие бота в очередь на доставку подписанным на него Webhook. Повторный вызов с
+	// тем же eventUUID доставок не дублирует.
+	Enqueue(ctx context.Context, botID bots.BotID, eventUUID string, event string, payload []byte) error
+}
+
+type WebhookDeliveryHandler interface {
+	// Deliver совершает попытку доставки и фиксирует её результат в журнале.
+	Deliver(ctx context.Context, d WebhookDelivery) error
+}
@@ -0,0 +1,75 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery есть запись журнала доставки одного события на Webhook.
+type WebhookDelivery struct {
+	ID        int64
+	BotID     bots.BotID
+	WebhookID bots.WebhookID
+	Event     string
+	Payload   []byte
+	Status    WebhookDeliveryStatus
+	// Attempts есть количество совершённых попыток доставки.
+	Attempts int
+	// ResponseCode есть HTTP-код ответа на последнюю попытку или 0, если ответа не было.
+	ResponseCode  int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	DeliveredAt   time.Time
+}
+
+type WebhookRepository interface {
+	// UpsertWebhook создаёт или заменяет Webhook.
+	UpsertWebhook(ctx context.Context, w *bots.Webhook) error
+
+	// Webhook возвращает найденный Webhook бота или ошибку ErrWebhookNotFound.
+	Webhook(ctx context.Context, botID bots.BotID, id bots.WebhookID) (*bots.Webhook, error)
+
+	// BotWebhooks возвращает возможно пустой список Webhook бота.
+	BotWebhooks(ctx context.Context, botID bots.BotID) ([]*bots.Webhook, error)
+
+	// DeleteWebhook удаляет Webhook вместе с журналом его доставок или возвращает ErrWebhookNotFound.
+	DeleteWebhook(ctx context.Context, botID bots.BotID, id bots.WebhookID) error
+
+	// EnqueueWebhookDeliveries в одной транзакции ставит событие eventUUID в очередь на доставку на
+	// каждый из Webhook ws. Доставки, уже поставленные для того же события, повторно не создаются.
+	EnqueueWebhookDeliveries(
+		ctx context.Context, ws []*bots.Webhook, eventUUID string, event string, payload []byte,
+	) error
+
+	// ClaimWebhookDeliveries захватывает на время lockFor не более limit доставок, готовых к
+	// очередной попытке.
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lockFor time.Duration) ([]WebhookDelivery, error)
+
+	// MarkWebhookDelivered отмечает доставку успешной.
+	MarkWebhookDelivered(ctx context.Context, id int64, responseCode int) error
+
+	// RetryWebhookDelivery откладывает следующую попытку доставки до момента at.
+	RetryWebhookDelivery(ctx context.Context, id int64, at time.Time, responseCode int, reason string) error
+
+	// FailWebhookDelivery окончательно отмечает доставку неудавшейся.
+	FailWebhookDelivery(ctx context.Context, id int64, responseCode int, reason string) error
+
+	// WebhookDeliveries возвращает не более limit последних доставок на Webhook, от новых к старым.
+	WebhookDeliveries(
+		ctx context.Context, botID bots.BotID, id bots.WebhookID, limit int,
+	) ([]WebhookDelivery, error)
+}
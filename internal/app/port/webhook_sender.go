@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+type WebhookSender interface {
+	// Send отправляет payload события на URL, подписывая его секретом. Возвращает HTTP-код
+	// ответа (0, если ответ не получен) и ошибку, если событие не принято получателем.
+	Send(ctx context.Context, url string, secret string, event string, deliveryID int64, payload []byte) (int, error)
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// AuthenticateAPIKeyHandler проверяет API-ключ, переданный клиентом, и возвращает его владельца и
+// ограничения. Время использования ключа запоминает отдельная команда command.TouchAPIKeyHandler.
+type AuthenticateAPIKeyHandler decorator.QueryHandler[
+	request.AuthenticateAPIKeyQuery, response.AuthenticateAPIKeyResponse,
+]
+
+type authenticateAPIKeyHandler struct {
+	kp port.APIKeyProvider
+}
+
+func (h authenticateAPIKeyHandler) Handle(
+	ctx context.Context, q request.AuthenticateAPIKeyQuery,
+) (response.AuthenticateAPIKeyResponse, error) {
+	id, secret, err := bots.ParseAPIKey(q.Key)
+	if err != nil {
+		return dto.APIKey{}, err
+	}
+
+	k, err := h.kp.APIKey(ctx, id)
+	if errors.Is(err, port.ErrAPIKeyNotFound) {
+		return dto.APIKey{}, bots.ErrInvalidAPIKey
+	} else if err != nil {
+		return dto.APIKey{}, err
+	}
+
+	if err = k.Verify(secret, time.Now()); err != nil {
+		return dto.APIKey{}, err
+	}
+	return dto.APIKeyToDto(k), nil
+}
+
+func NewAuthenticateAPIKeyHandler(
+	kp port.APIKeyProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) AuthenticateAPIKeyHandler {
+	return decorator.ApplyQueryDecorators(authenticateAPIKeyHandler{kp}, l, mc)
+}
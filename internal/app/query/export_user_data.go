@@ -0,0 +1,39 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// ExportUserDataHandler выгружает данные пользователя Telegram во всех ботах без проверки доступа, поэтому
+// его строит только команда оператора participant-data, а не app.Application HTTP-сервера.
+type ExportUserDataHandler decorator.QueryHandler[request.ExportUserDataQuery, response.ExportUserDataResponse]
+
+type exportUserDataHandler struct {
+	dp port.ParticipantDataRepository
+}
+
+func (h exportUserDataHandler) Handle(
+	ctx context.Context, q request.ExportUserDataQuery,
+) (response.ExportUserDataResponse, error) {
+	data, err := h.dp.ParticipantData(ctx, bots.UserID(q.UserID), bots.BotID(q.BotID))
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchParticipantDataToDto(data), nil
+}
+
+func NewExportUserDataHandler(
+	dp port.ParticipantDataRepository,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) ExportUserDataHandler {
+	return decorator.ApplyQueryDecorators(exportUserDataHandler{dp}, l, mc)
+}
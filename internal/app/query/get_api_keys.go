@@ -0,0 +1,36 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetAPIKeysHandler decorator.QueryHandler[request.GetAPIKeysQuery, response.GetAPIKeysResponse]
+
+type getAPIKeysHandler struct {
+	kr port.APIKeyRepository
+}
+
+func (h getAPIKeysHandler) Handle(ctx context.Context, q request.GetAPIKeysQuery) (response.GetAPIKeysResponse, error) {
+	if err := access.RequireJWT(q.Scope); err != nil {
+		return nil, err
+	}
+
+	ks, err := h.kr.AccountAPIKeys(ctx, bots.AccountID(q.AccountID))
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchAPIKeyToDto(ks), nil
+}
+
+func NewGetAPIKeysHandler(kr port.APIKeyRepository, l *slog.Logger, mc decorator.MetricsClient) GetAPIKeysHandler {
+	return decorator.ApplyQueryDecorators(getAPIKeysHandler{kr}, l, mc)
+}
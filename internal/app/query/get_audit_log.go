@@ -0,0 +1,61 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 500
+)
+
+type GetAuditLogHandler decorator.QueryHandler[request.GetAuditLogQuery, response.GetAuditLogResponse]
+
+type getAuditLogHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	ap port.AuditLogProvider
+}
+
+// Handle возвращает журнал аудита бота. Журнал раскрывает действия всех соавторов, поэтому доступен
+// только владельцам бота.
+func (h getAuditLogHandler) Handle(
+	ctx context.Context, q request.GetAuditLogQuery,
+) (response.GetAuditLogResponse, error) {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermManage); err != nil {
+		return nil, err
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultAuditLogLimit
+	}
+	rs, err := h.ap.BotAuditLog(ctx, bots.BotID(q.BotID), port.AuditLogFilter{
+		Action: q.Action,
+		Before: q.Before,
+		Limit:  min(limit, maxAuditLogLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchAuditRecordToDto(rs), nil
+}
+
+func NewGetAuditLogHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	ap port.AuditLogProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetAuditLogHandler {
+	return decorator.ApplyQueryDecorators(getAuditLogHandler{bp, cp, ap}, l, mc)
+}
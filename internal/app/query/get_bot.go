@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
@@ -16,13 +17,42 @@ type GetBotHandler decorator.QueryHandler[request.GetBotQuery, response.GetBotRe
 
 type getBotHandler struct {
 	bp port.BotProvider
+	cp port.CollaboratorProvider
 }
 
 func (h getBotHandler) Handle(ctx context.Context, q request.GetBotQuery) (response.GetBotResponse, error) {
-	bot, err := h.bp.Bot(ctx, bots.BotID(q.ID))
-	return dto.BotToDto(bot), err
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, q.ID, q.AccountID, q.Scope, bots.PermView)
+	if err != nil {
+		return dto.Bot{}, err
+	}
+	return botToDtoFor(ctx, h.cp, bot, bots.AccountID(q.AccountID), q.Scope)
 }
 
-func NewGetBotHandler(bp port.BotProvider, l *slog.Logger, mc decorator.MetricsClient) GetBotHandler {
-	return decorator.ApplyQueryDecorators(getBotHandler{bp}, l, mc)
+// botToDtoFor возвращает бота таким, каким его видит пользователь account: тому, кто не может изменять
+// бота, значения заголовков узлов-запросов не показываются.
+func botToDtoFor(
+	ctx context.Context,
+	cp port.CollaboratorProvider,
+	bot *bots.Bot,
+	account bots.AccountID,
+	scope *dto.APIKeyScope,
+) (dto.Bot, error) {
+	res := dto.BotToDto(bot)
+	canEdit, err := access.Allows(ctx, cp, bot, account, scope, bots.PermEdit)
+	if err != nil {
+		return dto.Bot{}, err
+	}
+	if !canEdit {
+		res.Script = res.Script.Redacted()
+	}
+	return res, nil
+}
+
+func NewGetBotHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetBotHandler {
+	return decorator.ApplyQueryDecorators(getBotHandler{bp, cp}, l, mc)
 }
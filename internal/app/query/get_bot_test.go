@@ -0,0 +1,68 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/query"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+// oneBot отдаёт единственного бота.
+type oneBot struct {
+	port.BotProvider
+	bot *bots.Bot
+}
+
+func (p oneBot) Bot(context.Context, bots.BotID) (*bots.Bot, error) {
+	return p.bot, nil
+}
+
+// memCollaborators хранит соавторов бота по их аккаунтам.
+type memCollaborators map[bots.AccountID]*bots.Collaborator
+
+func (m memCollaborators) Collaborator(
+	_ context.Context, _ bots.BotID, acc bots.AccountID,
+) (*bots.Collaborator, error) {
+	if c, ok := m[acc]; ok {
+		return c, nil
+	}
+	return nil, port.ErrCollaboratorNotFound
+}
+
+func (m memCollaborators) BotCollaborators(context.Context, bots.BotID) ([]*bots.Collaborator, error) {
+	return nil, nil
+}
+
+func TestGetBot_HidesRequestHeadersFromViewers(t *testing.T) {
+	bot := bots.MustNewBot("bot", "123456:secret", "author", bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewRequestNode(bots.MustNewState(1), "Lookup", nil, bots.MustNewRequest(
+				"GET", "https://crm.example.com/students", map[string]string{"Authorization": "Bearer key"}, "", nil,
+				bots.MustNewState(2), bots.MustNewState(2),
+			)),
+			bots.MustNewNode(bots.MustNewState(2), "Done", nil, []bots.Message{bots.MustNewMessage("Done")}, nil),
+		},
+		[]bots.Entry{bots.MustNewEntry("start", bots.MustNewState(1))},
+	))
+	cp := memCollaborators{
+		"editor": bots.MustNewCollaborator("bot", "editor", bots.Editor),
+		"viewer": bots.MustNewCollaborator("bot", "viewer", bots.Viewer),
+	}
+	h := query.NewGetBotHandler(oneBot{bot: bot}, cp, logs.DefaultLogger(), metrics.NoOp{})
+
+	headers := func(account string) map[string]string {
+		res, err := h.Handle(context.Background(), request.GetBotQuery{AccountID: account, ID: "bot"})
+		require.NoError(t, err)
+		return res.Script.Nodes[0].Request.Headers
+	}
+	require.Equal(t, map[string]string{"Authorization": "Bearer key"}, headers("author"))
+	require.Equal(t, map[string]string{"Authorization": "Bearer key"}, headers("editor"))
+	require.Equal(t, map[string]string{"Authorization": "***"}, headers("viewer"))
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+const (
+	defaultBotThreadsLimit = 50
+	maxBotThreadsLimit     = 500
+)
+
+type GetBotThreadsHandler decorator.QueryHandler[request.GetBotThreadsQuery, response.GetBotThreadsResponse]
+
+type getBotThreadsHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	tp port.ThreadProvider
+	pp port.ProfileProvider
+}
+
+func (h getBotThreadsHandler) Handle(
+	ctx context.Context, q request.GetBotThreadsQuery,
+) (response.GetBotThreadsResponse, error) {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView)
+	if err != nil {
+		return response.GetBotThreadsResponse{}, err
+	}
+
+	var after *port.ThreadCursor
+	if q.Cursor != "" {
+		cursor, err2 := decodeThreadCursor(q.Cursor)
+		if err2 != nil {
+			return response.GetBotThreadsResponse{}, err2
+		}
+		after = &cursor
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultBotThreadsLimit
+	}
+	limit = min(limit, maxBotThreadsLimit)
+
+	finalStates := make(map[bots.State]bool)
+	for _, node := range bot.Script().Nodes() {
+		if node.IsFinal() {
+			finalStates[node.State()] = true
+		}
+	}
+	filter := port.ThreadFilter{
+		EntryKey:      bots.EntryKey(q.EntryKey),
+		StartedAfter:  q.StartedAfter,
+		StartedBefore: q.StartedBefore,
+		Completed:     q.Completed,
+		FinalStates:   make([]bots.State, 0, len(finalStates)),
+		Contains:      q.Contains,
+		Ascending:     q.Ascending,
+		After:         after,
+		// Лишний тред показывает, что за страницей есть продолжение
+		Limit: limit + 1,
+	}
+	for state := range finalStates {
+		filter.FinalStates = append(filter.FinalStates, state)
+	}
+
+	threads, err := h.tp.FilteredBotThreads(ctx, bot.ID(), filter)
+	if err != nil {
+		return response.GetBotThreadsResponse{}, err
+	}
+
+	var res response.GetBotThreadsResponse
+	if len(threads) > limit {
+		threads = threads[:limit]
+		last := threads[limit-1].Thread()
+		res.NextCursor = encodeThreadCursor(port.ThreadCursor{StartedAt: last.StartedAt(), ThreadID: last.ID()})
+	}
+	userIDs := make([]bots.UserID, len(threads))
+	for i, thread := range threads {
+		userIDs[i] = thread.UserID()
+	}
+	profiles, err := h.pp.ParticipantProfilesByUserIDs(ctx, bot.ID(), userIDs)
+	if err != nil {
+		return response.GetBotThreadsResponse{}, err
+	}
+	res.Threads = make([]dto.Thread, len(threads))
+	for i, thread := range threads {
+		res.Threads[i] = dto.ThreadToDto(thread, threadUsername(profiles, thread.UserID()))
+		res.Threads[i].Completed = finalStates[thread.Thread().State()]
+	}
+	return res, nil
+}
+
+// Курсор есть время начала последнего треда страницы в наносекундах и его ID, закодированные в base64.
+func encodeThreadCursor(c port.ThreadCursor) string {
+	raw := strconv.FormatInt(c.StartedAt.UnixNano(), 10) + ":" + string(c.ThreadID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeThreadCursor(s string) (port.ThreadCursor, error) {
+	invalid := bots.NewInvalidInputError("threads-invalid-cursor", "invalid threads page cursor", "field", "cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return port.ThreadCursor{}, invalid
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return port.ThreadCursor{}, invalid
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return port.ThreadCursor{}, invalid
+	}
+	return port.ThreadCursor{StartedAt: time.Unix(0, n), ThreadID: bots.ThreadID(id)}, nil
+}
+
+func NewGetBotThreadsHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	tp port.ThreadProvider,
+	pp port.ProfileProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetBotThreadsHandler {
+	return decorator.ApplyQueryDecorators(getBotThreadsHandler{bp, cp, tp, pp}, l, mc)
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetCollaboratorsHandler decorator.QueryHandler[request.GetCollaboratorsQuery, response.GetCollaboratorsResponse]
+
+type getCollaboratorsHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+}
+
+func (h getCollaboratorsHandler) Handle(
+	ctx context.Context, q request.GetCollaboratorsQuery,
+) (response.GetCollaboratorsResponse, error) {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := h.cp.BotCollaborators(ctx, bot.ID())
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchCollaboratorToDto(bot, cs), nil
+}
+
+func NewGetCollaboratorsHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetCollaboratorsHandler {
+	return decorator.ApplyQueryDecorators(getCollaboratorsHandler{bp, cp}, l, mc)
+}
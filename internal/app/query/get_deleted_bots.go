@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetDeletedBotsHandler decorator.QueryHandler[request.GetDeletedBotsQuery, response.GetDeletedBotsResponse]
+
+type getDeletedBotsHandler struct {
+	dp port.DeletedBotRepository
+	cp port.CollaboratorProvider
+}
+
+// Handle возвращает удалённых ботов, которых пользователь может восстановить или удалить окончательно.
+func (h getDeletedBotsHandler) Handle(
+	ctx context.Context, q request.GetDeletedBotsQuery,
+) (response.GetDeletedBotsResponse, error) {
+	deleted, err := h.dp.UserDeletedBots(ctx, bots.AccountID(q.AccountID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]port.DeletedBot, 0, len(deleted))
+	for _, d := range deleted {
+		role, err2 := access.RoleOf(ctx, h.cp, d.Bot, bots.AccountID(q.AccountID))
+		if err2 != nil {
+			return nil, err2
+		}
+		if role.Check(bots.PermManage) != nil || access.CheckScope(q.Scope, d.Bot.ID(), bots.PermManage) != nil {
+			continue
+		}
+		res = append(res, d)
+	}
+	return dto.BatchDeletedBotToDto(res), nil
+}
+
+func NewGetDeletedBotsHandler(
+	dp port.DeletedBotRepository,
+	cp port.CollaboratorProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetDeletedBotsHandler {
+	return decorator.ApplyQueryDecorators(getDeletedBotsHandler{dp, cp}, l, mc)
+}
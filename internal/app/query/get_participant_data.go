@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetParticipantDataHandler decorator.QueryHandler[
+	request.GetParticipantDataQuery, response.GetParticipantDataResponse,
+]
+
+type getParticipantDataHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	dp port.ParticipantDataRepository
+}
+
+// Handle возвращает данные пользователя во всех чатах бота или port.ErrParticipantNotFound, если бот
+// ничего о нём не хранит.
+func (h getParticipantDataHandler) Handle(
+	ctx context.Context, q request.GetParticipantDataQuery,
+) (response.GetParticipantDataResponse, error) {
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermManage)
+	if err != nil {
+		return nil, err
+	}
+	data, err := h.dp.ParticipantData(ctx, bots.UserID(q.UserID), bot.ID())
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: user %d in bot %s", port.ErrParticipantNotFound, q.UserID, bot.ID())
+	}
+	return dto.BatchParticipantDataToDto(data), nil
+}
+
+func NewGetParticipantDataHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	dp port.ParticipantDataRepository,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetParticipantDataHandler {
+	return decorator.ApplyQueryDecorators(getParticipantDataHandler{bp, cp, dp}, l, mc)
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetParticipantStatsHandler decorator.QueryHandler[
+	request.GetParticipantStatsQuery, response.GetParticipantStatsResponse,
+]
+
+type getParticipantStatsHandler struct {
+	ps port.ParticipantStatsProvider
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+}
+
+func (h getParticipantStatsHandler) Handle(
+	ctx context.Context, q request.GetParticipantStatsQuery,
+) (response.GetParticipantStatsResponse, error) {
+	_, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView)
+	if err != nil {
+		return dto.ParticipantStats{}, err
+	}
+	stats, err := h.ps.ParticipantStats(ctx, bots.BotID(q.BotID))
+	if err != nil {
+		return dto.ParticipantStats{}, err
+	}
+	return dto.ParticipantStatsToDTO(stats), nil
+}
+
+func NewGetParticipantStatsHandler(
+	ps port.ParticipantStatsProvider,
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetParticipantStatsHandler {
+	return decorator.ApplyQueryDecorators(getParticipantStatsHandler{ps, bp, cp}, l, mc)
+}
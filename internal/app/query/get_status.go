@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
@@ -16,22 +17,42 @@ type GetStatusHandler decorator.QueryHandler[request.GetStatusQuery, response.Ge
 type getStatusHandler struct {
 	sp port.StatusProvider
 	bp port.BotProvider
+	cp port.CollaboratorProvider
 }
 
 func (h getStatusHandler) Handle(ctx context.Context, q request.GetStatusQuery) (response.GetStatusResponse, error) {
-	_, err := h.bp.Bot(ctx, bots.BotID(q.BotID))
+	_, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView)
 	if err != nil {
-		return "", err
+		return response.GetStatusResponse{}, err
 	}
 	status, err := h.sp.Status(ctx, bots.BotID(q.BotID))
 	if err != nil {
-		return "", err
+		return response.GetStatusResponse{}, err
 	}
-	return status.String(), nil
+	return response.GetStatusResponse{
+		Status:           status.Status.String(),
+		Replica:          status.Replica,
+		Mode:             string(status.Mode),
+		HeartbeatAt:      status.HeartbeatAt,
+		StatsReplica:     status.StatsReplica,
+		TelegramID:       status.TelegramID,
+		Username:         status.Username,
+		StartedAt:        status.StartedAt,
+		LastUpdateAt:     status.LastUpdateAt,
+		UpdatesProcessed: status.UpdatesProcessed,
+		MessagesSent:     status.MessagesSent,
+		Restarts:         status.Restarts,
+		LastError:        status.LastError,
+		LastErrorAt:      status.LastErrorAt,
+	}, nil
 }
 
 func NewGetStatusHandler(
-	sp port.StatusProvider, bp port.BotProvider, l *slog.Logger, mc decorator.MetricsClient,
+	sp port.StatusProvider,
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
 ) GetStatusHandler {
-	return decorator.ApplyQueryDecorators(getStatusHandler{sp, bp}, l, mc)
+	return decorator.ApplyQueryDecorators(getStatusHandler{sp, bp, cp}, l, mc)
 }
@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"log/slog"
+	"slices"
 
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
@@ -16,18 +17,42 @@ type GetUserBotsHandler decorator.QueryHandler[request.GetUserBotsQuery, respons
 
 type getUserBotsHandler struct {
 	bp port.BotProvider
+	cp port.CollaboratorProvider
 }
 
 func (h getUserBotsHandler) Handle(
 	ctx context.Context, q request.GetUserBotsQuery,
 ) (response.GetUserBotsResponse, error) {
-	res, err := h.bp.UserBots(ctx, bots.UserID(q.UserID))
+	res, err := h.bp.UserBots(ctx, bots.AccountID(q.Author))
 	if err != nil {
 		return nil, err
 	}
-	return dto.BatchBotToDto(res), nil
+	if q.Scope != nil {
+		// По API-ключу видны только боты, для которых он действует
+		scope, err2 := dto.APIKeyScopeFromDTO(*q.Scope)
+		if err2 != nil {
+			return nil, err2
+		}
+		res = slices.DeleteFunc(res, func(b *bots.Bot) bool {
+			return !scope.Allows(b.ID(), bots.PermView)
+		})
+	}
+	dtos := make([]dto.Bot, 0, len(res))
+	for _, bot := range res {
+		d, err2 := botToDtoFor(ctx, h.cp, bot, bots.AccountID(q.Author), q.Scope)
+		if err2 != nil {
+			return nil, err2
+		}
+		dtos = append(dtos, d)
+	}
+	return dtos, nil
 }
 
-func NewGetUserBotsHandler(bp port.BotProvider, l *slog.Logger, mc decorator.MetricsClient) GetUserBotsHandler {
-	return decorator.ApplyQueryDecorators(getUserBotsHandler{bp}, l, mc)
+func NewGetUserBotsHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetUserBotsHandler {
+	return decorator.ApplyQueryDecorators(getUserBotsHandler{bp, cp}, l, mc)
 }
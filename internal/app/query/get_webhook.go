@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetWebhookHandler decorator.QueryHandler[request.GetWebhookQuery, response.GetWebhookResponse]
+
+type getWebhookHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	wr port.WebhookRepository
+}
+
+func (h getWebhookHandler) Handle(
+	ctx context.Context, q request.GetWebhookQuery,
+) (response.GetWebhookResponse, error) {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView); err != nil {
+		return dto.Webhook{}, err
+	}
+	w, err := h.wr.Webhook(ctx, bots.BotID(q.BotID), bots.WebhookID(q.WebhookID))
+	if err != nil {
+		return dto.Webhook{}, err
+	}
+	return dto.WebhookToDto(w), nil
+}
+
+func NewGetWebhookHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	wr port.WebhookRepository,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetWebhookHandler {
+	return decorator.ApplyQueryDecorators(getWebhookHandler{bp, cp, wr}, l, mc)
+}
@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+const (
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 500
+)
+
+type GetWebhookDeliveriesHandler decorator.QueryHandler[
+	request.GetWebhookDeliveriesQuery,
+	response.GetWebhookDeliveriesResponse,
+]
+
+type getWebhookDeliveriesHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	wr port.WebhookRepository
+}
+
+func (h getWebhookDeliveriesHandler) Handle(
+	ctx context.Context, q request.GetWebhookDeliveriesQuery,
+) (response.GetWebhookDeliveriesResponse, error) {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView); err != nil {
+		return nil, err
+	}
+	botID, webhookID := bots.BotID(q.BotID), bots.WebhookID(q.WebhookID)
+	if _, err := h.wr.Webhook(ctx, botID, webhookID); err != nil {
+		return nil, err
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+	ds, err := h.wr.WebhookDeliveries(ctx, botID, webhookID, min(limit, maxWebhookDeliveriesLimit))
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchWebhookDeliveryToDto(ds), nil
+}
+
+func NewGetWebhookDeliveriesHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	wr port.WebhookRepository,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetWebhookDeliveriesHandler {
+	return decorator.ApplyQueryDecorators(getWebhookDeliveriesHandler{bp, cp, wr}, l, mc)
+}
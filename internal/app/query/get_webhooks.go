@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type GetWebhooksHandler decorator.QueryHandler[request.GetWebhooksQuery, response.GetWebhooksResponse]
+
+type getWebhooksHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	wr port.WebhookRepository
+}
+
+func (h getWebhooksHandler) Handle(
+	ctx context.Context, q request.GetWebhooksQuery,
+) (response.GetWebhooksResponse, error) {
+	if _, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView); err != nil {
+		return nil, err
+	}
+	ws, err := h.wr.BotWebhooks(ctx, bots.BotID(q.BotID))
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchWebhookToDto(ws), nil
+}
+
+func NewGetWebhooksHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	wr port.WebhookRepository,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) GetWebhooksHandler {
+	return decorator.ApplyQueryDecorators(getWebhooksHandler{bp, cp, wr}, l, mc)
+}
@@ -0,0 +1,97 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/access"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/response"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// streamThreadsBatchSize ограничивает количество тредов, одновременно находящихся в памяти при выгрузке.
+const streamThreadsBatchSize = 500
+
+type StreamThreadsHandler decorator.QueryHandler[request.StreamThreadsQuery, response.StreamThreadsResponse]
+
+type streamThreadsHandler struct {
+	bp port.BotProvider
+	cp port.CollaboratorProvider
+	tp port.ThreadProvider
+	pp port.ProfileProvider
+}
+
+func (h streamThreadsHandler) Handle(
+	ctx context.Context, q request.StreamThreadsQuery,
+) (response.StreamThreadsResponse, error) {
+	var res response.StreamThreadsResponse
+	bot, err := access.AccessibleBot(ctx, h.bp, h.cp, q.BotID, q.AccountID, q.Scope, bots.PermView)
+	if err != nil {
+		return res, err
+	}
+	botID := bot.ID()
+
+	states, err := h.tp.AnsweredStates(ctx, botID)
+	if err != nil {
+		return res, err
+	}
+	titles := make(map[bots.State]string)
+	for _, node := range bot.Script().Nodes() {
+		titles[node.State()] = node.Title()
+	}
+	columns := make([]dto.AnswerColumn, len(states))
+	for i, state := range states {
+		columns[i] = dto.AnswerColumn{State: state.Int(), Title: titles[state]}
+	}
+	if err = q.Header(columns); err != nil {
+		return res, err
+	}
+
+	err = h.tp.StreamBotThreads(ctx, botID, streamThreadsBatchSize, func(threads []bots.BotThread) error {
+		userIDs := make([]bots.UserID, 0, len(threads))
+		seen := make(map[bots.UserID]bool, len(threads))
+		for _, thread := range threads {
+			if !seen[thread.UserID()] {
+				seen[thread.UserID()] = true
+				userIDs = append(userIDs, thread.UserID())
+			}
+		}
+		profiles, err2 := h.pp.ParticipantProfilesByUserIDs(ctx, botID, userIDs)
+		if err2 != nil {
+			return err2
+		}
+		for _, thread := range threads {
+			if err2 = q.Row(dto.ThreadToDto(thread, threadUsername(profiles, thread.UserID()))); err2 != nil {
+				return err2
+			}
+			res.Threads++
+		}
+		return nil
+	})
+	return res, err
+}
+
+// threadUsername возвращает имя пользователя Telegram или, если его нет, ID пользователя в виде id123.
+func threadUsername(profiles map[bots.UserID]bots.Profile, userID bots.UserID) string {
+	username := profiles[userID].Username()
+	if username == "" {
+		username = bots.Username(fmt.Sprintf("id%d", userID))
+	}
+	return string(username)
+}
+
+func NewStreamThreadsHandler(
+	bp port.BotProvider,
+	cp port.CollaboratorProvider,
+	tp port.ThreadProvider,
+	pp port.ProfileProvider,
+	l *slog.Logger,
+	mc decorator.MetricsClient,
+) StreamThreadsHandler {
+	return decorator.ApplyQueryDecorators(streamThreadsHandler{bp, cp, tp, pp}, l, mc)
+}
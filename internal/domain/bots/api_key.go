@@ -0,0 +1,309 @@
+package bots
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type APIKeyID string
+
+// apiKeyPrefix начинает каждый API-ключ, чтобы его можно было отличить от JWT и найти в утёкших логах.
+const apiKeyPrefix = "itsreg_"
+
+// apiKeySecretMinLength есть минимальная длина секретной части API-ключа.
+const apiKeySecretMinLength = 32
+
+var (
+	// ErrInvalidAPIKey возвращается для неизвестного или неверного API-ключа.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAPIKeyExpired возвращается для API-ключа с истёкшим сроком действия.
+	ErrAPIKeyExpired = errors.New("api key expired")
+	// ErrOutOfAPIKeyScope возвращается, если действие или бот не входят в ограничения API-ключа.
+	ErrOutOfAPIKeyScope = fmt.Errorf("%w: out of api key scope", ErrBotAccessDenied)
+)
+
+// APIKeyScope ограничивает доступ по API-ключу ботами и действиями над ними. Доступ по ключу
+// никогда не превышает роли его владельца в боте.
+type APIKeyScope struct {
+	// bots пуст, если ключ действует для всех ботов владельца.
+	bots  []BotID
+	perms []Permission
+}
+
+func NewAPIKeyScope(botIDs []BotID, perms []Permission) (APIKeyScope, error) {
+	if len(perms) == 0 {
+		return APIKeyScope{}, NewInvalidInputError(
+			"api-key-empty-actions", "expected at least one action", "field", "actions",
+		)
+	}
+	for _, id := range botIDs {
+		if id == "" {
+			return APIKeyScope{}, NewInvalidInputError(
+				"api-key-empty-bot-id", "expected not empty bot id", "field", "bots",
+			)
+		}
+	}
+
+	botIDs = slices.Clone(botIDs)
+	slices.Sort(botIDs)
+	perms = slices.Clone(perms)
+	slices.Sort(perms)
+	return APIKeyScope{
+		bots:  slices.Compact(botIDs),
+		perms: slices.Compact(perms),
+	}, nil
+}
+
+func MustNewAPIKeyScope(botIDs []BotID, perms []Permission) APIKeyScope {
+	s, err := NewAPIKeyScope(botIDs, perms)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Allows сообщает, разрешено ли по ключу действие perm над ботом botID. Действия не
+// включают друг друга: ключу с manage не разрешён view, если тот не указан явно.
+func (s APIKeyScope) Allows(botID BotID, perm Permission) bool {
+	return s.IncludesBot(botID) && slices.Contains(s.perms, perm)
+}
+
+// Check возвращает ErrOutOfAPIKeyScope, если действие perm над ботом botID не разрешено по ключу.
+func (s APIKeyScope) Check(botID BotID, perm Permission) error {
+	if !s.Allows(botID, perm) {
+		return ErrOutOfAPIKeyScope
+	}
+	return nil
+}
+
+// IncludesBot сообщает, действует ли ключ для бота botID.
+func (s APIKeyScope) IncludesBot(botID BotID) bool {
+	return len(s.bots) == 0 || slices.Contains(s.bots, botID)
+}
+
+func (s APIKeyScope) Bots() []BotID {
+	return s.bots
+}
+
+func (s APIKeyScope) Permissions() []Permission {
+	return s.perms
+}
+
+// APIKey есть долгоживущий ключ пользователя для доступа к API из скриптов и CI вместо JWT.
+// Хранится только SHA-256 хеш секрета: сам ключ показывается пользователю один раз при создании.
+type APIKey struct {
+	id         APIKeyID
+	account    AccountID
+	name       string
+	hash       string
+	scope      APIKeyScope
+	expiresAt  time.Time
+	lastUsedAt time.Time
+	createdAt  time.Time
+}
+
+func NewAPIKey(
+	id APIKeyID,
+	account AccountID,
+	name string,
+	secret string,
+	scope APIKeyScope,
+	expiresAt time.Time,
+) (*APIKey, error) {
+	if id == "" {
+		return nil, NewInvalidInputError("api-key-empty-id", "expected not empty api key id", "field", "id")
+	}
+
+	if account == "" {
+		return nil, NewInvalidInputError(
+			"api-key-empty-account-id", "expected not empty account id", "field", "accountId",
+		)
+	}
+
+	if name == "" {
+		return nil, NewInvalidInputError("api-key-empty-name", "expected not empty api key name", "field", "name")
+	}
+
+	if len(secret) < apiKeySecretMinLength {
+		return nil, NewInvalidInputError(
+			"api-key-short-secret", "expected secret of at least 32 characters", "field", "secret",
+		)
+	}
+
+	if len(scope.perms) == 0 {
+		return nil, NewInvalidInputError(
+			"api-key-empty-actions", "expected at least one action", "field", "actions",
+		)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if !expiresAt.After(now) {
+		return nil, NewInvalidInputError(
+			"api-key-expired", "expected expiration time in the future", "field", "expiresAt",
+		)
+	}
+
+	return &APIKey{
+		id:        id,
+		account:   account,
+		name:      name,
+		hash:      hashAPIKeySecret(secret),
+		scope:     scope,
+		expiresAt: expiresAt,
+		createdAt: now,
+	}, nil
+}
+
+func MustNewAPIKey(
+	id APIKeyID,
+	account AccountID,
+	name string,
+	secret string,
+	scope APIKeyScope,
+	expiresAt time.Time,
+) *APIKey {
+	k, err := NewAPIKey(id, account, name, secret, scope, expiresAt)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// FormatAPIKey возвращает API-ключ в том виде, в котором его передаёт клиент.
+func FormatAPIKey(id APIKeyID, secret string) string {
+	return apiKeyPrefix + string(id) + "_" + secret
+}
+
+// ParseAPIKey разбирает API-ключ, переданный клиентом, на идентификатор и секрет.
+func ParseAPIKey(key string) (APIKeyID, string, error) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return "", "", ErrInvalidAPIKey
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", ErrInvalidAPIKey
+	}
+	return APIKeyID(id), secret, nil
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify проверяет секрет ключа и срок его действия на момент now.
+func (k *APIKey) Verify(secret string, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(hashAPIKeySecret(secret)), []byte(k.hash)) != 1 {
+		return ErrInvalidAPIKey
+	}
+	if !now.Before(k.expiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// MarkUsed запоминает время последнего использования ключа.
+func (k *APIKey) MarkUsed(at time.Time) {
+	k.lastUsedAt = at.Truncate(time.Second)
+}
+
+func (k *APIKey) ID() APIKeyID {
+	return k.id
+}
+
+func (k *APIKey) AccountID() AccountID {
+	return k.account
+}
+
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+func (k *APIKey) Hash() string {
+	return k.hash
+}
+
+func (k *APIKey) Scope() APIKeyScope {
+	return k.scope
+}
+
+func (k *APIKey) ExpiresAt() time.Time {
+	return k.expiresAt
+}
+
+// LastUsedAt возвращает время последнего использования ключа или нулевое время, если ключ
+// ещё не использовался.
+func (k *APIKey) LastUsedAt() time.Time {
+	return k.lastUsedAt
+}
+
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+func UnmarshallAPIKey(
+	id string,
+	account string,
+	name string,
+	hash string,
+	botIDs []string,
+	perms []string,
+	expiresAt time.Time,
+	lastUsedAt time.Time,
+	createdAt time.Time,
+) (*APIKey, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	if account == "" {
+		return nil, errors.New("account is empty")
+	}
+
+	if hash == "" {
+		return nil, errors.New("hash is empty")
+	}
+
+	bs := make([]BotID, len(botIDs))
+	for i, b := range botIDs {
+		bs[i] = BotID(b)
+	}
+	ps := make([]Permission, len(perms))
+	for i, s := range perms {
+		p, err := NewPermission(s)
+		if err != nil {
+			return nil, err
+		}
+		ps[i] = p
+	}
+	scope, err := NewAPIKeyScope(bs, ps)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.IsZero() {
+		return nil, errors.New("expiresAt is empty")
+	}
+
+	if createdAt.IsZero() {
+		return nil, errors.New("createdAt is empty")
+	}
+
+	return &APIKey{
+		id:         APIKeyID(id),
+		account:    AccountID(account),
+		name:       name,
+		hash:       hash,
+		scope:      scope,
+		expiresAt:  expiresAt,
+		lastUsedAt: lastUsedAt,
+		createdAt:  createdAt,
+	}, nil
+}
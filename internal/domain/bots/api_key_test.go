@@ -0,0 +1,66 @@
+package bots_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+const testAPIKeySecret = "0123456789abcdef0123456789abcdef"
+
+func TestNewAPIKey(t *testing.T) {
+	scope := bots.MustNewAPIKeyScope(nil, []bots.Permission{bots.PermView})
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := bots.NewAPIKey("key", "user", "ci", "short", scope, expiresAt)
+	var iiErr bots.InvalidInputError
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "api-key-short-secret", iiErr.Code)
+
+	_, err = bots.NewAPIKey("key", "user", "ci", testAPIKeySecret, scope, time.Now().Add(-time.Hour))
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "api-key-expired", iiErr.Code)
+
+	_, err = bots.NewAPIKeyScope(nil, nil)
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "api-key-empty-actions", iiErr.Code)
+
+	k, err := bots.NewAPIKey("key", "user", "ci", testAPIKeySecret, scope, expiresAt)
+	require.NoError(t, err)
+	require.NotContains(t, k.Hash(), testAPIKeySecret)
+	require.True(t, k.LastUsedAt().IsZero())
+}
+
+func TestAPIKey_Verify(t *testing.T) {
+	scope := bots.MustNewAPIKeyScope(nil, []bots.Permission{bots.PermView})
+	expiresAt := time.Now().Add(time.Hour)
+	k := bots.MustNewAPIKey("key", "user", "ci", testAPIKeySecret, scope, expiresAt)
+
+	id, secret, err := bots.ParseAPIKey(bots.FormatAPIKey(k.ID(), testAPIKeySecret))
+	require.NoError(t, err)
+	require.Equal(t, k.ID(), id)
+	require.NoError(t, k.Verify(secret, time.Now()))
+
+	require.ErrorIs(t, k.Verify(strings.ToUpper(secret), time.Now()), bots.ErrInvalidAPIKey)
+	require.ErrorIs(t, k.Verify(secret, expiresAt), bots.ErrAPIKeyExpired)
+
+	_, _, err = bots.ParseAPIKey("eyJhbGciOiJIUzI1NiJ9.e30.sig")
+	require.ErrorIs(t, err, bots.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyScope_Allows(t *testing.T) {
+	scope := bots.MustNewAPIKeyScope([]bots.BotID{"a"}, []bots.Permission{bots.PermEdit})
+
+	require.True(t, scope.Allows("a", bots.PermEdit))
+	require.False(t, scope.Allows("a", bots.PermView))
+	require.False(t, scope.Allows("b", bots.PermEdit))
+	require.ErrorIs(t, scope.Check("b", bots.PermEdit), bots.ErrBotAccessDenied)
+
+	all := bots.MustNewAPIKeyScope(nil, []bots.Permission{bots.PermView, bots.PermView})
+	require.True(t, all.Allows("b", bots.PermView))
+	require.Len(t, all.Permissions(), 1)
+}
@@ -2,6 +2,7 @@ package bots
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -11,16 +12,38 @@ type BotID string
 // Token есть Telegram токен для бота.
 type Token string
 
+// Redacted возвращает токен, пригодный для показа: ID бота Telegram до двоеточия сохраняется, секретная
+// часть скрывается.
+func (t Token) Redacted() string {
+	id, _, ok := strings.Cut(string(t), ":")
+	if !ok {
+		return redactedSecret
+	}
+	return id + ":" + redactedSecret
+}
+
+const redactedSecret = "***"
+
+// AccountID есть идентификатор пользователя платформы (субъект JWT), в отличие от UserID
+// пользователя Telegram.
+type AccountID string
+
+// ErrBotAccessDenied возвращается при обращении пользователя к чужому боту.
+var ErrBotAccessDenied = errors.New("access to bot denied")
+
 type Bot struct {
-	id        BotID
-	token     Token
-	author    UserID
-	enabled   bool
-	script    Script
+	id         BotID
+	token      Token
+	author     AccountID
+	enabled    bool
+	chatPolicy ChatPolicy
+	script     Script
+	// telegram нулевой, пока токен не проверен.
+	telegram  TelegramAccount
 	createdAt time.Time
 }
 
-func NewBot(id BotID, token Token, author UserID, script Script) (*Bot, error) {
+func NewBot(id BotID, token Token, author AccountID, script Script) (*Bot, error) {
 	if id == "" {
 		return nil, NewInvalidInputError("bot-empty-id", "expected not empty bot id", "field", "id2")
 	}
@@ -29,7 +52,7 @@ func NewBot(id BotID, token Token, author UserID, script Script) (*Bot, error) {
 		return nil, NewInvalidInputError("bot-empty-token", "expected not empty bot token", "field", "token")
 	}
 
-	if author == 0 {
+	if author == "" {
 		return nil, NewInvalidInputError("bot-empty-author-id", "expected not empty bot author", "field", "author")
 	}
 
@@ -38,16 +61,17 @@ func NewBot(id BotID, token Token, author UserID, script Script) (*Bot, error) {
 	}
 
 	return &Bot{
-		id:        id,
-		token:     token,
-		author:    author,
-		enabled:   false,
-		script:    script,
-		createdAt: time.Now().Truncate(time.Second),
+		id:         id,
+		token:      token,
+		author:     author,
+		enabled:    false,
+		chatPolicy: PrivateOnly,
+		script:     script,
+		createdAt:  time.Now().Truncate(time.Second),
 	}, nil
 }
 
-func MustNewBot(id BotID, token Token, author UserID, script Script) *Bot {
+func MustNewBot(id BotID, token Token, author AccountID, script Script) *Bot {
 	b, err := NewBot(id, token, author, script)
 	if err != nil {
 		panic(err)
@@ -63,6 +87,20 @@ func (b *Bot) Disable() {
 	b.enabled = false
 }
 
+// SetChatPolicy задаёт, в каких чатах бот отвечает на сообщения. Нулевая политика
+// заменяется на PrivateOnly.
+func (b *Bot) SetChatPolicy(p ChatPolicy) {
+	if p.IsZero() {
+		p = PrivateOnly
+	}
+	b.chatPolicy = p
+}
+
+// SetTelegram запоминает аккаунт Telegram, которому принадлежит токен бота.
+func (b *Bot) SetTelegram(a TelegramAccount) {
+	b.telegram = a
+}
+
 func (b *Bot) ID() BotID {
 	return b.id
 }
@@ -71,18 +109,55 @@ func (b *Bot) Token() Token {
 	return b.token
 }
 
-func (b *Bot) Author() UserID {
+func (b *Bot) Author() AccountID {
 	return b.author
 }
 
+// RoleOf возвращает роль пользователя acc в боте. Автор бота является его владельцем, соавтор c
+// (если он есть) - имеет свою роль. Нулевая роль означает отсутствие доступа.
+func (b *Bot) RoleOf(acc AccountID, c *Collaborator) Role {
+	if acc == "" {
+		return Role{}
+	}
+	if acc == b.author {
+		return Owner
+	}
+	if c != nil && c.botID == b.id && c.account == acc {
+		return c.role
+	}
+	return Role{}
+}
+
+// Replaces сохраняет за ботом, заменяющим prev, автора prev: замена бота соавтором не делает
+// соавтора владельцем. Скрытый токен (см. Token.Redacted), полученный клиентом вместе с ботом и
+// переданный обратно, означает, что токен не меняется; тогда сохраняется и аккаунт Telegram.
+func (b *Bot) Replaces(prev *Bot) {
+	b.author = prev.author
+	if string(b.token) == prev.token.Redacted() {
+		b.token = prev.token
+	}
+	if b.token == prev.token && b.telegram.IsZero() {
+		b.telegram = prev.telegram
+	}
+}
+
 func (b *Bot) Enabled() bool {
 	return b.enabled
 }
 
+func (b *Bot) ChatPolicy() ChatPolicy {
+	return b.chatPolicy
+}
+
 func (b *Bot) Script() Script {
 	return b.script
 }
 
+// Telegram возвращает аккаунт Telegram бота или нулевое значение, если токен ещё не проверялся.
+func (b *Bot) Telegram() TelegramAccount {
+	return b.telegram
+}
+
 func (b *Bot) CreatedAt() time.Time {
 	return b.createdAt
 }
@@ -90,9 +165,12 @@ func (b *Bot) CreatedAt() time.Time {
 func UnmarshallBot(
 	id string,
 	token string,
-	author int64,
+	author string,
 	enabled bool,
+	chatPolicy string,
 	script Script,
+	telegramID int64,
+	telegramUsername string,
 	createdAt time.Time,
 ) (*Bot, error) {
 	if id == "" {
@@ -103,7 +181,7 @@ func UnmarshallBot(
 		return nil, errors.New("token is empty")
 	}
 
-	if author == 0 {
+	if author == "" {
 		return nil, errors.New("author id is empty")
 	}
 
@@ -111,16 +189,31 @@ func UnmarshallBot(
 		return nil, errors.New("script is empty")
 	}
 
+	policy, err := NewChatPolicy(chatPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var telegram TelegramAccount
+	if telegramID != 0 {
+		telegram, err = NewTelegramAccount(telegramID, telegramUsername)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if createdAt.IsZero() {
 		return nil, errors.New("createdAt is empty")
 	}
 
 	return &Bot{
-		id:        BotID(id),
-		token:     Token(token),
-		author:    UserID(author),
-		enabled:   enabled,
-		script:    script,
-		createdAt: createdAt,
+		id:         BotID(id),
+		token:      Token(token),
+		author:     AccountID(author),
+		enabled:    enabled,
+		chatPolicy: policy,
+		script:     script,
+		telegram:   telegram,
+		createdAt:  createdAt,
 	}, nil
 }
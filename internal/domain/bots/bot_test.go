@@ -18,7 +18,7 @@ func TestNewBot(t *testing.T) {
 		name    string
 		id      bots.BotID
 		token   bots.Token
-		author  bots.UserID
+		author  bots.AccountID
 		script  bots.Script
 		wantErr bool
 		errCode string
@@ -27,7 +27,7 @@ func TestNewBot(t *testing.T) {
 			name:    "Valid bot",
 			id:      "bot",
 			token:   "token",
-			author:  1,
+			author:  "author",
 			script:  validScript,
 			wantErr: false,
 		},
@@ -35,7 +35,7 @@ func TestNewBot(t *testing.T) {
 			name:    "Empty bot id",
 			id:      "",
 			token:   "token",
-			author:  1,
+			author:  "author",
 			script:  validScript,
 			wantErr: true,
 			errCode: "bot-empty-id",
@@ -44,16 +44,16 @@ func TestNewBot(t *testing.T) {
 			name:    "Empty token",
 			id:      "bot",
 			token:   "",
-			author:  1,
+			author:  "author",
 			script:  validScript,
 			wantErr: true,
 			errCode: "bot-empty-token",
 		},
 		{
-			name:    "Zero author id",
+			name:    "Empty author id",
 			id:      "bot",
 			token:   "token",
-			author:  0,
+			author:  "",
 			script:  validScript,
 			wantErr: true,
 			errCode: "bot-empty-author-id",
@@ -73,7 +73,42 @@ func TestNewBot(t *testing.T) {
 				require.Equal(t, tt.id, bot.ID())
 				require.Equal(t, tt.token, bot.Token())
 				require.Equal(t, tt.script, bot.Script())
+				require.Equal(t, bots.PrivateOnly, bot.ChatPolicy())
 			}
 		})
 	}
 }
+
+func TestBot_RoleOf(t *testing.T) {
+	node := bots.MustNewNode(bots.MustNewState(1), "test", nil, []bots.Message{bots.MustNewMessage("some text")}, nil)
+	entry := bots.MustNewEntry("start", bots.MustNewState(1))
+	bot := bots.MustNewBot("bot", "token", "author", bots.MustNewScript([]bots.Node{node}, []bots.Entry{entry}))
+
+	editor := bots.MustNewCollaborator("bot", "editor", bots.Editor)
+	foreign := bots.MustNewCollaborator("other_bot", "editor", bots.Owner)
+
+	require.Equal(t, bots.Owner, bot.RoleOf("author", nil))
+	require.Equal(t, bots.Editor, bot.RoleOf("editor", editor))
+	require.True(t, bot.RoleOf("editor", foreign).IsZero())
+	require.True(t, bot.RoleOf("stranger", editor).IsZero())
+	require.True(t, bot.RoleOf("", nil).IsZero())
+}
+
+func TestBot_Replaces(t *testing.T) {
+	node := bots.MustNewNode(bots.MustNewState(1), "test", nil, []bots.Message{bots.MustNewMessage("some text")}, nil)
+	entry := bots.MustNewEntry("start", bots.MustNewState(1))
+	script := bots.MustNewScript([]bots.Node{node}, []bots.Entry{entry})
+	prev := bots.MustNewBot("bot", "123456:secret", "author", script)
+
+	require.Equal(t, "123456:***", prev.Token().Redacted())
+	require.Equal(t, "***", bots.Token("secret").Redacted())
+
+	bot := bots.MustNewBot("bot", bots.Token(prev.Token().Redacted()), "editor", script)
+	bot.Replaces(prev)
+	require.Equal(t, prev.Token(), bot.Token())
+	require.Equal(t, prev.Author(), bot.Author())
+
+	bot = bots.MustNewBot("bot", "123456:rotated", "editor", script)
+	bot.Replaces(prev)
+	require.Equal(t, bots.Token("123456:rotated"), bot.Token())
+}
@@ -0,0 +1,68 @@
+package bots
+
+// ChatID есть идентификатор чата Telegram. Для личных чатов совпадает с UserID собеседника.
+type ChatID int64
+
+// ChatPolicy определяет, в каких чатах бот отвечает на сообщения.
+type ChatPolicy struct {
+	s string
+}
+
+var (
+	// PrivateOnly разрешает только личные чаты. Сообщения из групп игнорируются.
+	PrivateOnly = ChatPolicy{"private"}
+	// GroupMentions разрешает группы, но в них бот отвечает только на команды, упоминания
+	// и ответы на свои сообщения.
+	GroupMentions = ChatPolicy{"mentions"}
+	// GroupAll разрешает группы и отвечает на любые сообщения в них.
+	GroupAll = ChatPolicy{"groups"}
+)
+
+func NewChatPolicy(s string) (ChatPolicy, error) {
+	switch s {
+	case PrivateOnly.s:
+		return PrivateOnly, nil
+	case GroupMentions.s:
+		return GroupMentions, nil
+	case GroupAll.s:
+		return GroupAll, nil
+	}
+	return ChatPolicy{}, NewInvalidInputError(
+		"bot-invalid-chat-policy",
+		"expected one of private, mentions, groups",
+		"field", "chatPolicy",
+		"chatPolicy", s,
+	)
+}
+
+func MustNewChatPolicy(s string) ChatPolicy {
+	p, err := NewChatPolicy(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p ChatPolicy) IsZero() bool {
+	return p == ChatPolicy{}
+}
+
+func (p ChatPolicy) String() string {
+	return p.s
+}
+
+// Accepts сообщает, должен ли бот обработать сообщение. addressed означает, что сообщение
+// в группе адресовано боту: является командой, упоминанием или ответом на сообщение бота.
+func (p ChatPolicy) Accepts(group bool, addressed bool) bool {
+	if !group {
+		return true
+	}
+	switch p {
+	case GroupAll:
+		return true
+	case GroupMentions:
+		return addressed
+	default:
+		return false
+	}
+}
@@ -0,0 +1,60 @@
+package bots_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestNewChatPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    bots.ChatPolicy
+		wantErr bool
+	}{
+		{name: "Private only", s: "private", want: bots.PrivateOnly},
+		{name: "Group mentions", s: "mentions", want: bots.GroupMentions},
+		{name: "Group all", s: "groups", want: bots.GroupAll},
+		{name: "Unknown policy", s: "channels", wantErr: true},
+		{name: "Empty policy", s: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bots.NewChatPolicy(tt.s)
+			if tt.wantErr {
+				var iiErr bots.InvalidInputError
+				require.ErrorAs(t, err, &iiErr)
+				require.Equal(t, "bot-invalid-chat-policy", iiErr.Code)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChatPolicy_Accepts(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    bots.ChatPolicy
+		group     bool
+		addressed bool
+		want      bool
+	}{
+		{name: "Private chat is always accepted", policy: bots.PrivateOnly, group: false, want: true},
+		{name: "Private only ignores groups", policy: bots.PrivateOnly, group: true, addressed: true, want: false},
+		{name: "Mentions accepts addressed", policy: bots.GroupMentions, group: true, addressed: true, want: true},
+		{name: "Mentions ignores others", policy: bots.GroupMentions, group: true, addressed: false, want: false},
+		{name: "Groups accepts everything", policy: bots.GroupAll, group: true, addressed: false, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.policy.Accepts(tt.group, tt.addressed))
+		})
+	}
+}
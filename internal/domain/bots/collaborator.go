@@ -0,0 +1,209 @@
+package bots
+
+import (
+	"errors"
+	"time"
+)
+
+// Role определяет права пользователя на бота.
+type Role struct {
+	s string
+}
+
+var (
+	// Owner может всё, в том числе удалять бота и управлять его соавторами. Автор бота всегда
+	// является его владельцем.
+	Owner = Role{"owner"}
+	// Editor может изменять сценарий, запускать и останавливать бота, делать рассылки и
+	// управлять Webhook.
+	Editor = Role{"editor"}
+	// Viewer может только просматривать бота, его статус и ответы участников.
+	Viewer = Role{"viewer"}
+)
+
+// Permission есть действие над ботом, требующее определённой роли.
+type Permission int
+
+const (
+	// PermView разрешает просмотр бота и ответов участников.
+	PermView Permission = iota + 1
+	// PermEdit разрешает изменение и управление работой бота.
+	PermEdit
+	// PermManage разрешает удаление бота и управление соавторами.
+	PermManage
+)
+
+// NewPermission возвращает действие по его имени: view, edit или manage.
+func NewPermission(s string) (Permission, error) {
+	switch s {
+	case "view":
+		return PermView, nil
+	case "edit":
+		return PermEdit, nil
+	case "manage":
+		return PermManage, nil
+	}
+	return 0, NewInvalidInputError(
+		"invalid-permission",
+		"expected one of view, edit, manage",
+		"permission", s,
+	)
+}
+
+func (p Permission) String() string {
+	switch p {
+	case PermView:
+		return "view"
+	case PermEdit:
+		return "edit"
+	case PermManage:
+		return "manage"
+	default:
+		return ""
+	}
+}
+
+func NewRole(s string) (Role, error) {
+	switch s {
+	case Owner.s:
+		return Owner, nil
+	case Editor.s:
+		return Editor, nil
+	case Viewer.s:
+		return Viewer, nil
+	}
+	return Role{}, NewInvalidInputError(
+		"collaborator-invalid-role",
+		"expected one of owner, editor, viewer",
+		"field", "role",
+		"role", s,
+	)
+}
+
+func MustNewRole(s string) Role {
+	r, err := NewRole(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Role) IsZero() bool {
+	return r == Role{}
+}
+
+func (r Role) String() string {
+	return r.s
+}
+
+// Allows сообщает, разрешено ли роли действие p. Нулевая роль не разрешает ничего.
+func (r Role) Allows(p Permission) bool {
+	switch r {
+	case Owner:
+		return true
+	case Editor:
+		return p == PermView || p == PermEdit
+	case Viewer:
+		return p == PermView
+	default:
+		return false
+	}
+}
+
+// Check возвращает ErrBotAccessDenied, если роли не разрешено действие p.
+func (r Role) Check(p Permission) error {
+	if !r.Allows(p) {
+		return ErrBotAccessDenied
+	}
+	return nil
+}
+
+// Collaborator есть пользователь, которому автор бота предоставил доступ к нему с ролью.
+type Collaborator struct {
+	botID   BotID
+	account AccountID
+	role    Role
+	addedAt time.Time
+}
+
+func NewCollaborator(botID BotID, account AccountID, role Role) (*Collaborator, error) {
+	if botID == "" {
+		return nil, NewInvalidInputError("collaborator-empty-bot-id", "expected not empty bot id", "field", "botId")
+	}
+
+	if account == "" {
+		return nil, NewInvalidInputError(
+			"collaborator-empty-account-id", "expected not empty account id", "field", "accountId",
+		)
+	}
+
+	if role.IsZero() {
+		return nil, NewInvalidInputError("collaborator-empty-role", "expected not empty role", "field", "role")
+	}
+
+	return &Collaborator{
+		botID:   botID,
+		account: account,
+		role:    role,
+		addedAt: time.Now().Truncate(time.Second),
+	}, nil
+}
+
+func MustNewCollaborator(botID BotID, account AccountID, role Role) *Collaborator {
+	c, err := NewCollaborator(botID, account, role)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// SetRole заменяет роль соавтора.
+func (c *Collaborator) SetRole(role Role) error {
+	if role.IsZero() {
+		return NewInvalidInputError("collaborator-empty-role", "expected not empty role", "field", "role")
+	}
+	c.role = role
+	return nil
+}
+
+func (c *Collaborator) BotID() BotID {
+	return c.botID
+}
+
+func (c *Collaborator) AccountID() AccountID {
+	return c.account
+}
+
+func (c *Collaborator) Role() Role {
+	return c.role
+}
+
+func (c *Collaborator) AddedAt() time.Time {
+	return c.addedAt
+}
+
+func UnmarshallCollaborator(botID string, account string, role string, addedAt time.Time) (*Collaborator, error) {
+	if botID == "" {
+		return nil, errors.New("botID is empty")
+	}
+
+	if account == "" {
+		return nil, errors.New("account is empty")
+	}
+
+	r, err := NewRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	if addedAt.IsZero() {
+		return nil, errors.New("addedAt is empty")
+	}
+
+	return &Collaborator{
+		botID:   BotID(botID),
+		account: AccountID(account),
+		role:    r,
+		addedAt: addedAt,
+	}, nil
+}
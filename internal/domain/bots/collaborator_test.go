@@ -0,0 +1,51 @@
+package bots_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role   bots.Role
+		view   bool
+		edit   bool
+		manage bool
+	}{
+		{role: bots.Owner, view: true, edit: true, manage: true},
+		{role: bots.Editor, view: true, edit: true, manage: false},
+		{role: bots.Viewer, view: true, edit: false, manage: false},
+		{role: bots.Role{}, view: false, edit: false, manage: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role.String(), func(t *testing.T) {
+			require.Equal(t, tt.view, tt.role.Allows(bots.PermView))
+			require.Equal(t, tt.edit, tt.role.Allows(bots.PermEdit))
+			require.Equal(t, tt.manage, tt.role.Allows(bots.PermManage))
+		})
+	}
+
+	require.ErrorIs(t, bots.Viewer.Check(bots.PermEdit), bots.ErrBotAccessDenied)
+	require.NoError(t, bots.Editor.Check(bots.PermEdit))
+}
+
+func TestNewCollaborator(t *testing.T) {
+	_, err := bots.NewRole("admin")
+	var iiErr bots.InvalidInputError
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "collaborator-invalid-role", iiErr.Code)
+
+	_, err = bots.NewCollaborator("bot", "", bots.Viewer)
+	require.ErrorAs(t, err, &iiErr)
+	require.Equal(t, "collaborator-empty-account-id", iiErr.Code)
+
+	c, err := bots.NewCollaborator("bot", "user", bots.Viewer)
+	require.NoError(t, err)
+	require.NoError(t, c.SetRole(bots.Editor))
+	require.Equal(t, bots.Editor, c.Role())
+	require.Error(t, c.SetRole(bots.Role{}))
+}
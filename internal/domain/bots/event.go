@@ -0,0 +1,94 @@
+package bots
+
+import "time"
+
+// Event есть доменное событие, о котором могут узнать внешние системы.
+type Event interface {
+	// EventName возвращает имя типа события, например, "thread_completed".
+	EventName() string
+}
+
+// Имена доменных событий.
+const (
+	EventThreadStarted   = "thread_started"
+	EventAnswerSaved     = "answer_saved"
+	EventThreadCompleted = "thread_completed"
+	EventBotEnabled      = "bot_enabled"
+	EventBotDisabled     = "bot_disabled"
+	EventMailingFinished = "mailing_finished"
+)
+
+// EventNames возвращает имена всех доменных событий.
+func EventNames() []string {
+	return []string{
+		EventThreadStarted,
+		EventAnswerSaved,
+		EventThreadCompleted,
+		EventBotEnabled,
+		EventBotDisabled,
+		EventMailingFinished,
+	}
+}
+
+// ThreadStarted возникает, когда участник начинает новый тред через Entry.
+type ThreadStarted struct {
+	ParticipantID ParticipantID
+	ThreadID      ThreadID
+	Key           EntryKey
+	StartedAt     time.Time
+}
+
+func (ThreadStarted) EventName() string { return EventThreadStarted }
+
+// AnswerSaved возникает, когда ответ участника сохраняется в тред.
+type AnswerSaved struct {
+	ParticipantID ParticipantID
+	ThreadID      ThreadID
+	State         State
+	Title         string  // Заголовок узла, на который дан ответ
+	Answer        Message // Сохранённый ответ с учётом объединения предыдущих
+	SavedAt       time.Time
+}
+
+func (AnswerSaved) EventName() string { return EventAnswerSaved }
+
+// ThreadCompleted возникает, когда тред участника достигает узла без исходящих рёбер.
+type ThreadCompleted struct {
+	ParticipantID ParticipantID
+	ThreadID      ThreadID
+	Key           EntryKey
+	Answers       map[State]Message
+	Titles        map[State]string // Заголовки узлов, на которые даны ответы
+	StartedAt     time.Time
+	CompletedAt   time.Time
+}
+
+func (ThreadCompleted) EventName() string { return EventThreadCompleted }
+
+// BotEnabled возникает, когда бот включается в автозапуск.
+type BotEnabled struct {
+	BotID     BotID
+	EnabledAt time.Time
+}
+
+func (BotEnabled) EventName() string { return EventBotEnabled }
+
+// BotDisabled возникает, когда бот исключается из автозапуска.
+type BotDisabled struct {
+	BotID      BotID
+	DisabledAt time.Time
+}
+
+func (BotDisabled) EventName() string { return EventBotDisabled }
+
+// MailingFinished возникает по завершении рассылки: Recipients есть количество пользователей,
+// которым рассылка была поставлена в очередь, Skipped - количество заблокировавших бота.
+type MailingFinished struct {
+	BotID      BotID
+	Key        EntryKey
+	Recipients int
+	Skipped    int
+	FinishedAt time.Time
+}
+
+func (MailingFinished) EventName() string { return EventMailingFinished }
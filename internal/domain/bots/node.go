@@ -46,6 +46,7 @@ type Node struct {
 	edges []Edge    // Отсортированный по приоритету список исходящих рёбер.
 	msgs  []Message // Список сообщений, который будет отправлен пользователю.
 	opts  []Option  // Список кнопок-клавиатуры, которые будут отправлены с последним сообщением
+	req   Request   // Запрос узла-запроса или нулевой Request для обычного узла
 }
 
 // NewNode создаёт Node. msgs должно содержать как минимум одно Message.
@@ -89,6 +90,44 @@ func MustNewNode(state State, title string, edges []Edge, msgs []Message, opts [
 	return n
 }
 
+// NewRequestNode создаёт узел-запрос. Такой узел не имеет рёбер и опций: переход из него
+// определяется результатом Request. msgs может быть пустым, иначе сообщения отправляются
+// пользователю перед выполнением запроса.
+func NewRequestNode(state State, title string, msgs []Message, req Request) (Node, error) {
+	if state == ZeroState {
+		return Node{}, errors.New("empty state")
+	}
+
+	if title == "" {
+		return Node{}, NewInvalidInputError("node-empty-title", "expected not empty title text", "field", "title")
+	}
+
+	if req.IsZero() {
+		return Node{}, errors.New("empty request")
+	}
+
+	if msgs == nil {
+		msgs = make([]Message, 0)
+	}
+
+	return Node{
+		state: state,
+		title: title,
+		edges: make([]Edge, 0),
+		msgs:  msgs,
+		opts:  make([]Option, 0),
+		req:   req,
+	}, nil
+}
+
+func MustNewRequestNode(state State, title string, msgs []Message, req Request) Node {
+	n, err := NewRequestNode(state, title, msgs, req)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
 func (n Node) IsZero() bool {
 	// Конструктор гарантирует, что msgs не будет nil.
 	// Поэтому если msgs = nil, то сущность создана не через конструктор,
@@ -110,9 +149,16 @@ func (n Node) Transition(msg Message) (Edge, bool) {
 // Children возвращает упорядоченное множество State дочерних узлов.
 // Обычно используется для обхода графа.
 func (n Node) Children() []State {
-	children := make([]State, 0, len(n.edges))
+	targets := make([]State, 0, len(n.edges))
 	for _, edge := range n.edges {
-		to := edge.To()
+		targets = append(targets, edge.To())
+	}
+	if n.IsRequest() {
+		targets = append(targets, n.req.OnSuccess(), n.req.OnFailure())
+	}
+
+	children := make([]State, 0, len(targets))
+	for _, to := range targets {
 		// Повторные вхождения игнорируем
 		if slices.Contains(children, to) {
 			continue
@@ -138,17 +184,50 @@ func (n Node) Messages() []Message {
 // BotMessages возвращает сообщения в том виде, в котором они будут отправлены пользователю.
 // Если для узла заданы опции, последнее сообщение будет их содержать.
 func (n Node) BotMessages() []BotMessage {
+	if len(n.msgs) == 0 {
+		// Только узел-запрос может не иметь сообщений
+		return nil
+	}
 	res := make([]BotMessage, len(n.msgs))
 	for i, msg := range n.msgs[:len(n.msgs)-1] {
 		// Промежуточные сообщения не могут иметь опций ответа.
 		res[i] = msg.PromoteToBotMessage(nil)
 	}
-	// Последнее сообщение гарантировано существует, т.к. len(n.msgs) > 0.
+	// Последнее сообщение существует, т.к. len(n.msgs) > 0.
 	// Добавляем к нему опции.
 	res[len(res)-1] = n.msgs[len(n.msgs)-1].PromoteToBotMessage(n.opts)
 	return res
 }
 
+// RenderBotMessages возвращает BotMessages, в тексте которых подстановки {{answers.N}} и
+// {{vars.name}} заменены значениями треда.
+func (n Node) RenderBotMessages(thr *Thread) []BotMessage {
+	res := n.BotMessages()
+	for i, msg := range res {
+		text := render(msg.Text(), thr, noEscape)
+		if text == "" {
+			// Сообщение не может быть пустым, оставляем шаблон как есть
+			continue
+		}
+		res[i] = Message{text: text}.PromoteToBotMessage(msg.Options())
+	}
+	return res
+}
+
+// IsRequest сообщает, что узел является узлом-запросом.
+func (n Node) IsRequest() bool {
+	return !n.req.IsZero()
+}
+
+// IsFinal сообщает, что из узла нет переходов: тред, достигший его, завершён.
+func (n Node) IsFinal() bool {
+	return len(n.edges) == 0 && !n.IsRequest()
+}
+
+func (n Node) Request() Request {
+	return n.req
+}
+
 func (n Node) Edges() []Edge {
 	return n.edges
 }
@@ -2,17 +2,33 @@ package bots
 
 import (
 	"errors"
+	"fmt"
+	"time"
 )
 
 type UserID int64
 
+// UpdateID есть номер обновления Telegram. Номера обновлений бота строго возрастают.
+type UpdateID int64
+
+var ErrUpdateAlreadyApplied = errors.New("update already applied")
+
+// ParticipantID идентифицирует пользователя в конкретном чате с ботом. В личном чате ChatID
+// совпадает с UserID; в группе у каждого её участника свой ParticipantID.
 type ParticipantID struct {
+	chatID ChatID
 	userID UserID
 	botID  BotID
 }
 
+// NewParticipantID возвращает ParticipantID пользователя в личном чате с ботом.
 func NewParticipantID(userID UserID, botID BotID) ParticipantID {
+	return NewChatParticipantID(ChatID(userID), userID, botID)
+}
+
+func NewChatParticipantID(chatID ChatID, userID UserID, botID BotID) ParticipantID {
 	return ParticipantID{
+		chatID: chatID,
 		userID: userID,
 		botID:  botID,
 	}
@@ -22,6 +38,15 @@ func (id ParticipantID) IsZero() bool {
 	return id.botID == ""
 }
 
+func (id ParticipantID) ChatID() ChatID {
+	return id.chatID
+}
+
+// IsPrivate сообщает, что участник общается с ботом в личном чате.
+func (id ParticipantID) IsPrivate() bool {
+	return int64(id.chatID) == int64(id.userID)
+}
+
 func (id ParticipantID) UserID() UserID {
 	return id.userID
 }
@@ -31,8 +56,12 @@ func (id ParticipantID) BotID() BotID {
 }
 
 type Participant struct {
-	id     ParticipantID
-	thread *Thread // Активный тред для пользователя или nil
+	id         ParticipantID
+	thread     *Thread   // Активный тред для пользователя или nil
+	blockedAt  time.Time // Момент блокировки бота пользователем или нулевое время
+	profile    Profile   // Последние известные данные пользователя или нулевой профиль
+	lastUpdate UpdateID  // Последнее применённое обновление или 0
+	events     []Event   // События, возникшие с момента загрузки участника
 }
 
 func NewParticipant(id ParticipantID) (*Participant, error) {
@@ -60,9 +89,24 @@ func (p *Participant) StartThread(entry Entry) (*Thread, error) {
 		return nil, err
 	}
 	p.thread = thread
+	p.record(ThreadStarted{
+		ParticipantID: p.id,
+		ThreadID:      thread.ID(),
+		Key:           thread.Key(),
+		StartedAt:     thread.StartedAt(),
+	})
 	return thread, nil
 }
 
+// Events возвращает события, возникшие с момента создания или загрузки участника.
+func (p *Participant) Events() []Event {
+	return p.events
+}
+
+func (p *Participant) record(e Event) {
+	p.events = append(p.events, e)
+}
+
 func (p *Participant) ActiveThread() *Thread {
 	return p.thread
 }
@@ -71,10 +115,66 @@ func (p *Participant) ID() ParticipantID {
 	return p.id
 }
 
+func (p *Participant) Profile() Profile {
+	return p.profile
+}
+
+// UpdateProfile заменяет данные пользователя на полученные из последнего обновления.
+// Нулевой профиль игнорируется, чтобы не затирать известные данные.
+func (p *Participant) UpdateProfile(profile Profile) {
+	if profile.IsZero() {
+		return
+	}
+	p.profile = profile
+}
+
+// Block отмечает, что пользователь заблокировал бота. Повторная блокировка не изменяет
+// момент первой блокировки.
+func (p *Participant) Block() {
+	if p.IsBlocked() {
+		return
+	}
+	p.blockedAt = time.Now().Truncate(time.Second)
+}
+
+// Unblock снимает отметку о блокировке, например, когда пользователь снова пишет боту.
+func (p *Participant) Unblock() {
+	p.blockedAt = time.Time{}
+}
+
+func (p *Participant) IsBlocked() bool {
+	return !p.blockedAt.IsZero()
+}
+
+// BlockedAt возвращает момент блокировки бота пользователем или нулевое время.
+func (p *Participant) BlockedAt() time.Time {
+	return p.blockedAt
+}
+
+// ApplyUpdate отмечает обновление применённым к участнику. Возвращает ErrUpdateAlreadyApplied,
+// если обновление с таким или большим номером уже было применено: повторно полученное после
+// сбоя обновление не должно второй раз продвигать тред.
+func (p *Participant) ApplyUpdate(id UpdateID) error {
+	if id <= p.lastUpdate {
+		return fmt.Errorf("%w: %d", ErrUpdateAlreadyApplied, id)
+	}
+	p.lastUpdate = id
+	return nil
+}
+
+// LastUpdateID возвращает номер последнего применённого обновления или 0.
+func (p *Participant) LastUpdateID() UpdateID {
+	return p.lastUpdate
+}
+
 func UnmarshallParticipant(
 	botID string,
+	chatID int64,
 	userID int64,
 	thread *Thread,
+	blockedAt *time.Time,
+	profile Profile,
+	lastUpdate int64,
 ) (*Participant, error) {
 	if botID == "" {
 		return nil, errors.New("botID is empty")
@@ -84,10 +184,51 @@ func UnmarshallParticipant(
 		return nil, errors.New("UserID is empty")
 	}
 
-	id := NewParticipantID(UserID(userID), BotID(botID))
+	if chatID == 0 {
+		return nil, errors.New("chatID is empty")
+	}
 
-	return &Participant{
-		id:     id,
-		thread: thread,
+	id := NewChatParticipantID(ChatID(chatID), UserID(userID), BotID(botID))
+
+	prt := &Participant{
+		id:         id,
+		thread:     thread,
+		profile:    profile,
+		lastUpdate: UpdateID(lastUpdate),
+	}
+	if blockedAt != nil {
+		prt.blockedAt = *blockedAt
+	}
+
+	return prt, nil
+}
+
+// ParticipantStats есть сводка по участникам бота.
+type ParticipantStats struct {
+	total   int
+	blocked int
+}
+
+func NewParticipantStats(total int, blocked int) (ParticipantStats, error) {
+	if total < 0 || blocked < 0 {
+		return ParticipantStats{}, errors.New("negative participant count")
+	}
+
+	if blocked > total {
+		return ParticipantStats{}, errors.New("blocked count exceeds total")
+	}
+
+	return ParticipantStats{
+		total:   total,
+		blocked: blocked,
 	}, nil
 }
+
+func (s ParticipantStats) Total() int {
+	return s.total
+}
+
+// Blocked возвращает количество участников, заблокировавших бота.
+func (s ParticipantStats) Blocked() int {
+	return s.blocked
+}
@@ -64,3 +64,95 @@ func TestParticipant_StartThread(t *testing.T) {
 	require.NotNil(t, current)
 	require.Equal(t, started, current)
 }
+
+func TestParticipant_Block(t *testing.T) {
+	prt := bots.MustNewParticipant(bots.NewParticipantID(1, "bot"))
+	require.False(t, prt.IsBlocked())
+	require.True(t, prt.BlockedAt().IsZero())
+
+	prt.Block()
+	require.True(t, prt.IsBlocked())
+	blockedAt := prt.BlockedAt()
+	require.False(t, blockedAt.IsZero())
+
+	prt.Block()
+	require.Equal(t, blockedAt, prt.BlockedAt())
+
+	prt.Unblock()
+	require.False(t, prt.IsBlocked())
+	require.True(t, prt.BlockedAt().IsZero())
+}
+
+func TestNewParticipantStats(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		blocked     int
+		wantErr     bool
+		expectedErr string
+	}{
+		{
+			name:    "Valid stats",
+			total:   10,
+			blocked: 3,
+			wantErr: false,
+		},
+		{
+			name:        "Negative count",
+			total:       -1,
+			blocked:     0,
+			wantErr:     true,
+			expectedErr: "negative participant count",
+		},
+		{
+			name:        "Blocked exceeds total",
+			total:       1,
+			blocked:     2,
+			wantErr:     true,
+			expectedErr: "blocked count exceeds total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bots.NewParticipantStats(tt.total, tt.blocked)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.total, got.Total())
+				require.Equal(t, tt.blocked, got.Blocked())
+			}
+		})
+	}
+}
+
+func TestParticipantID_IsPrivate(t *testing.T) {
+	private := bots.NewParticipantID(1, "bot")
+	require.True(t, private.IsPrivate())
+	require.Equal(t, bots.ChatID(1), private.ChatID())
+
+	group := bots.NewChatParticipantID(-100, 1, "bot")
+	require.False(t, group.IsPrivate())
+	require.Equal(t, bots.ChatID(-100), group.ChatID())
+	require.Equal(t, bots.UserID(1), group.UserID())
+}
+
+func TestParticipant_ApplyUpdate(t *testing.T) {
+	prt := bots.MustNewParticipant(bots.NewParticipantID(1, "bot"))
+	require.Equal(t, bots.UpdateID(0), prt.LastUpdateID())
+
+	require.NoError(t, prt.ApplyUpdate(10))
+	require.Equal(t, bots.UpdateID(10), prt.LastUpdateID())
+
+	err := prt.ApplyUpdate(10)
+	require.ErrorIs(t, err, bots.ErrUpdateAlreadyApplied)
+
+	err = prt.ApplyUpdate(9)
+	require.ErrorIs(t, err, bots.ErrUpdateAlreadyApplied)
+	require.Equal(t, bots.UpdateID(10), prt.LastUpdateID())
+
+	require.NoError(t, prt.ApplyUpdate(11))
+	require.Equal(t, bots.UpdateID(11), prt.LastUpdateID())
+}
@@ -0,0 +1,40 @@
+package bots
+
+// Profile есть данные пользователя Telegram, полученные ботом из последнего обновления.
+type Profile struct {
+	username     Username
+	firstName    string
+	lastName     string
+	languageCode string
+}
+
+func NewProfile(username Username, firstName string, lastName string, languageCode string) Profile {
+	return Profile{
+		username:     username,
+		firstName:    firstName,
+		lastName:     lastName,
+		languageCode: languageCode,
+	}
+}
+
+func (p Profile) IsZero() bool {
+	return p == Profile{}
+}
+
+// Username возвращает имя пользователя без @ или пустую строку, если оно не задано.
+func (p Profile) Username() Username {
+	return p.username
+}
+
+func (p Profile) FirstName() string {
+	return p.firstName
+}
+
+func (p Profile) LastName() string {
+	return p.lastName
+}
+
+// LanguageCode возвращает IETF-тег языка клиента пользователя, если он известен.
+func (p Profile) LanguageCode() string {
+	return p.languageCode
+}
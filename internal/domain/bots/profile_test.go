@@ -0,0 +1,33 @@
+package bots_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestNewProfile(t *testing.T) {
+	p := bots.NewProfile("durov", "Pavel", "Durov", "en")
+	require.False(t, p.IsZero())
+	require.Equal(t, bots.Username("durov"), p.Username())
+	require.Equal(t, "Pavel", p.FirstName())
+	require.Equal(t, "Durov", p.LastName())
+	require.Equal(t, "en", p.LanguageCode())
+
+	require.True(t, bots.Profile{}.IsZero())
+}
+
+func TestParticipant_UpdateProfile(t *testing.T) {
+	prt := bots.MustNewParticipant(bots.NewParticipantID(1, "bot"))
+	require.True(t, prt.Profile().IsZero())
+
+	p := bots.NewProfile("durov", "Pavel", "Durov", "en")
+	prt.UpdateProfile(p)
+	require.Equal(t, p, prt.Profile())
+
+	// Пустой профиль, например, при рассылке, не затирает известные данные
+	prt.UpdateProfile(bots.Profile{})
+	require.Equal(t, p, prt.Profile())
+}
@@ -0,0 +1,308 @@
+package bots
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Request есть HTTP-запрос к внешней системе, который выполняется, когда тред переходит в
+// узел-запрос. В отличие от обычного узла, узел-запрос не ждёт сообщения пользователя: по
+// результату запроса тред сразу переходит в onSuccess или onFailure.
+//
+// URL, значения заголовков и тело являются шаблонами. Подстановка {{answers.N}} заменяется
+// ответом треда на узел с состоянием N, а {{vars.name}} - переменной треда name. Отсутствующие
+// значения заменяются пустой строкой.
+type Request struct {
+	method    string
+	url       string
+	headers   map[string]string
+	body      string
+	save      map[string]string // Переменная треда -> путь к полю в JSON-ответе
+	onSuccess State
+	onFailure State
+}
+
+// RenderedRequest есть Request с выполненными подстановками, готовый к отправке.
+type RenderedRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// RequestResult есть результат выполнения RenderedRequest. Нулевой StatusCode означает, что
+// ответ не был получен.
+type RequestResult struct {
+	StatusCode int
+	Body       []byte
+}
+
+var requestMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+var (
+	varNameRegexp     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	placeholderRegexp = regexp.MustCompile(`\{\{\s*(answers|vars)\.([A-Za-z0-9_]+)\s*\}\}`)
+)
+
+func NewRequest(
+	method string,
+	rawURL string,
+	headers map[string]string,
+	body string,
+	save map[string]string,
+	onSuccess State,
+	onFailure State,
+) (Request, error) {
+	method = strings.ToUpper(method)
+	if !slices.Contains(requestMethods, method) {
+		return Request{}, NewInvalidInputError(
+			"request-invalid-method", "expected one of GET, POST, PUT, PATCH, DELETE",
+			"field", "method", "method", method,
+		)
+	}
+
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return Request{}, NewInvalidInputError(
+			"request-invalid-url", "expected absolute http or https url", "field", "url",
+		)
+	}
+
+	for name := range headers {
+		if name == "" || strings.ContainsAny(name, " :\r\n") {
+			return Request{}, NewInvalidInputError(
+				"request-invalid-header", "invalid header name", "field", "headers", "header", name,
+			)
+		}
+	}
+
+	for name, path := range save {
+		if !varNameRegexp.MatchString(name) {
+			return Request{}, NewInvalidInputError(
+				"request-invalid-variable", "expected variable name of latin letters, digits and _",
+				"field", "save", "variable", name,
+			)
+		}
+		if path == "" {
+			return Request{}, NewInvalidInputError(
+				"request-empty-path", "expected not empty JSON path", "field", "save", "variable", name,
+			)
+		}
+	}
+
+	if onSuccess == ZeroState {
+		return Request{}, NewInvalidInputError("request-empty-on-success", "expected not empty state", "field", "onSuccess")
+	}
+	if onFailure == ZeroState {
+		return Request{}, NewInvalidInputError("request-empty-on-failure", "expected not empty state", "field", "onFailure")
+	}
+
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	if save == nil {
+		save = make(map[string]string)
+	}
+
+	return Request{
+		method:    method,
+		url:       rawURL,
+		headers:   headers,
+		body:      body,
+		save:      save,
+		onSuccess: onSuccess,
+		onFailure: onFailure,
+	}, nil
+}
+
+func MustNewRequest(
+	method string,
+	rawURL string,
+	headers map[string]string,
+	body string,
+	save map[string]string,
+	onSuccess State,
+	onFailure State,
+) Request {
+	r, err := NewRequest(method, rawURL, headers, body, save, onSuccess, onFailure)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Request) IsZero() bool {
+	return r.method == ""
+}
+
+func (r Request) Method() string {
+	return r.method
+}
+
+func (r Request) URL() string {
+	return r.url
+}
+
+func (r Request) Headers() map[string]string {
+	return r.headers
+}
+
+func (r Request) Body() string {
+	return r.body
+}
+
+func (r Request) Save() map[string]string {
+	return r.save
+}
+
+func (r Request) OnSuccess() State {
+	return r.onSuccess
+}
+
+func (r Request) OnFailure() State {
+	return r.onFailure
+}
+
+func (r Request) Equals(o Request) bool {
+	return r.method == o.method &&
+		r.url == o.url &&
+		maps.Equal(r.headers, o.headers) &&
+		r.body == o.body &&
+		maps.Equal(r.save, o.save) &&
+		r.onSuccess == o.onSuccess &&
+		r.onFailure == o.onFailure
+}
+
+// Render выполняет подстановки значений треда. Значения экранируются в соответствии с местом
+// подстановки: в URL - как компонент URL, в теле - как содержимое JSON-строки.
+func (r Request) Render(thr *Thread) RenderedRequest {
+	headers := make(map[string]string, len(r.headers))
+	for name, value := range r.headers {
+		headers[name] = render(value, thr, stripNewlines)
+	}
+	return RenderedRequest{
+		Method:  r.method,
+		URL:     render(r.url, thr, escapeURL),
+		Headers: headers,
+		Body:    render(r.body, thr, escapeJSON),
+	}
+}
+
+// Apply сохраняет в переменные треда поля ответа и сообщает, успешно ли выполнен запрос.
+// Запрос успешен, если получен ответ с кодом 2xx и в нём найдены все сохраняемые поля.
+func (r Request) Apply(thr *Thread, res RequestResult) bool {
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false
+	}
+	if len(r.save) == 0 {
+		return true
+	}
+
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(res.Body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return false
+	}
+
+	vars := make(map[string]string, len(r.save))
+	for name, path := range r.save {
+		value, ok := lookupJSON(doc, path)
+		if !ok {
+			return false
+		}
+		vars[name] = value
+	}
+	// Переменные сохраняются только при успехе, чтобы не оставить тред в промежуточном состоянии
+	for name, value := range vars {
+		thr.SetVar(name, value)
+	}
+	return true
+}
+
+// lookupJSON возвращает значение поля по пути вида "data.slots.0.title". Строки возвращаются
+// как есть, остальные значения - в виде JSON.
+func lookupJSON(doc any, path string) (string, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return "", false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", false
+			}
+			cur = v[i]
+		default:
+			return "", false
+		}
+	}
+
+	switch v := cur.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
+
+// render заменяет подстановки в шаблоне значениями треда, экранированными функцией escape.
+func render(tmpl string, thr *Thread, escape func(string) string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(tmpl, func(m string) string {
+		sub := placeholderRegexp.FindStringSubmatch(m)
+		return escape(lookupPlaceholder(thr, sub[1], sub[2]))
+	})
+}
+
+func lookupPlaceholder(thr *Thread, kind string, name string) string {
+	switch kind {
+	case "answers":
+		i, err := strconv.Atoi(name)
+		if err != nil {
+			return ""
+		}
+		state, err := NewState(i)
+		if err != nil {
+			return ""
+		}
+		return thr.Answers()[state].Text()
+	case "vars":
+		return thr.Vars()[name]
+	default:
+		return ""
+	}
+}
+
+func escapeURL(s string) string {
+	// QueryEscape кодирует пробел как "+", что допустимо только в query
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func escapeJSON(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
+
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", "", "\n", " ").Replace(s)
+}
+
+func noEscape(s string) string {
+	return s
+}
@@ -0,0 +1,98 @@
+package bots_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestNewRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		headers map[string]string
+		save    map[string]string
+		wantErr bool
+	}{
+		{name: "valid", method: "get", url: "https://example.com", save: map[string]string{"group": "data.group"}},
+		{name: "invalid method", method: "TRACE", url: "https://example.com", wantErr: true},
+		{name: "relative url", method: "GET", url: "/students", wantErr: true},
+		{name: "invalid header", method: "GET", url: "https://example.com", headers: map[string]string{"X Token": "1"}, wantErr: true},
+		{name: "invalid variable", method: "GET", url: "https://example.com", save: map[string]string{"1group": "group"}, wantErr: true},
+		{name: "empty path", method: "GET", url: "https://example.com", save: map[string]string{"group": ""}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := bots.NewRequest(
+				tt.method, tt.url, tt.headers, "", tt.save, bots.MustNewState(2), bots.MustNewState(3),
+			)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "GET", req.Method())
+		})
+	}
+}
+
+func TestRequest_Render(t *testing.T) {
+	req := bots.MustNewRequest(
+		"POST",
+		"https://example.com/students/{{answers.1}}?group={{ vars.group }}",
+		map[string]string{"X-Name": "{{answers.1}}"},
+		`{"name": "{{answers.1}}", "missing": "{{vars.missing}}"}`,
+		nil,
+		bots.MustNewState(2),
+		bots.MustNewState(3),
+	)
+	thread := bots.MustNewThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+	thread.SaveAnswer(bots.MustNewMessage("Иван \"Ваня\"\nИванов"))
+	thread.SetVar("group", "ИУ7 11Б")
+
+	rendered := req.Render(thread)
+	require.Equal(t, "POST", rendered.Method)
+	require.Equal(t,
+		"https://example.com/students/%D0%98%D0%B2%D0%B0%D0%BD%20%22%D0%92%D0%B0%D0%BD%D1%8F%22%0A%D0%98%D0%B2%D0%B0%D0%BD%D0%BE%D0%B2"+
+			"?group=%D0%98%D0%A37%2011%D0%91",
+		rendered.URL,
+	)
+	require.Equal(t, map[string]string{"X-Name": `Иван "Ваня" Иванов`}, rendered.Headers)
+	require.Equal(t, `{"name": "Иван \"Ваня\"\nИванов", "missing": ""}`, rendered.Body)
+}
+
+func TestRequest_Apply(t *testing.T) {
+	req := bots.MustNewRequest(
+		"GET", "https://example.com", nil, "",
+		map[string]string{"group": "data.group", "first": "data.slots.0", "count": "data.count"},
+		bots.MustNewState(2), bots.MustNewState(3),
+	)
+	body := []byte(`{"data": {"group": "ИУ7-11Б", "slots": ["10:00", "11:00"], "count": 2}}`)
+
+	t.Run("success", func(t *testing.T) {
+		thread := bots.MustNewThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		require.True(t, req.Apply(thread, bots.RequestResult{StatusCode: 200, Body: body}))
+		require.Equal(t, map[string]string{"group": "ИУ7-11Б", "first": "10:00", "count": "2"}, thread.Vars())
+	})
+
+	t.Run("non 2xx", func(t *testing.T) {
+		thread := bots.MustNewThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		require.False(t, req.Apply(thread, bots.RequestResult{StatusCode: 404, Body: body}))
+		require.Empty(t, thread.Vars())
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		thread := bots.MustNewThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		require.False(t, req.Apply(thread, bots.RequestResult{StatusCode: 200, Body: []byte(`{"data": {"group": "ИУ7-11Б"}}`)}))
+		require.Empty(t, thread.Vars())
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		thread := bots.MustNewThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		require.False(t, req.Apply(thread, bots.RequestResult{}))
+	})
+}
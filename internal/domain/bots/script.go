@@ -3,11 +3,15 @@ package bots
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"strconv"
+	"time"
 )
 
 var ErrNoStartedThread = errors.New("has no started thread")
 
+var ErrNoPendingRequest = errors.New("has no pending request")
+
 type EntryNotFoundError struct {
 	key EntryKey
 }
@@ -68,7 +72,7 @@ func (s Script) Entry(prt *Participant, key EntryKey) ([]BotMessage, error) {
 		return nil, fmt.Errorf("no bot node with state %d", thread.State())
 	}
 
-	return current.BotMessages(), nil
+	return current.RenderBotMessages(thread), nil
 }
 
 func (s Script) Process(prt *Participant, in Message) ([]BotMessage, error) {
@@ -91,8 +95,59 @@ func (s Script) Process(prt *Participant, in Message) ([]BotMessage, error) {
 		return nil, nil
 	}
 	edge.Operation().Apply(thread, in)
+	if _, noop := edge.Operation().(NoOp); !noop {
+		prt.record(AnswerSaved{
+			ParticipantID: prt.ID(),
+			ThreadID:      thread.ID(),
+			State:         thread.State(),
+			Title:         current.Title(),
+			Answer:        thread.Answers()[thread.State()],
+			SavedAt:       time.Now(),
+		})
+	}
+
+	return s.stepTo(prt, thread, edge.To())
+}
+
+// PendingRequest возвращает запрос, который необходимо выполнить, если активный тред участника
+// находится в узле-запросе.
+func (s Script) PendingRequest(prt *Participant) (RenderedRequest, bool) {
+	thread := prt.ActiveThread()
+	if thread == nil {
+		return RenderedRequest{}, false
+	}
+	current, ok := s.nodes[thread.State()]
+	if !ok || !current.IsRequest() {
+		return RenderedRequest{}, false
+	}
+	return current.Request().Render(thread), true
+}
 
-	nextState := edge.To()
+// CompleteRequest применяет результат запроса узла-запроса, в котором находится активный тред,
+// и переводит тред в узел, соответствующий успеху или неудаче запроса.
+func (s Script) CompleteRequest(prt *Participant, res RequestResult) ([]BotMessage, error) {
+	thread := prt.ActiveThread()
+	if thread == nil {
+		return nil, ErrNoStartedThread
+	}
+
+	current, ok := s.nodes[thread.State()]
+	if !ok {
+		return nil, fmt.Errorf("no bot node with state %d", thread.State())
+	}
+	if !current.IsRequest() {
+		return nil, ErrNoPendingRequest
+	}
+
+	req := current.Request()
+	if req.Apply(thread, res) {
+		return s.stepTo(prt, thread, req.OnSuccess())
+	}
+	return s.stepTo(prt, thread, req.OnFailure())
+}
+
+// stepTo переводит тред в узел nextState и возвращает его сообщения.
+func (s Script) stepTo(prt *Participant, thread *Thread, nextState State) ([]BotMessage, error) {
 	next, ok := s.nodes[nextState]
 	if !ok {
 		// Аналогично, схемой гарантируется, что следующий state будет существовать.
@@ -100,8 +155,23 @@ func (s Script) Process(prt *Participant, in Message) ([]BotMessage, error) {
 	}
 
 	thread.StepTo(nextState)
+	if next.IsFinal() {
+		titles := make(map[State]string, len(thread.Answers()))
+		for state := range thread.Answers() {
+			titles[state] = s.nodes[state].Title()
+		}
+		prt.record(ThreadCompleted{
+			ParticipantID: prt.ID(),
+			ThreadID:      thread.ID(),
+			Key:           thread.Key(),
+			Answers:       maps.Clone(thread.Answers()),
+			Titles:        titles,
+			StartedAt:     thread.StartedAt(),
+			CompletedAt:   time.Now(),
+		})
+	}
 
-	return next.BotMessages(), nil
+	return next.RenderBotMessages(thread), nil
 }
 
 func (s Script) Nodes() []Node {
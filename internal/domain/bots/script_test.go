@@ -173,6 +173,45 @@ func TestScript_EntryNProcess(t *testing.T) {
 	}, thread.Answers())
 }
 
+func TestScript_Events(t *testing.T) {
+	script := bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewNode(bots.MustNewState(1), "ФИО", []bots.Edge{
+				bots.NewEdge(bots.AlwaysTruePredicate{}, bots.MustNewState(2), bots.SaveOp{}),
+			}, []bots.Message{bots.MustNewMessage("Введите своё ФИО")}, nil),
+			bots.MustNewNode(bots.MustNewState(2), "Конец", nil, []bots.Message{
+				bots.MustNewMessage("Спасибо за регистрацию!"),
+			}, nil),
+		},
+		[]bots.Entry{bots.MustNewEntry("start", bots.MustNewState(1))},
+	)
+	prt := bots.MustNewParticipant(bots.NewParticipantID(1, "bot"))
+
+	_, err := script.Entry(prt, "start")
+	require.NoError(t, err)
+	require.Len(t, prt.Events(), 1)
+	started, ok := prt.Events()[0].(bots.ThreadStarted)
+	require.True(t, ok)
+	require.Equal(t, prt.ActiveThread().ID(), started.ThreadID)
+	require.Equal(t, bots.EntryKey("start"), started.Key)
+
+	_, err = script.Process(prt, bots.MustNewMessage("Иванов Иван Иванович"))
+	require.NoError(t, err)
+	require.Len(t, prt.Events(), 3)
+
+	saved, ok := prt.Events()[1].(bots.AnswerSaved)
+	require.True(t, ok)
+	require.Equal(t, "ФИО", saved.Title)
+	require.Equal(t, bots.MustNewMessage("Иванов Иван Иванович"), saved.Answer)
+
+	completed, ok := prt.Events()[2].(bots.ThreadCompleted)
+	require.True(t, ok)
+	require.Equal(t, map[bots.State]bots.Message{
+		bots.MustNewState(1): bots.MustNewMessage("Иванов Иван Иванович"),
+	}, completed.Answers)
+	require.Equal(t, map[bots.State]string{bots.MustNewState(1): "ФИО"}, completed.Titles)
+}
+
 func TestScript_Entry(t *testing.T) {
 	script := buildSurveyScript()
 	prtID := bots.NewParticipantID(bots.UserID(1), "bot")
@@ -238,3 +277,72 @@ func TestNewScript(t *testing.T) {
 		// Какой именно state - неизвестно, порядок обхода map не определён.
 	})
 }
+
+func TestScript_Request(t *testing.T) {
+	script := bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewNode(bots.MustNewState(1), "Номер студенческого", []bots.Edge{
+				bots.NewEdge(bots.AlwaysTruePredicate{}, bots.MustNewState(2), bots.SaveOp{}),
+			}, []bots.Message{bots.MustNewMessage("Введите номер студенческого")}, nil),
+			bots.MustNewRequestNode(bots.MustNewState(2), "Поиск студента", nil, bots.MustNewRequest(
+				"GET", "https://example.com/students/{{answers.1}}", nil, "",
+				map[string]string{"group": "group"}, bots.MustNewState(3), bots.MustNewState(4),
+			)),
+			bots.MustNewNode(bots.MustNewState(3), "Найден", nil, []bots.Message{
+				bots.MustNewMessage("Ваша группа: {{vars.group}}"),
+			}, nil),
+			bots.MustNewNode(bots.MustNewState(4), "Не найден", nil, []bots.Message{
+				bots.MustNewMessage("Студент {{answers.1}} не найден"),
+			}, nil),
+		},
+		[]bots.Entry{bots.MustNewEntry("start", bots.MustNewState(1))},
+	)
+
+	t.Run("success", func(t *testing.T) {
+		prt := bots.MustNewParticipant(bots.NewParticipantID(1, "bot"))
+		_, err := script.Entry(prt, "start")
+		require.NoError(t, err)
+		_, ok := script.PendingRequest(prt)
+		require.False(t, ok)
+
+		msgs, err := script.Process(prt, bots.MustNewMessage("21У123"))
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+
+		req, ok := script.PendingRequest(prt)
+		require.True(t, ok)
+		require.Equal(t, "https://example.com/students/21%D0%A3123", req.URL)
+
+		msgs, err = script.CompleteRequest(prt, bots.RequestResult{StatusCode: 200, Body: []byte(`{"group": "ИУ7-11Б"}`)})
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		require.Equal(t, "Ваша группа: ИУ7-11Б", msgs[0].Text())
+		require.Equal(t, bots.MustNewState(3), prt.ActiveThread().State())
+
+		_, ok = script.PendingRequest(prt)
+		require.False(t, ok)
+		_, err = script.CompleteRequest(prt, bots.RequestResult{StatusCode: 200})
+		require.ErrorIs(t, err, bots.ErrNoPendingRequest)
+
+		var completed bool
+		for _, e := range prt.Events() {
+			_, ok := e.(bots.ThreadCompleted)
+			completed = completed || ok
+		}
+		require.True(t, completed)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		prt := bots.MustNewParticipant(bots.NewParticipantID(2, "bot"))
+		_, err := script.Entry(prt, "start")
+		require.NoError(t, err)
+		_, err = script.Process(prt, bots.MustNewMessage("21У123"))
+		require.NoError(t, err)
+
+		msgs, err := script.CompleteRequest(prt, bots.RequestResult{StatusCode: 500})
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		require.Equal(t, "Студент 21У123 не найден", msgs[0].Text())
+		require.Equal(t, bots.MustNewState(4), prt.ActiveThread().State())
+	})
+}
@@ -0,0 +1,45 @@
+package bots
+
+// TelegramAccount есть аккаунт бота в Telegram, которому принадлежит токен. Определяется методом
+// getMe при сохранении бота.
+type TelegramAccount struct {
+	id       int64
+	username string
+}
+
+func NewTelegramAccount(id int64, username string) (TelegramAccount, error) {
+	if id <= 0 {
+		return TelegramAccount{}, NewInvalidInputError(
+			"telegram-account-invalid-id", "expected positive telegram bot id", "field", "id",
+		)
+	}
+
+	if username == "" {
+		return TelegramAccount{}, NewInvalidInputError(
+			"telegram-account-empty-username", "expected not empty telegram bot username", "field", "username",
+		)
+	}
+
+	return TelegramAccount{id: id, username: username}, nil
+}
+
+func MustNewTelegramAccount(id int64, username string) TelegramAccount {
+	a, err := NewTelegramAccount(id, username)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a TelegramAccount) ID() int64 {
+	return a.id
+}
+
+func (a TelegramAccount) Username() string {
+	return a.username
+}
+
+// IsZero сообщает, что аккаунт неизвестен: бот сохранён до проверки токенов.
+func (a TelegramAccount) IsZero() bool {
+	return a == TelegramAccount{}
+}
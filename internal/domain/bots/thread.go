@@ -17,6 +17,7 @@ type Thread struct {
 	key       EntryKey
 	state     State
 	answers   map[State]Message
+	vars      map[string]string // Переменные, сохранённые узлами-запросами
 	startedAt time.Time
 }
 
@@ -30,6 +31,7 @@ func NewThread(entry Entry) (*Thread, error) {
 		key:       entry.Key(),
 		state:     entry.Start(),
 		answers:   make(map[State]Message),
+		vars:      make(map[string]string),
 		startedAt: time.Now(),
 	}, nil
 }
@@ -48,6 +50,7 @@ func (t *Thread) Clone() *Thread {
 		key:       t.key,
 		state:     t.state,
 		answers:   maps.Clone(t.answers),
+		vars:      maps.Clone(t.vars),
 		startedAt: t.startedAt,
 	}
 }
@@ -57,6 +60,7 @@ func (t *Thread) Equals(other *Thread) bool {
 		t.key == other.key &&
 		t.state == other.state &&
 		maps.Equal(t.answers, other.answers) &&
+		maps.Equal(t.vars, other.vars) &&
 		t.startedAt.Equal(other.startedAt)
 }
 
@@ -81,6 +85,11 @@ func (t *Thread) AppendAnswer(ans Message) {
 	}
 }
 
+// SetVar сохраняет значение переменной треда, перезаписывая предыдущее.
+func (t *Thread) SetVar(name string, value string) {
+	t.vars[name] = value
+}
+
 func (t *Thread) ID() ThreadID {
 	return t.id
 }
@@ -97,6 +106,10 @@ func (t *Thread) Answers() map[State]Message {
 	return t.answers
 }
 
+func (t *Thread) Vars() map[string]string {
+	return t.vars
+}
+
 func (t *Thread) StartedAt() time.Time {
 	return t.startedAt
 }
@@ -132,6 +145,7 @@ func UnmarshallThread(
 	key string,
 	state int,
 	answers map[State]Message,
+	vars map[string]string,
 	startedAt time.Time,
 ) (*Thread, error) {
 	if id == "" {
@@ -151,6 +165,10 @@ func UnmarshallThread(
 		answers = make(map[State]Message)
 	}
 
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+
 	if startedAt.IsZero() {
 		return nil, errors.New("startedAt is empty")
 	}
@@ -160,6 +178,7 @@ func UnmarshallThread(
 		key:       EntryKey(key),
 		state:     s,
 		answers:   answers,
+		vars:      vars,
 		startedAt: startedAt,
 	}, nil
 }
@@ -0,0 +1,133 @@
+package bots
+
+import (
+	"net/url"
+	"slices"
+	"time"
+)
+
+type WebhookID string
+
+// webhookSecretMinLength есть минимальная длина секрета, которым подписываются запросы.
+const webhookSecretMinLength = 16
+
+// Webhook есть подписка внешней системы на события бота. Каждое событие, на которое
+// подписан Webhook, отправляется POST-запросом на URL с подписью секретом.
+type Webhook struct {
+	id        WebhookID
+	botID     BotID
+	url       string
+	secret    string
+	events    []string
+	createdAt time.Time
+}
+
+func NewWebhook(id WebhookID, botID BotID, rawURL string, secret string, events []string) (*Webhook, error) {
+	if id == "" {
+		return nil, NewInvalidInputError("webhook-empty-id", "expected not empty webhook id", "field", "id")
+	}
+
+	if botID == "" {
+		return nil, NewInvalidInputError("webhook-empty-bot-id", "expected not empty bot id", "field", "botId")
+	}
+
+	w := &Webhook{
+		id:        id,
+		botID:     botID,
+		createdAt: time.Now().Truncate(time.Second),
+	}
+	if err := w.Update(rawURL, secret, events); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func MustNewWebhook(id WebhookID, botID BotID, rawURL string, secret string, events []string) *Webhook {
+	w, err := NewWebhook(id, botID, rawURL, secret, events)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Update заменяет URL, секрет и список событий подписки.
+func (w *Webhook) Update(rawURL string, secret string, events []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewInvalidInputError(
+			"webhook-invalid-url", "expected absolute http or https url", "field", "url",
+		)
+	}
+
+	if len(secret) < webhookSecretMinLength {
+		return NewInvalidInputError(
+			"webhook-short-secret", "expected secret of at least 16 characters", "field", "secret",
+		)
+	}
+
+	if len(events) == 0 {
+		return NewInvalidInputError(
+			"webhook-empty-events", "expected at least one event", "field", "events",
+		)
+	}
+	known := EventNames()
+	for _, e := range events {
+		if !slices.Contains(known, e) {
+			return NewInvalidInputError(
+				"webhook-unknown-event", "unknown event", "field", "events", "event", e,
+			)
+		}
+	}
+
+	w.url = rawURL
+	w.secret = secret
+	events = slices.Clone(events)
+	slices.Sort(events)
+	w.events = slices.Compact(events)
+	return nil
+}
+
+// SubscribedTo сообщает, подписан ли Webhook на событие с именем event.
+func (w *Webhook) SubscribedTo(event string) bool {
+	return slices.Contains(w.events, event)
+}
+
+func (w *Webhook) ID() WebhookID {
+	return w.id
+}
+
+func (w *Webhook) BotID() BotID {
+	return w.botID
+}
+
+func (w *Webhook) URL() string {
+	return w.url
+}
+
+func (w *Webhook) Secret() string {
+	return w.secret
+}
+
+func (w *Webhook) Events() []string {
+	return w.events
+}
+
+func (w *Webhook) CreatedAt() time.Time {
+	return w.createdAt
+}
+
+func UnmarshallWebhook(
+	id string,
+	botID string,
+	rawURL string,
+	secret string,
+	events []string,
+	createdAt time.Time,
+) (*Webhook, error) {
+	w, err := NewWebhook(WebhookID(id), BotID(botID), rawURL, secret, events)
+	if err != nil {
+		return nil, err
+	}
+	w.createdAt = createdAt
+	return w, nil
+}
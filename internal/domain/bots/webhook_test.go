@@ -0,0 +1,70 @@
+package bots_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestNewWebhook(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	tests := []struct {
+		name     string
+		url      string
+		secret   string
+		events   []string
+		wantCode string
+	}{
+		{
+			name:   "Valid webhook",
+			url:    "https://example.com/hook",
+			secret: secret,
+			events: []string{bots.EventThreadCompleted},
+		},
+		{
+			name:     "Relative url",
+			url:      "/hook",
+			secret:   secret,
+			events:   []string{bots.EventThreadCompleted},
+			wantCode: "webhook-invalid-url",
+		},
+		{
+			name:     "Short secret",
+			url:      "https://example.com/hook",
+			secret:   "secret",
+			events:   []string{bots.EventThreadCompleted},
+			wantCode: "webhook-short-secret",
+		},
+		{
+			name:     "No events",
+			url:      "https://example.com/hook",
+			secret:   secret,
+			wantCode: "webhook-empty-events",
+		},
+		{
+			name:     "Unknown event",
+			url:      "https://example.com/hook",
+			secret:   secret,
+			events:   []string{"bot_exploded"},
+			wantCode: "webhook-unknown-event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := bots.NewWebhook("hook", "bot", tt.url, tt.secret, tt.events)
+			if tt.wantCode != "" {
+				var iiErr bots.InvalidInputError
+				require.ErrorAs(t, err, &iiErr)
+				require.Equal(t, tt.wantCode, iiErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, w.SubscribedTo(bots.EventThreadCompleted))
+			require.False(t, w.SubscribedTo(bots.EventBotEnabled))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package events
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// BackendGoChannel доставляет события только подписчикам внутри процесса.
+	BackendGoChannel = "gochannel"
+	// BackendPostgres дополнительно сохраняет события в Postgres для подписчиков watermill-sql
+	// в других сервисах.
+	BackendPostgres = "postgres"
+)
+
+// NewExternalPublisher возвращает транспорт для публикации событий во внешние сервисы по имени
+// backend или nil, если события доставляются только внутри процесса.
+func NewExternalPublisher(backend string, db *sqlx.DB, l *slog.Logger) (message.Publisher, error) {
+	switch backend {
+	case "", BackendGoChannel:
+		return nil, nil //nolint:nilnil // внешний транспорт не требуется
+	case BackendPostgres:
+		return NewPostgresPublisher(db.DB, l)
+	default:
+		return nil, fmt.Errorf(
+			"unknown events backend %q, expected one of %q, %q", backend, BackendGoChannel, BackendPostgres,
+		)
+	}
+}
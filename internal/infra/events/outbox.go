@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	wsql "github.com/ThreeDotsLabs/watermill-sql/v3/pkg/sql"
+	"github.com/ThreeDotsLabs/watermill/components/forwarder"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs/sl"
+)
+
+// OutboxTopic есть топик watermill-sql, в таблицу которого события сохраняются в конвертах
+// форвардера до пересылки в транспорты.
+const OutboxTopic = "bots_outbox"
+
+// outboxConsumerGroup разделяет смещения таблицы outbox между репликами сервиса, поэтому каждое
+// событие пересылается один раз.
+const outboxConsumerGroup = "itsreg-bots"
+
+// Outbox сохраняет доменные события в таблицу топика OutboxTopic в той же транзакции, что
+// и породившие их изменения: событие публикуется тогда и только тогда, когда транзакция
+// зафиксирована.
+type Outbox struct {
+	l *slog.Logger
+}
+
+func NewOutbox(l *slog.Logger) *Outbox {
+	return &Outbox{l: l}
+}
+
+func (o *Outbox) PublishTx(ctx context.Context, tx *sql.Tx, events ...bots.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	// Схема создаётся форвардером при запуске: CREATE TABLE внутри транзакции неявно зафиксировал бы её
+	pub, err := wsql.NewPublisher(tx, wsql.PublisherConfig{
+		SchemaAdapter: wsql.DefaultPostgreSQLSchema{},
+	}, sl.NewWatermillLoggerAdapter(o.l))
+	if err != nil {
+		return err
+	}
+	return NewPublisher(o.l, wrapOutbox(pub)).Publish(ctx, events...)
+}
+
+// NewOutboxPublisher возвращает издателя, сохраняющего события в таблицу outbox вне транзакции.
+// Используется для событий, не связанных с изменениями участников.
+func NewOutboxPublisher(db *sql.DB, l *slog.Logger) (message.Publisher, error) {
+	pub, err := wsql.NewPublisher(db, wsql.PublisherConfig{
+		SchemaAdapter:        wsql.DefaultPostgreSQLSchema{},
+		AutoInitializeSchema: true,
+	}, sl.NewWatermillLoggerAdapter(l))
+	if err != nil {
+		return nil, err
+	}
+	return wrapOutbox(pub), nil
+}
+
+// NewForwarder возвращает форвардер, пересылающий события из таблицы outbox во все транспорты pubs.
+// Таблица outbox и смещения подписчика создаются сразу, до первой публикации в транзакции.
+func NewForwarder(db *sql.DB, l *slog.Logger, pubs ...message.Publisher) (*forwarder.Forwarder, error) {
+	logger := sl.NewWatermillLoggerAdapter(l)
+	sub, err := wsql.NewSubscriber(db, wsql.SubscriberConfig{
+		ConsumerGroup:    outboxConsumerGroup,
+		SchemaAdapter:    wsql.DefaultPostgreSQLSchema{},
+		OffsetsAdapter:   wsql.DefaultPostgreSQLOffsetsAdapter{},
+		InitializeSchema: true,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err = sub.SubscribeInitialize(OutboxTopic); err != nil {
+		return nil, err
+	}
+	return forwarder.NewForwarder(sub, fanOut(pubs), logger, forwarder.Config{ForwarderTopic: OutboxTopic})
+}
+
+func wrapOutbox(pub message.Publisher) message.Publisher {
+	return forwarder.NewPublisher(pub, forwarder.PublisherConfig{ForwarderTopic: OutboxTopic})
+}
+
+// fanOut публикует копию каждого сообщения во все транспорты. Если один из транспортов вернул
+// ошибку, форвардер повторит пересылку, и остальные транспорты могут получить событие повторно.
+type fanOut []message.Publisher
+
+func (f fanOut) Publish(topic string, messages ...*message.Message) error {
+	return publishAll(f, topic, messages...)
+}
+
+// Close не закрывает транспорты: ими владеет вызывающий код.
+func (f fanOut) Close() error {
+	return nil
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// Представления доменных событий в сообщениях Watermill. Формат является публичным контрактом
+// для внешних подписчиков, поэтому не зависит от внутреннего устройства bots.Event.
+
+type ThreadStarted struct {
+	BotID     string    `json:"botId"`
+	ChatID    int64     `json:"chatId"`
+	UserID    int64     `json:"userId"`
+	ThreadID  string    `json:"threadId"`
+	Key       string    `json:"key"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+type AnswerSaved struct {
+	BotID    string    `json:"botId"`
+	ChatID   int64     `json:"chatId"`
+	UserID   int64     `json:"userId"`
+	ThreadID string    `json:"threadId"`
+	State    int       `json:"state"`
+	Title    string    `json:"title"`
+	Answer   string    `json:"answer"`
+	SavedAt  time.Time `json:"savedAt"`
+}
+
+type ThreadCompleted struct {
+	BotID       string            `json:"botId"`
+	ChatID      int64             `json:"chatId"`
+	UserID      int64             `json:"userId"`
+	ThreadID    string            `json:"threadId"`
+	Key         string            `json:"key"`
+	Answers     map[string]string `json:"answers"` // Ответы по номерам состояний
+	Titles      map[string]string `json:"titles"`  // Заголовки узлов по номерам состояний
+	StartedAt   time.Time         `json:"startedAt"`
+	CompletedAt time.Time         `json:"completedAt"`
+}
+
+type BotEnabled struct {
+	BotID     string    `json:"botId"`
+	EnabledAt time.Time `json:"enabledAt"`
+}
+
+type BotDisabled struct {
+	BotID      string    `json:"botId"`
+	DisabledAt time.Time `json:"disabledAt"`
+}
+
+type MailingFinished struct {
+	BotID      string    `json:"botId"`
+	Key        string    `json:"key"`
+	Recipients int       `json:"recipients"`
+	Skipped    int       `json:"skipped"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+func payloadFromEvent(e bots.Event) any {
+	switch e := e.(type) {
+	case bots.ThreadStarted:
+		return ThreadStarted{
+			BotID:     string(e.ParticipantID.BotID()),
+			ChatID:    int64(e.ParticipantID.ChatID()),
+			UserID:    int64(e.ParticipantID.UserID()),
+			ThreadID:  string(e.ThreadID),
+			Key:       string(e.Key),
+			StartedAt: e.StartedAt,
+		}
+	case bots.AnswerSaved:
+		return AnswerSaved{
+			BotID:    string(e.ParticipantID.BotID()),
+			ChatID:   int64(e.ParticipantID.ChatID()),
+			UserID:   int64(e.ParticipantID.UserID()),
+			ThreadID: string(e.ThreadID),
+			State:    e.State.Int(),
+			Title:    e.Title,
+			Answer:   e.Answer.Text(),
+			SavedAt:  e.SavedAt,
+		}
+	case bots.ThreadCompleted:
+		answers := make(map[string]string, len(e.Answers))
+		titles := make(map[string]string, len(e.Titles))
+		for state, msg := range e.Answers {
+			answers[strconv.Itoa(state.Int())] = msg.Text()
+		}
+		for state, title := range e.Titles {
+			titles[strconv.Itoa(state.Int())] = title
+		}
+		return ThreadCompleted{
+			BotID:       string(e.ParticipantID.BotID()),
+			ChatID:      int64(e.ParticipantID.ChatID()),
+			UserID:      int64(e.ParticipantID.UserID()),
+			ThreadID:    string(e.ThreadID),
+			Key:         string(e.Key),
+			Answers:     answers,
+			Titles:      titles,
+			StartedAt:   e.StartedAt,
+			CompletedAt: e.CompletedAt,
+		}
+	case bots.BotEnabled:
+		return BotEnabled{
+			BotID:     string(e.BotID),
+			EnabledAt: e.EnabledAt,
+		}
+	case bots.BotDisabled:
+		return BotDisabled{
+			BotID:      string(e.BotID),
+			DisabledAt: e.DisabledAt,
+		}
+	case bots.MailingFinished:
+		return MailingFinished{
+			BotID:      string(e.BotID),
+			Key:        string(e.Key),
+			Recipients: e.Recipients,
+			Skipped:    e.Skipped,
+			FinishedAt: e.FinishedAt,
+		}
+	default:
+		// Неизвестное событие публикуется как есть
+		return e
+	}
+}
@@ -0,0 +1,21 @@
+package events
+
+import (
+	"database/sql"
+	"log/slog"
+
+	wsql "github.com/ThreeDotsLabs/watermill-sql/v3/pkg/sql"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs/sl"
+)
+
+// NewPostgresPublisher возвращает издателя watermill-sql, сохраняющего сообщения в таблицы
+// "watermill_<topic>" схемы PostgreSQL по умолчанию, поэтому другие сервисы могут читать события
+// стандартным подписчиком watermill-sql.
+func NewPostgresPublisher(db *sql.DB, l *slog.Logger) (message.Publisher, error) {
+	return wsql.NewPublisher(db, wsql.PublisherConfig{
+		SchemaAdapter:        wsql.DefaultPostgreSQLSchema{},
+		AutoInitializeSchema: true,
+	}, sl.NewWatermillLoggerAdapter(l))
+}
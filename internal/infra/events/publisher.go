@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// TopicPrefix есть общий префикс топиков доменных событий: событие с именем "thread_completed"
+// публикуется в топик "bots.thread_completed".
+const TopicPrefix = "bots."
+
+// MetadataEvent есть ключ метаданных сообщения Watermill с именем события.
+const MetadataEvent = "event"
+
+// Publisher публикует доменные события в Watermill. Транспорты определяются переданными
+// message.Publisher, поэтому backend можно заменить, не затрагивая команды.
+type Publisher struct {
+	pubs []message.Publisher
+	l    *slog.Logger
+}
+
+func NewPublisher(l *slog.Logger, pubs ...message.Publisher) *Publisher {
+	return &Publisher{
+		pubs: pubs,
+		l:    l,
+	}
+}
+
+func (p *Publisher) Publish(ctx context.Context, events ...bots.Event) error {
+	const op = "events.Publisher.Publish"
+
+	for _, e := range events {
+		payload, err := json.Marshal(payloadFromEvent(e))
+		if err != nil {
+			return fmt.Errorf("marshalling event %s: %w", e.EventName(), err)
+		}
+
+		msg := message.NewMessage(watermill.NewUUID(), payload)
+		msg.Metadata.Set(MetadataEvent, e.EventName())
+		msg.SetContext(ctx)
+
+		err = publishAll(p.pubs, Topic(e.EventName()), msg)
+		if err != nil {
+			p.l.ErrorContext(ctx, "failed to publish event",
+				slog.String("op", op),
+				slog.String("event", e.EventName()),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("publishing event %s: %w", e.EventName(), err)
+		}
+	}
+
+	return nil
+}
+
+// publishAll публикует сообщения во все транспорты pubs. Сообщение Watermill нельзя публиковать
+// повторно, поэтому каждый транспорт получает свои копии с тем же контекстом.
+func publishAll(pubs []message.Publisher, topic string, messages ...*message.Message) error {
+	for _, pub := range pubs {
+		copies := make([]*message.Message, len(messages))
+		for i, msg := range messages {
+			copies[i] = msg.Copy()
+			copies[i].SetContext(msg.Context())
+		}
+		if err := pub.Publish(topic, copies...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Topic возвращает топик, в который публикуются события с именем name.
+func Topic(name string) string {
+	return TopicPrefix + name
+}
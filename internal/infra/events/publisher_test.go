@@ -0,0 +1,52 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+)
+
+func TestPublisher_Publish(t *testing.T) {
+	bus := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	t.Cleanup(func() { _ = bus.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	sub, err := bus.Subscribe(ctx, events.Topic("thread_completed"))
+	require.NoError(t, err)
+
+	p := events.NewPublisher(logs.DefaultLogger(), bus)
+	err = p.Publish(ctx, bots.ThreadCompleted{
+		ParticipantID: bots.NewParticipantID(42, "bot"),
+		ThreadID:      "thread",
+		Key:           "start",
+		Answers: map[bots.State]bots.Message{
+			bots.MustNewState(2): bots.MustNewMessage("Иванов Иван Иванович"),
+		},
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-sub:
+		msg.Ack()
+		require.Equal(t, "thread_completed", msg.Metadata.Get(events.MetadataEvent))
+
+		var payload events.ThreadCompleted
+		require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+		require.Equal(t, "bot", payload.BotID)
+		require.Equal(t, int64(42), payload.UserID)
+		require.Equal(t, map[string]string{"2": "Иванов Иван Иванович"}, payload.Answers)
+	case <-ctx.Done():
+		t.Fatal("event was not delivered")
+	}
+}
@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// payload есть представление port.OutboxMessage в сообщении Watermill.
+type payload struct {
+	ID       int64    `json:"id"`
+	BotID    string   `json:"botId"`
+	ChatID   int64    `json:"chatId"`
+	UserID   int64    `json:"userId"`
+	Text     string   `json:"text"`
+	Options  []string `json:"options"`
+	Attempts int      `json:"attempts"`
+}
+
+func payloadFromMessage(msg port.OutboxMessage) payload {
+	opts := make([]string, len(msg.Message.Options()))
+	for i, opt := range msg.Message.Options() {
+		opts[i] = opt.String()
+	}
+	return payload{
+		ID:       msg.ID,
+		BotID:    string(msg.ParticipantID.BotID()),
+		ChatID:   int64(msg.ParticipantID.ChatID()),
+		UserID:   int64(msg.ParticipantID.UserID()),
+		Text:     msg.Message.Text(),
+		Options:  opts,
+		Attempts: msg.Attempts,
+	}
+}
+
+func (p payload) toMessage() (port.OutboxMessage, error) {
+	text, err := bots.NewMessage(p.Text)
+	if err != nil {
+		return port.OutboxMessage{}, err
+	}
+	opts := make([]bots.Option, len(p.Options))
+	for i, s := range p.Options {
+		opts[i], err = bots.NewOption(s)
+		if err != nil {
+			return port.OutboxMessage{}, err
+		}
+	}
+	return port.OutboxMessage{
+		ID: p.ID,
+		ParticipantID: bots.NewChatParticipantID(
+			bots.ChatID(p.ChatID), bots.UserID(p.UserID), bots.BotID(p.BotID),
+		),
+		Message:  text.PromoteToBotMessage(opts),
+		Attempts: p.Attempts,
+	}, nil
+}
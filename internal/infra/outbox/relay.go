@@ -0,0 +1,184 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs/sl"
+)
+
+const (
+	// pollInterval есть период опроса очереди, если новых сообщений не ожидается.
+	pollInterval = time.Second
+	// claimBatch ограничивает количество сообщений, захватываемых за один опрос.
+	claimBatch = 100
+	// claimLock есть время, на которое сообщение захватывается репликой. По его истечении
+	// неотправленное сообщение (например, после падения реплики) будет захвачено повторно.
+	claimLock = time.Minute
+	// closeTimeout ограничивает ожидание отправки уже захваченных сообщений при остановке.
+	closeTimeout = 10 * time.Second
+
+	topicPrefix = "outbox.messages."
+)
+
+// Relay доставляет сообщения из outbox. Захваченные из базы сообщения публикуются в Watermill
+// и распределяются между обработчиками по чатам. Порядок сообщений одного чата гарантирует
+// сама очередь: следующее сообщение чата не захватывается, пока не обработано предыдущее.
+type Relay struct {
+	repo    port.OutboxRepository
+	handler port.OutboxHandler
+	shards  int
+
+	pubSub *gochannel.GoChannel
+	router *message.Router
+	wake   chan struct{}
+	l      *slog.Logger
+}
+
+func NewRelay(
+	repo port.OutboxRepository,
+	handler port.OutboxHandler,
+	shards int,
+	l *slog.Logger,
+) (*Relay, error) {
+	if shards < 1 {
+		shards = 1
+	}
+	logger := sl.NewWatermillLoggerAdapter(l)
+
+	router, err := message.NewRouter(message.RouterConfig{CloseTimeout: closeTimeout}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Relay{
+		repo:    repo,
+		handler: handler,
+		shards:  shards,
+		pubSub:  gochannel.NewGoChannel(gochannel.Config{OutputChannelBuffer: claimBatch}, logger),
+		router:  router,
+		wake:    make(chan struct{}, 1),
+		l:       l,
+	}
+	for i := range shards {
+		topic := topicName(i)
+		router.AddNoPublisherHandler(topic, topic, r.pubSub, r.handle)
+	}
+	return r, nil
+}
+
+// Run доставляет сообщения до отмены ctx.
+func (r *Relay) Run(ctx context.Context) error {
+	const op = "outbox.Relay.Run"
+	l := r.l.With(slog.String("op", op))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.router.Run(ctx)
+	}()
+
+	select {
+	case <-r.router.Running():
+	case err := <-errCh:
+		return err
+	}
+	l.InfoContext(ctx, "outbox relay started")
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		n, err := r.poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.ErrorContext(ctx, "failed to claim outbox messages", slog.String("error", err.Error()))
+		}
+		if n == claimBatch {
+			// В очереди, вероятно, остались готовые сообщения
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			err = <-errCh
+			_ = r.pubSub.Close()
+			l.InfoContext(ctx, "outbox relay stopped")
+			return err
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// Notify сообщает о появлении в очереди новых сообщений, чтобы не ждать следующего опроса.
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Relay) poll(ctx context.Context) (int, error) {
+	msgs, err := r.repo.ClaimOutboxMessages(ctx, claimBatch, claimLock)
+	if err != nil {
+		return 0, err
+	}
+	for _, msg := range msgs {
+		payload, err2 := json.Marshal(payloadFromMessage(msg))
+		if err2 != nil {
+			return 0, err2
+		}
+		wm := message.NewMessage(watermill.NewUUID(), payload)
+		if err2 = r.pubSub.Publish(topicName(r.shard(msg.ParticipantID.ChatID())), wm); err2 != nil {
+			return 0, err2
+		}
+	}
+	return len(msgs), nil
+}
+
+func (r *Relay) handle(wm *message.Message) error {
+	const op = "outbox.Relay.handle"
+	ctx := wm.Context()
+
+	var p payload
+	if err := json.Unmarshal(wm.Payload, &p); err != nil {
+		// Повторная обработка не исправит повреждённое сообщение; оно будет захвачено снова
+		// по истечении claimLock
+		r.l.ErrorContext(ctx, "failed to decode outbox message",
+			slog.String("op", op), slog.String("error", err.Error()))
+		return nil
+	}
+
+	msg, err := p.toMessage()
+	if err == nil {
+		err = r.handler.Deliver(ctx, msg)
+	}
+	if err != nil {
+		// Результат попытки уже сохранён в очереди, повторять здесь не нужно
+		r.l.WarnContext(ctx, "failed to deliver outbox message",
+			slog.String("op", op), slog.Int64("id", p.ID), slog.String("error", err.Error()))
+	}
+
+	// Следующее сообщение чата стало доступно для захвата
+	r.Notify()
+	return nil
+}
+
+func (r *Relay) shard(chatID bots.ChatID) int {
+	id := int64(chatID)
+	if id < 0 {
+		id = -id
+	}
+	return int(id % int64(r.shards))
+}
+
+func topicName(shard int) string {
+	return fmt.Sprintf("%s%d", topicPrefix, shard)
+}
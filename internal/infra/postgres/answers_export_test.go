@@ -0,0 +1,118 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/dto/request"
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/query"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
+)
+
+func TestPostgresParticipantRepository_StreamBotThreads(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	db := tests.ConnectPostgresDB()
+	t.Cleanup(func() { _ = db.Close() })
+	insertSyntheticThreads(ctx, t, db, botID, 5, 3)
+
+	states, err := r.AnsweredStates(ctx, botID)
+	require.NoError(t, err)
+	require.Equal(t, []bots.State{bots.MustNewState(1), bots.MustNewState(2), bots.MustNewState(3)}, states)
+
+	var batches []int
+	var ids []bots.ThreadID
+	err = r.StreamBotThreads(ctx, botID, 2, func(threads []bots.BotThread) error {
+		batches = append(batches, len(threads))
+		for _, thread := range threads {
+			require.Len(t, thread.Thread().Answers(), 3)
+			ids = append(ids, thread.Thread().ID())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 2, 1}, batches)
+	// Треды идут от поздних к ранним
+	require.Equal(t, bots.ThreadID(string(botID)+"-5"), ids[0])
+
+	profiles, err := r.ParticipantProfilesByUserIDs(ctx, botID, []bots.UserID{1, 2})
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	require.Equal(t, bots.Username("user2"), profiles[2].Username())
+}
+
+// BenchmarkStreamThreads измеряет выгрузку таблицы ответов бота с 20000 тредов по 10 ответов от чтения
+// курсором до записи CSV.
+func BenchmarkStreamThreads(b *testing.B) {
+	r, closeFn := setupRepository()
+	b.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, b, r)
+	db := tests.ConnectPostgresDB()
+	b.Cleanup(func() { _ = db.Close() })
+	insertSyntheticThreads(ctx, b, db, botID, 20000, 10)
+
+	handler := query.NewStreamThreadsHandler(r, r, r, r, logs.DefaultLogger(), metrics.NoOp{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := csv.NewWriter(io.Discard)
+		res, err := handler.Handle(ctx, request.StreamThreadsQuery{
+			AccountID: "author",
+			BotID:     string(botID),
+			Header: func(columns []dto.AnswerColumn) error {
+				return w.Write(make([]string, len(columns)+3))
+			},
+			Row: func(thread dto.Thread) error {
+				row := make([]string, 0, len(thread.Answers)+3)
+				row = append(row, thread.ID, thread.StartedAt.String(), thread.Username)
+				for _, msg := range thread.Answers {
+					row = append(row, msg.Text)
+				}
+				return w.Write(row)
+			},
+		})
+		require.NoError(b, err)
+		require.Equal(b, 20000, res.Threads)
+		w.Flush()
+	}
+}
+
+// insertSyntheticThreads создаёт одним запросом на таблицу участников 1..n с профилями и по одному треду
+// <botID>-i с answers ответами у каждого. Тред участника i начат на i секунд позже эпохи.
+func insertSyntheticThreads(
+	ctx context.Context, tb testing.TB, db *sqlx.DB, botID bots.BotID, n int, answers int,
+) {
+	tb.Helper()
+	queries := []string{
+		`INSERT INTO participants (bot_id, chat_id, user_id)
+		SELECT $1::VARCHAR, g, g FROM generate_series(1, $2::INTEGER) g`,
+		`INSERT INTO participant_profiles (bot_id, chat_id, user_id, username)
+		SELECT $1::VARCHAR, g, g, 'user' || g FROM generate_series(1, $2::INTEGER) g`,
+		`INSERT INTO threads (id, bot_id, chat_id, user_id, key, state, started_at)
+		SELECT $1::VARCHAR || '-' || g, $1, g, g, 'start', 1, to_timestamp(g) FROM generate_series(1, $2::INTEGER) g`,
+	}
+	for _, q := range queries {
+		_, err := db.ExecContext(ctx, q, string(botID), n)
+		require.NoError(tb, err)
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO answers (thread_id, state, text)
+		SELECT $1::VARCHAR || '-' || g, s, 'answer ' || s FROM generate_series(1, $2::INTEGER) g, generate_series(1, $3::INTEGER) s`,
+		string(botID), n, answers,
+	)
+	require.NoError(tb, err)
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func (r *Repository) CreateAPIKey(ctx context.Context, k *bots.APIKey) error {
+	return r.insertAPIKeyRow(ctx, r.db, apiKeyToRow(k))
+}
+
+func (r *Repository) APIKey(ctx context.Context, id bots.APIKeyID) (*bots.APIKey, error) {
+	row, err := r.getAPIKeyRow(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", port.ErrAPIKeyNotFound, string(id))
+	} else if err != nil {
+		return nil, err
+	}
+	return apiKeyFromRow(row)
+}
+
+func (r *Repository) AccountAPIKeys(ctx context.Context, account bots.AccountID) ([]*bots.APIKey, error) {
+	rows, err := r.selectAccountAPIKeyRows(ctx, r.db, string(account))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*bots.APIKey, len(rows))
+	for i, row := range rows {
+		res[i], err = apiKeyFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *Repository) TouchAPIKey(ctx context.Context, id bots.APIKeyID, usedAt time.Time) error {
+	err := r.touchAPIKeyRow(ctx, r.db, string(id), usedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrAPIKeyNotFound, string(id))
+	}
+	return err
+}
+
+func (r *Repository) DeleteAPIKey(ctx context.Context, id bots.APIKeyID) error {
+	err := r.deleteAPIKeyRow(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrAPIKeyNotFound, string(id))
+	}
+	return err
+}
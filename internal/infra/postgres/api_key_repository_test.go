@@ -0,0 +1,46 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestPostgresAPIKeyRepository_CRUD(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	account := bots.AccountID(gofakeit.UUID())
+	scope := bots.MustNewAPIKeyScope([]bots.BotID{"bot"}, []bots.Permission{bots.PermView, bots.PermEdit})
+	k := bots.MustNewAPIKey(
+		bots.APIKeyID(gofakeit.UUID()), account, "ci", gofakeit.LetterN(32), scope,
+		time.Now().Add(time.Hour).Truncate(time.Second),
+	)
+	require.NoError(t, r.CreateAPIKey(ctx, k))
+
+	got, err := r.APIKey(ctx, k.ID())
+	require.NoError(t, err)
+	require.Equal(t, k.Hash(), got.Hash())
+	require.Equal(t, k.Scope(), got.Scope())
+	require.True(t, got.LastUsedAt().IsZero())
+
+	usedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, r.TouchAPIKey(ctx, k.ID(), usedAt))
+
+	ks, err := r.AccountAPIKeys(ctx, account)
+	require.NoError(t, err)
+	require.Len(t, ks, 1)
+	require.True(t, usedAt.Equal(ks[0].LastUsedAt()))
+
+	require.NoError(t, r.DeleteAPIKey(ctx, k.ID()))
+	_, err = r.APIKey(ctx, k.ID())
+	require.ErrorIs(t, err, port.ErrAPIKeyNotFound)
+	require.ErrorIs(t, r.DeleteAPIKey(ctx, k.ID()), port.ErrAPIKeyNotFound)
+}
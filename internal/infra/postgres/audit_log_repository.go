@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+func (r *Repository) RecordAudit(ctx context.Context, entry decorator.AuditEntry) error {
+	return r.insertAuditRow(ctx, r.db, auditEntryToRow(entry))
+}
+
+func (r *Repository) BotAuditLog(
+	ctx context.Context, botID bots.BotID, filter port.AuditLogFilter,
+) ([]port.AuditRecord, error) {
+	rows, err := r.selectBotAuditRows(ctx, r.db, string(botID), filter.Action, filter.Before, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]port.AuditRecord, len(rows))
+	for i, row := range rows {
+		res[i] = auditRecordFromRow(row)
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+func TestPostgresAuditLogRepository_BotAuditLog(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := gofakeit.UUID()
+	actor := gofakeit.UUID()
+	for _, action := range []string{"CreateBot", "UpdateBot", "DeleteBot"} {
+		require.NoError(t, r.RecordAudit(ctx, decorator.AuditEntry{
+			Actor:   actor,
+			Action:  action,
+			BotID:   botID,
+			Payload: []byte(`{"bot_id": "` + botID + `"}`),
+			At:      time.Now(),
+		}))
+	}
+	require.NoError(t, r.RecordAudit(ctx, decorator.AuditEntry{
+		Actor:   actor,
+		Action:  "UpdateBot",
+		BotID:   botID,
+		Payload: []byte(`{}`),
+		Error:   "access denied",
+		At:      time.Now(),
+	}))
+
+	rs, err := r.BotAuditLog(ctx, bots.BotID(botID), port.AuditLogFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, rs, 4)
+	require.Equal(t, "access denied", rs[0].Error)
+	require.Equal(t, "DeleteBot", rs[1].Action)
+	require.Equal(t, bots.AccountID(actor), rs[1].Actor)
+	require.JSONEq(t, `{"bot_id": "`+botID+`"}`, string(rs[1].Payload))
+
+	rs, err = r.BotAuditLog(ctx, bots.BotID(botID), port.AuditLogFilter{Action: "UpdateBot", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	page, err := r.BotAuditLog(ctx, bots.BotID(botID), port.AuditLogFilter{Before: rs[0].ID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "DeleteBot", page[0].Action)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -13,6 +14,7 @@ import (
 	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/diffcalc"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
 )
 
 func (r *Repository) Bot(ctx context.Context, id bots.BotID) (*bots.Bot, error) {
@@ -25,11 +27,11 @@ func (r *Repository) Bot(ctx context.Context, id bots.BotID) (*bots.Bot, error)
 	return bot, err
 }
 
-func (r *Repository) UserBots(ctx context.Context, author bots.UserID) ([]*bots.Bot, error) {
+func (r *Repository) UserBots(ctx context.Context, account bots.AccountID) ([]*bots.Bot, error) {
 	var _bots []*bots.Bot
 	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
 		var err error
-		_bots, err = r.selectBotsByAuthor(ctx, tx, author)
+		_bots, err = r.selectBotsByAccount(ctx, tx, account)
 		return err
 	})
 	return _bots, err
@@ -45,14 +47,30 @@ func (r *Repository) EnabledBots(ctx context.Context) ([]*bots.Bot, error) {
 	return _bots, err
 }
 
+func (r *Repository) BotByTelegramID(ctx context.Context, telegramID int64) (*bots.Bot, error) {
+	var bot *bots.Bot
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		var err error
+		bot, err = r.getBotByTelegramID(ctx, tx, telegramID)
+		return err
+	})
+	return bot, err
+}
+
 func (r *Repository) UpsertBot(ctx context.Context, bot *bots.Bot) error {
-	_botRow := botToRow(bot)
+	_botRow, err := r.sealBotToken(botToRow(bot))
+	if err != nil {
+		return err
+	}
 	entryRows := entriesToRows(bot.ID(), bot.Script().Entries())
 	nodes := bot.Script().Nodes()
 	nodeRows := nodesToRows(bot.ID(), nodes)
 
 	return pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
-		if err := r.upsertBotRow(ctx, tx, _botRow); err != nil {
+		err := r.upsertBotRow(ctx, tx, _botRow)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", port.ErrBotIDTaken, bot.ID())
+		} else if err != nil {
 			return err
 		}
 		if err := r.syncNodeRows(ctx, tx, bot.ID(), nodeRows); err != nil {
@@ -74,6 +92,10 @@ func (r *Repository) UpsertBot(ctx context.Context, bot *bots.Bot) error {
 			if err := r.syncOptionRows(ctx, tx, bot.ID(), node.State(), optionRows); err != nil {
 				return err
 			}
+			requestRows := requestsToRows(bot.ID(), node)
+			if err := r.syncRequestRows(ctx, tx, bot.ID(), node.State(), requestRows); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -87,6 +109,107 @@ func (r *Repository) DeleteBot(ctx context.Context, id bots.BotID) error {
 	return err
 }
 
+// ReencryptBotTokens шифрует активным ключом токены всех ботов, в том числе удалённых, которые хранятся
+// открыто или зашифрованы другим ключом, и возвращает количество перешифрованных токенов. После
+// перешифрования прежние ключи можно убрать из связки.
+func (r *Repository) ReencryptBotTokens(ctx context.Context) (int, error) {
+	const op = "PostgresRepository.ReencryptBotTokens"
+	l := r.l.With(slog.String("op", op))
+
+	if r.keys == nil {
+		return 0, errors.New("token encryption keys are not configured")
+	}
+
+	rows, err := r.selectBotRowsToReencrypt(ctx, r.db, r.keys.ActiveKeyID())
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, row := range rows {
+		token, err2 := r.openBotToken(row)
+		if err2 != nil {
+			return n, err2
+		}
+		prevKeyID := row.TokenKeyID
+		row.Token = token
+		row, err2 = r.sealBotToken(row)
+		if err2 != nil {
+			return n, err2
+		}
+		err2 = r.updateBotTokenRow(ctx, r.db, row, prevKeyID)
+		if errors.Is(err2, sql.ErrNoRows) {
+			// Бот удалён или сохранён заново после выборки: его токен уже зашифрован активным ключом
+			l.InfoContext(ctx, "bot token changed concurrently, skipping", slog.String("bot_id", row.ID))
+			continue
+		} else if err2 != nil {
+			return n, err2
+		}
+		n++
+	}
+	return n, nil
+}
+
+// ReassignBotAuthors в одной транзакции заменяет прежние числовые ID авторов ботов, в том числе удалённых,
+// субъектами JWT по соответствию authors и возвращает количество переназначенных ботов.
+func (r *Repository) ReassignBotAuthors(ctx context.Context, authors map[string]bots.AccountID) (int, error) {
+	n := 0
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		for legacyID, account := range authors {
+			affected, err := r.updateLegacyBotAuthorRows(ctx, tx, legacyID, string(account))
+			if err != nil {
+				return err
+			}
+			n += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// LegacyBotAuthors возвращает прежние числовые ID авторов, которые ещё не переназначены.
+func (r *Repository) LegacyBotAuthors(ctx context.Context) ([]string, error) {
+	return r.selectLegacyBotAuthors(ctx, r.db)
+}
+
+// sealBotToken шифрует открытый токен строки, если настроены ключи шифрования.
+func (r *Repository) sealBotToken(row botRow) (botRow, error) {
+	if r.keys == nil {
+		return row, nil
+	}
+	sealed, err := r.keys.Seal([]byte(row.Token), []byte(row.ID))
+	if err != nil {
+		return row, fmt.Errorf("encrypting token of bot %s: %w", row.ID, err)
+	}
+	row.Token = ""
+	row.TokenKeyID = &sealed.KeyID
+	row.TokenDataKey = sealed.DataKey
+	row.TokenCiphertext = sealed.Ciphertext
+	return row, nil
+}
+
+// openBotToken возвращает токен бота, расшифровывая его, если он хранится зашифрованным.
+func (r *Repository) openBotToken(row botRow) (string, error) {
+	if row.TokenKeyID == nil {
+		return row.Token, nil
+	}
+	if r.keys == nil {
+		return "", fmt.Errorf("token of bot %s is encrypted, but encryption keys are not configured", row.ID)
+	}
+	token, err := r.keys.Open(envelope.Sealed{
+		KeyID:      *row.TokenKeyID,
+		DataKey:    row.TokenDataKey,
+		Ciphertext: row.TokenCiphertext,
+	}, []byte(row.ID))
+	if err != nil {
+		return "", fmt.Errorf("decrypting token of bot %s: %w", row.ID, err)
+	}
+	return string(token), nil
+}
+
 //
 //
 // ОПЕРАЦИИ НАД СУЩНОСТЯМИ ВНУТРИ АГГРЕГАТА
@@ -127,12 +250,12 @@ func (r *Repository) DeleteBot(ctx context.Context, id bots.BotID) error {
 
 // Функции типа sync выполняют необходимую синхронизацию строк в БД и желаемого состояния, передаваемого в аргументах.
 
-func (r *Repository) selectBotsByAuthor(
+func (r *Repository) selectBotsByAccount(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
-	author bots.UserID,
+	account bots.AccountID,
 ) ([]*bots.Bot, error) {
-	rows, err := r.selectBotRowsByAuthor(ctx, qc, int64(author))
+	rows, err := r.selectBotRowsByAccount(ctx, qc, string(account))
 	if err != nil {
 		return nil, err
 	}
@@ -150,9 +273,7 @@ func (r *Repository) selectBotsByAuthor(
 		if err2 != nil {
 			return nil, err2
 		}
-		bot, err2 := bots.UnmarshallBot(
-			row.ID, row.Token, row.Author, row.Enabled, script, row.CreatedAt.In(time.Local),
-		)
+		bot, err2 := r.botFromRow(row, script)
 		if err2 != nil {
 			return nil, err2
 		}
@@ -183,9 +304,7 @@ func (r *Repository) selectEnabledBots(
 		if err2 != nil {
 			return nil, err2
 		}
-		bot, err2 := bots.UnmarshallBot(
-			row.ID, row.Token, row.Author, row.Enabled, script, row.CreatedAt.In(time.Local),
-		)
+		bot, err2 := r.botFromRow(row, script)
 		if err2 != nil {
 			return nil, err2
 		}
@@ -205,6 +324,30 @@ func (r *Repository) getBot(
 	} else if err != nil {
 		return nil, err
 	}
+	return r.selectBotByRow(ctx, qc, row)
+}
+
+func (r *Repository) getBotByTelegramID(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	telegramID int64,
+) (*bots.Bot, error) {
+	row, err := r.getBotRowByTelegramID(ctx, qc, telegramID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: telegram id %d", port.ErrBotNotFound, telegramID)
+	} else if err != nil {
+		return nil, err
+	}
+	return r.selectBotByRow(ctx, qc, row)
+}
+
+// selectBotByRow дополняет строку bots сценарием бота.
+func (r *Repository) selectBotByRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	row botRow,
+) (*bots.Bot, error) {
+	id := bots.BotID(row.ID)
 	entries, err := r.selectEntries(ctx, qc, id)
 	if err != nil {
 		return nil, err
@@ -217,7 +360,29 @@ func (r *Repository) getBot(
 	if err != nil {
 		return nil, err
 	}
-	return bots.UnmarshallBot(row.ID, row.Token, row.Author, row.Enabled, script, row.CreatedAt.In(time.Local))
+	return r.botFromRow(row, script)
+}
+
+// botFromRow собирает бота из строки bots и сценария, расшифровывая токен.
+func (r *Repository) botFromRow(row botRow, script bots.Script) (*bots.Bot, error) {
+	token, err := r.openBotToken(row)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		telegramID       int64
+		telegramUsername string
+	)
+	if row.TelegramID != nil {
+		telegramID = *row.TelegramID
+	}
+	if row.TelegramUsername != nil {
+		telegramUsername = *row.TelegramUsername
+	}
+	return bots.UnmarshallBot(
+		row.ID, token, row.Author, row.Enabled, row.ChatPolicy, script,
+		telegramID, telegramUsername, row.CreatedAt.In(time.Local),
+	)
 }
 
 func (r *Repository) selectEntries(
@@ -271,7 +436,20 @@ func (r *Repository) selectNodes(
 		if err2 != nil {
 			return nil, err2
 		}
-		node, err2 := bots.NewNode(state, row.Title, edges, msgs, opts)
+		reqRows, err2 := r.selectRequestRows(ctx, qc, string(botID), state.Int())
+		if err2 != nil {
+			return nil, err2
+		}
+		var node bots.Node
+		if len(reqRows) > 0 {
+			req, err3 := requestFromRow(reqRows[0])
+			if err3 != nil {
+				return nil, err3
+			}
+			node, err2 = bots.NewRequestNode(state, row.Title, msgs, req)
+		} else {
+			node, err2 = bots.NewNode(state, row.Title, edges, msgs, opts)
+		}
 		if err2 != nil {
 			return nil, err2
 		}
@@ -529,3 +707,38 @@ func (r *Repository) syncOptionRows(
 
 	return nil
 }
+
+func (r *Repository) syncRequestRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID bots.BotID,
+	state bots.State,
+	rows []requestRow,
+) error {
+	dbRows, err := r.selectRequestRows(ctx, ec, string(botID), state.Int())
+	if err != nil {
+		return err
+	}
+
+	changes := diffcalc.Changes(dbRows, rows, requestEqual, requestEqual)
+
+	if changes.IsZero() {
+		return nil
+	}
+
+	if len(dbRows) > 0 {
+		err = r.deleteRequestRows(ctx, ec, string(botID), state.Int())
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(rows) > 0 {
+		err = r.insertRequestRows(ctx, ec, rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package postgres_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/brianvoe/gofakeit/v6"
@@ -18,7 +19,7 @@ func TestPostgresBotRepository_CreateNew(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -54,7 +55,7 @@ func TestPostgresBotRepository_AddNode(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -68,7 +69,7 @@ func TestPostgresBotRepository_AddNode(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(
 				bots.MustNewState(1),
@@ -104,7 +105,7 @@ func TestPostgresBotRepository_RemoveNode(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(
 				bots.MustNewState(1),
@@ -129,7 +130,7 @@ func TestPostgresBotRepository_RemoveNode(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -154,7 +155,7 @@ func TestPostgresBotRepository_AddEntry(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -168,7 +169,7 @@ func TestPostgresBotRepository_AddEntry(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -194,7 +195,7 @@ func TestPostgresBotRepository_RemoveEntry(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -209,7 +210,7 @@ func TestPostgresBotRepository_RemoveEntry(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -234,7 +235,7 @@ func TestPostgresBotRepository_AddEdge(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -248,7 +249,7 @@ func TestPostgresBotRepository_AddEdge(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting",
 				[]bots.Edge{
@@ -279,7 +280,7 @@ func TestPostgresBotRepository_RemoveEdge(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting",
 				[]bots.Edge{
@@ -296,7 +297,7 @@ func TestPostgresBotRepository_RemoveEdge(t *testing.T) {
 		},
 	))
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -324,7 +325,7 @@ func TestPostgresBotRepository_AddMessage(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -338,7 +339,7 @@ func TestPostgresBotRepository_AddMessage(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -364,7 +365,7 @@ func TestPostgresBotRepository_RemoveMessage(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -379,7 +380,7 @@ func TestPostgresBotRepository_RemoveMessage(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -404,7 +405,7 @@ func TestPostgresBotRepository_UpdateBotData(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -418,7 +419,7 @@ func TestPostgresBotRepository_UpdateBotData(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token2", bots.UserID(2), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token2", bots.AccountID("author2"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -443,7 +444,7 @@ func TestPostgresBotRepository_UpdateNodeAndEntry(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -457,7 +458,7 @@ func TestPostgresBotRepository_UpdateNodeAndEntry(t *testing.T) {
 	err := r.UpsertBot(ctx, bot)
 	require.NoError(t, err)
 
-	updatedBot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	updatedBot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(2), "Greeting 2", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -482,7 +483,7 @@ func TestPostgresBotRepository_DeleteBot(t *testing.T) {
 	ctx := context.Background()
 
 	id := bots.BotID(gofakeit.AppName())
-	bot := bots.MustNewBot(id, "token", bots.UserID(1), bots.MustNewScript(
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
 		[]bots.Node{
 			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
 				bots.MustNewMessage("Hello, world!"),
@@ -502,3 +503,124 @@ func TestPostgresBotRepository_DeleteBot(t *testing.T) {
 	_, err = r.Bot(ctx, id)
 	require.ErrorIs(t, err, port.ErrBotNotFound)
 }
+
+func TestPostgresBotRepository_ReencryptBotTokens(t *testing.T) {
+	plain, closePlain := setupRepositoryWithKeys(nil)
+	t.Cleanup(closePlain)
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+
+	id := bots.BotID(gofakeit.AppName())
+	bot := bots.MustNewBot(id, "123456:secret", bots.AccountID("author"), bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
+				bots.MustNewMessage("Hello, world!"),
+			}, nil),
+		},
+		[]bots.Entry{
+			bots.MustNewEntry("start", bots.MustNewState(1)),
+		},
+	))
+
+	// Открыто сохранённый токен читается и после настройки шифрования
+	err := plain.UpsertBot(ctx, bot)
+	require.NoError(t, err)
+	recv, err := r.Bot(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, bot.Token(), recv.Token())
+
+	n, err := r.ReencryptBotTokens(ctx)
+	require.NoError(t, err)
+	require.Positive(t, n)
+
+	recv, err = r.Bot(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, bot.Token(), recv.Token())
+
+	// Без ключей зашифрованный токен прочитать нельзя
+	_, err = plain.Bot(ctx, id)
+	require.Error(t, err)
+}
+
+func TestPostgresBotRepository_BotByTelegramID(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+
+	id := bots.BotID(gofakeit.AppName())
+	telegramID := gofakeit.Int64()&0xffffffff + 1
+	bot := bots.MustNewBot(id, "token", bots.AccountID("author"), bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
+				bots.MustNewMessage("Hello, world!"),
+			}, nil),
+		},
+		[]bots.Entry{
+			bots.MustNewEntry("start", bots.MustNewState(1)),
+		},
+	))
+	bot.SetTelegram(bots.MustNewTelegramAccount(telegramID, "test_bot"))
+
+	err := r.UpsertBot(ctx, bot)
+	require.NoError(t, err)
+
+	recv, err := r.BotByTelegramID(ctx, telegramID)
+	require.NoError(t, err)
+	require.Equal(t, bot, recv)
+
+	err = r.DeleteBot(ctx, id)
+	require.NoError(t, err)
+
+	_, err = r.BotByTelegramID(ctx, telegramID)
+	require.ErrorIs(t, err, port.ErrBotNotFound)
+}
+
+func TestPostgresBotRepository_ReassignBotAuthors(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+
+	legacyID := strconv.FormatInt(gofakeit.Int64()&0xffffffff+1, 10)
+	newBot := func(author string) bots.BotID {
+		id := bots.BotID(gofakeit.AppName())
+		bot := bots.MustNewBot(id, "123456:secret", bots.AccountID(author), bots.MustNewScript(
+			[]bots.Node{
+				bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
+					bots.MustNewMessage("Hello, world!"),
+				}, nil),
+			},
+			[]bots.Entry{
+				bots.MustNewEntry("start", bots.MustNewState(1)),
+			},
+		))
+		require.NoError(t, r.UpsertBot(ctx, bot))
+		return id
+	}
+	id := newBot("legacy:" + legacyID)
+	// Бот пользователя, числовой субъект JWT которого совпал с прежним ID, не переназначается
+	numeric := newBot(legacyID)
+
+	left, err := r.LegacyBotAuthors(ctx)
+	require.NoError(t, err)
+	require.Contains(t, left, legacyID)
+
+	account := bots.AccountID(gofakeit.UUID())
+	n, err := r.ReassignBotAuthors(ctx, map[string]bots.AccountID{legacyID: account})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	recv, err := r.Bot(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, account, recv.Author())
+	recv, err = r.Bot(ctx, numeric)
+	require.NoError(t, err)
+	require.Equal(t, bots.AccountID(legacyID), recv.Author())
+
+	left, err = r.LegacyBotAuthors(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, left, legacyID)
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func (r *Repository) UpsertCollaborator(ctx context.Context, c *bots.Collaborator) error {
+	return r.upsertCollaboratorRow(ctx, r.db, collaboratorToRow(c))
+}
+
+func (r *Repository) Collaborator(
+	ctx context.Context, botID bots.BotID, account bots.AccountID,
+) (*bots.Collaborator, error) {
+	row, err := r.getCollaboratorRow(ctx, r.db, string(botID), string(account))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", port.ErrCollaboratorNotFound, string(account))
+	} else if err != nil {
+		return nil, err
+	}
+	return collaboratorFromRow(row)
+}
+
+func (r *Repository) BotCollaborators(ctx context.Context, botID bots.BotID) ([]*bots.Collaborator, error) {
+	rows, err := r.selectBotCollaboratorRows(ctx, r.db, string(botID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*bots.Collaborator, len(rows))
+	for i, row := range rows {
+		res[i], err = collaboratorFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *Repository) DeleteCollaborator(ctx context.Context, botID bots.BotID, account bots.AccountID) error {
+	err := r.deleteCollaboratorRow(ctx, r.db, string(botID), string(account))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrCollaboratorNotFound, string(account))
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestPostgresCollaboratorRepository_CRUD(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	account := bots.AccountID(gofakeit.UUID())
+
+	c := bots.MustNewCollaborator(botID, account, bots.Viewer)
+	require.NoError(t, r.UpsertCollaborator(ctx, c))
+
+	got, err := r.Collaborator(ctx, botID, account)
+	require.NoError(t, err)
+	require.Equal(t, c, got)
+
+	require.NoError(t, c.SetRole(bots.Editor))
+	require.NoError(t, r.UpsertCollaborator(ctx, c))
+
+	cs, err := r.BotCollaborators(ctx, botID)
+	require.NoError(t, err)
+	require.Equal(t, []*bots.Collaborator{c}, cs)
+
+	require.NoError(t, r.DeleteCollaborator(ctx, botID, account))
+	_, err = r.Collaborator(ctx, botID, account)
+	require.True(t, errors.Is(err, port.ErrCollaboratorNotFound))
+	require.True(t, errors.Is(r.DeleteCollaborator(ctx, botID, account), port.ErrCollaboratorNotFound))
+}
+
+func TestPostgresCollaboratorRepository_UserBots(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	account := bots.AccountID(gofakeit.UUID())
+
+	bs, err := r.UserBots(ctx, account)
+	require.NoError(t, err)
+	require.Empty(t, bs)
+
+	require.NoError(t, r.UpsertCollaborator(ctx, bots.MustNewCollaborator(botID, account, bots.Viewer)))
+
+	bs, err = r.UserBots(ctx, account)
+	require.NoError(t, err)
+	require.Len(t, bs, 1)
+	require.Equal(t, botID, bs[0].ID())
+}
@@ -2,10 +2,15 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/zhikh23/pgutils"
 )
 
@@ -21,8 +26,14 @@ func (r *Repository) getBotRow(
 		SELECT
 			id,
 			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
 			author,
 			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
 			created_at
 		FROM bots
 		WHERE
@@ -37,28 +48,73 @@ func (r *Repository) getBotRow(
 	return row, nil
 }
 
-func (r *Repository) selectBotRowsByAuthor(
+func (r *Repository) getBotRowByTelegramID(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
-	author int64,
+	telegramID int64,
+) (botRow, error) {
+	var row botRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			id,
+			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
+			author,
+			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
+			created_at
+		FROM bots
+		WHERE
+			telegram_id = $1
+			AND deleted_at IS NULL
+		`,
+		telegramID,
+	)
+	if err != nil {
+		return row, fmt.Errorf("selecting bot row by telegram id: %w", err)
+	}
+	return row, nil
+}
+
+func (r *Repository) selectBotRowsByAccount(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	account string,
 ) ([]botRow, error) {
 	var rows []botRow
 	err := pgutils.Select(ctx, qc, &rows, `
 		SELECT
 			id,
 			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
 			author,
 			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
 			created_at
 		FROM bots
 		WHERE
-			author = $1
+			(
+				author = $1
+				OR id IN (
+					SELECT bot_id
+					FROM bot_collaborators
+					WHERE account_id = $1
+				)
+			)
 			AND deleted_at IS NULL
 		`,
-		author,
+		account,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("selecting bot rows by author: %w", err)
+		return nil, fmt.Errorf("selecting bot rows by account: %w", err)
 	}
 	return rows, nil
 }
@@ -72,8 +128,14 @@ func (r *Repository) selectEnabledBotRows(
 		SELECT
 			id,
 			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
 			author,
 			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
 			created_at
 		FROM bots
 		WHERE
@@ -97,25 +159,45 @@ func (r *Repository) upsertBotRow(
 			bots (
 				id, 
 				token, 
+				token_key_id,
+				token_data_key,
+				token_ciphertext,
 				author,
 				enabled,
+				chat_policy,
+				telegram_id,
+				telegram_username,
 				created_at
 			)
 		VALUES (
 		    :id,
 			:token,
+			:token_key_id,
+			:token_data_key,
+			:token_ciphertext,
 			:author,
 			:enabled,
+			:chat_policy,
+			:telegram_id,
+			:telegram_username,
 			:created_at
 		)
 		ON CONFLICT 
 			(id)
 		DO UPDATE 
 		SET
-			token      = :token,
-			author     = :author,
-			enabled    = :enabled,
-			created_at = :created_at
+			token             = :token,
+			token_key_id      = :token_key_id,
+			token_data_key    = :token_data_key,
+			token_ciphertext  = :token_ciphertext,
+			author            = :author,
+			enabled           = :enabled,
+			chat_policy       = :chat_policy,
+			telegram_id       = :telegram_id,
+			telegram_username = :telegram_username,
+			created_at        = :created_at
+		WHERE
+			bots.deleted_at IS NULL
 		`,
 		row,
 	))
@@ -462,6 +544,97 @@ func (r *Repository) deleteMessageRows(
 	return nil
 }
 
+func (r *Repository) selectRequestRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	state int,
+) ([]requestRow, error) {
+	var rows []requestRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			bot_id,
+			state,
+			method,
+			url,
+			headers,
+			body,
+			save,
+			on_success,
+			on_failure
+		FROM node_requests
+		WHERE
+		    bot_id = $1
+			AND state = $2
+		`,
+		botID,
+		state,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting request rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) insertRequestRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	rows []requestRow,
+) error {
+	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			node_requests (
+				bot_id,
+				state,
+				method,
+				url,
+				headers,
+				body,
+				save,
+				on_success,
+				on_failure
+			)
+		VALUES (
+			:bot_id,
+			:state,
+			:method,
+			:url,
+			:headers,
+			:body,
+			:save,
+			:on_success,
+			:on_failure
+		)
+		`,
+		rows,
+	))
+	if err != nil {
+		return fmt.Errorf("inserting request rows: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) deleteRequestRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	state int,
+) error {
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		DELETE FROM node_requests
+		WHERE
+		    bot_id = $1
+			AND state = $2
+		`,
+		botID,
+		state,
+	))
+	if err != nil {
+		return fmt.Errorf("deleting request rows: %w", err)
+	}
+	return nil
+}
+
 func (r *Repository) selectOptionRows(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
@@ -539,12 +712,14 @@ func (r *Repository) getParticipantRow(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
 	botID string,
+	chatID int64,
 	userID int64,
 ) (participantRow, error) {
 	const op = "PostgresRepository.getParticipantRow"
 	l := r.l.With(
 		slog.String("op", op),
 		slog.String("bot_id", botID),
+		slog.Int64("chat_id", chatID),
 		slog.Int64("user_id", userID),
 	)
 
@@ -553,14 +728,19 @@ func (r *Repository) getParticipantRow(
 	err := pgutils.Get(ctx, qc, &row, `
 		SELECT
 			bot_id,
+			chat_id,
 			user_id,
-			active_thread
+			active_thread,
+			blocked_at,
+			last_update_id
 		FROM participants
 		WHERE
 			bot_id = $1
-			AND user_id = $2
+			AND chat_id = $2
+			AND user_id = $3
 		`,
 		botID,
+		chatID,
 		userID,
 	)
 	if err != nil {
@@ -579,6 +759,7 @@ func (r *Repository) upsertParticipantRow(
 	l := r.l.With(
 		slog.String("op", op),
 		slog.String("bot_id", row.BotID),
+		slog.Int64("chat_id", row.ChatID),
 		slog.Int64("user_id", row.UserID),
 	)
 
@@ -586,20 +767,28 @@ func (r *Repository) upsertParticipantRow(
 	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
 		INSERT INTO
 			participants (
-				bot_id, 
+				bot_id,
+				chat_id,
 				user_id,
-				active_thread
+				active_thread,
+				blocked_at,
+				last_update_id
 			)
 		VALUES (
 		    :bot_id,
+			:chat_id,
 			:user_id,
-			:active_thread
+			:active_thread,
+			:blocked_at,
+			:last_update_id
 		)
 		ON CONFLICT 
-			(bot_id, user_id)
+			(bot_id, chat_id, user_id)
 		DO UPDATE 
 		SET
-			active_thread = :active_thread
+			active_thread = :active_thread,
+			blocked_at = :blocked_at,
+			last_update_id = :last_update_id
 		`,
 		row,
 	))
@@ -610,264 +799,2684 @@ func (r *Repository) upsertParticipantRow(
 	return nil
 }
 
-func (r *Repository) getThreadRow(
+func (r *Repository) getProfileRow(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
-	threadID string,
-) (threadRow, error) {
-	const op = "PostgresRepository.getThreadRow"
+	botID string,
+	chatID int64,
+	userID int64,
+) (profileRow, error) {
+	const op = "PostgresRepository.getProfileRow"
 	l := r.l.With(
 		slog.String("op", op),
-		slog.String("thread_id", threadID),
+		slog.String("bot_id", botID),
+		slog.Int64("chat_id", chatID),
+		slog.Int64("user_id", userID),
 	)
 
-	l.DebugContext(ctx, "querying thread row")
-	var row threadRow
+	l.DebugContext(ctx, "querying profile row")
+	var row profileRow
 	err := pgutils.Get(ctx, qc, &row, `
 		SELECT
-			id,
 			bot_id,
+			chat_id,
 			user_id,
-			key,
-			state,
-			started_at
-		FROM threads
+			username,
+			first_name,
+			last_name,
+			language_code
+		FROM participant_profiles
 		WHERE
-		    id = $1
+			bot_id = $1
+			AND chat_id = $2
+			AND user_id = $3
 		`,
-		threadID,
+		botID,
+		chatID,
+		userID,
 	)
 	if err != nil {
-		l.ErrorContext(ctx, "failed to query thread row", slog.String("error", err.Error()))
-		return threadRow{}, fmt.Errorf("querying thread row: %w", err)
+		l.ErrorContext(ctx, "failed to get profile row", slog.String("error", err.Error()))
+		return profileRow{}, fmt.Errorf("getting profile row: %w", err)
 	}
 	return row, nil
 }
 
-func (r *Repository) selectBotThreadsRows(
+func (r *Repository) selectUsersProfileRows(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
 	botID string,
-) ([]threadRow, error) {
-	const op = "PostgresRepository.selectBotThreadsRows"
+	userIDs []int64,
+) ([]profileRow, error) {
+	var rows []profileRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			bot_id,
+			chat_id,
+			user_id,
+			username,
+			first_name,
+			last_name,
+			language_code
+		FROM participant_profiles
+		WHERE
+			bot_id = $1
+			AND user_id = ANY($2)
+		-- Профиль из личного чата точнее групповых и должен обрабатываться последним
+		ORDER BY (chat_id = user_id)
+		`,
+		botID, pq.Array(userIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting users profile rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) selectBotProfileRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) ([]profileRow, error) {
+	const op = "PostgresRepository.selectBotProfileRows"
 	l := r.l.With(
 		slog.String("op", op),
 		slog.String("bot_id", botID),
 	)
 
-	l.DebugContext(ctx, "querying bot thread rows")
-	var rows []threadRow
+	l.DebugContext(ctx, "querying bot profile rows")
+	var rows []profileRow
 	err := pgutils.Select(ctx, qc, &rows, `
 		SELECT
-			id,
 			bot_id,
+			chat_id,
 			user_id,
-			key,
-			state,
-			started_at
-		FROM threads
+			username,
+			first_name,
+			last_name,
+			language_code
+		FROM participant_profiles
 		WHERE
 			bot_id = $1
-		ORDER BY started_at DESC
+		-- Профиль из личного чата точнее групповых и должен обрабатываться последним
+		ORDER BY (chat_id = user_id)
 		`,
 		botID,
 	)
 	if err != nil {
-		l.ErrorContext(ctx, "failed to query bot thread rows", slog.String("error", err.Error()))
-		return nil, fmt.Errorf("selecting bot thread rows: %w", err)
+		l.ErrorContext(ctx, "failed to query bot profile rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("selecting bot profile rows: %w", err)
 	}
 	return rows, nil
 }
 
-func (r *Repository) upsertThreadRow(
+// upsertProfileRow не изменяет строку, если данные профиля не изменились.
+func (r *Repository) upsertProfileRow(
 	ctx context.Context,
 	ec sqlx.ExtContext,
-	row threadRow,
+	row profileRow,
 ) error {
-	const op = "PostgresRepository.upsertThreadRow"
+	const op = "PostgresRepository.upsertProfileRow"
 	l := r.l.With(
 		slog.String("op", op),
-		slog.String("id", row.ID),
+		slog.String("bot_id", row.BotID),
+		slog.Int64("chat_id", row.ChatID),
+		slog.Int64("user_id", row.UserID),
 	)
 
-	l.DebugContext(ctx, "upserting thread row")
-	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
-		INSERT INTO 
-			threads (
-				id, 
-				bot_id, 
-				user_id, 
-				key, 
-				state, 
-				started_at
-			)	 
+	l.DebugContext(ctx, "upserting profile row")
+	_, err := pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			participant_profiles (
+				bot_id,
+				chat_id,
+				user_id,
+				username,
+				first_name,
+				last_name,
+				language_code
+			)
 		VALUES (
-			:id,
 			:bot_id,
+			:chat_id,
 			:user_id,
-			:key,
-			:state,
-			:started_at
+			:username,
+			:first_name,
+			:last_name,
+			:language_code
 		)
-		ON CONFLICT (id)
-		DO UPDATE SET
-			state = :state
+		ON CONFLICT
+			(bot_id, chat_id, user_id)
+		DO UPDATE
+		SET
+			username = :username,
+			first_name = :first_name,
+			last_name = :last_name,
+			language_code = :language_code,
+			updated_at = now()
+		WHERE
+			(
+				participant_profiles.username,
+				participant_profiles.first_name,
+				participant_profiles.last_name,
+				participant_profiles.language_code
+			) IS DISTINCT FROM (
+				EXCLUDED.username,
+				EXCLUDED.first_name,
+				EXCLUDED.last_name,
+				EXCLUDED.language_code
+			)
 		`,
 		row,
-	))
+	)
 	if err != nil {
-		l.ErrorContext(ctx, "failed to upsert thread row", slog.String("error", err.Error()))
-		return fmt.Errorf("upserting thread row: %w", err)
+		l.ErrorContext(ctx, "failed to upsert profile row", slog.String("error", err.Error()))
+		return fmt.Errorf("upserting profile row: %w", err)
 	}
 	return nil
 }
 
-func (r *Repository) selectAnswerRows(
+func (r *Repository) getParticipantStatsRow(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
-	threadID string,
-) ([]answerRow, error) {
-	const op = "PostgresRepository.selectAnswerRows"
+	botID string,
+) (participantStatsRow, error) {
+	const op = "PostgresRepository.getParticipantStatsRow"
 	l := r.l.With(
 		slog.String("op", op),
-		slog.String("thread_id", threadID),
+		slog.String("bot_id", botID),
 	)
 
-	l.DebugContext(ctx, "querying answer rows")
-	var rows []answerRow
-	err := pgutils.Select(ctx, qc, &rows, `
+	l.DebugContext(ctx, "querying participant stats row")
+	var row participantStatsRow
+	err := pgutils.Get(ctx, qc, &row, `
 		SELECT
-			thread_id,
-			state,
-			text
-		FROM answers
+			COUNT(*) AS total,
+			COUNT(blocked_at) AS blocked
+		FROM participants
 		WHERE
-			thread_id = $1
+			bot_id = $1
 		`,
-		threadID,
+		botID,
 	)
 	if err != nil {
-		l.ErrorContext(ctx, "failed to query answer rows", slog.String("error", err.Error()))
-		return nil, fmt.Errorf("selecting answer rows: %w", err)
+		l.ErrorContext(ctx, "failed to get participant stats row", slog.String("error", err.Error()))
+		return participantStatsRow{}, fmt.Errorf("getting participant stats row: %w", err)
 	}
-	return rows, nil
+	return row, nil
 }
 
-func (r *Repository) insertAnswerRows(
+func (r *Repository) getThreadRow(
 	ctx context.Context,
-	ec sqlx.ExtContext,
-	rows []answerRow,
-) error {
-	const op = "PostgresRepository.insertAnswerRows"
+	qc sqlx.QueryerContext,
+	threadID string,
+) (threadRow, error) {
+	const op = "PostgresRepository.getThreadRow"
 	l := r.l.With(
 		slog.String("op", op),
-		slog.Int("rows", len(rows)),
+		slog.String("thread_id", threadID),
 	)
 
-	l.DebugContext(ctx, "inserting answer rows")
-	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
-		INSERT INTO
-			answers (
-				thread_id, 
-			    state,
-				text
-			)
-		VALUES (
-			:thread_id,
-			:state,
-			:text
-		)
+	l.DebugContext(ctx, "querying thread row")
+	var row threadRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			id,
+			bot_id,
+			chat_id,
+			user_id,
+			key,
+			state,
+			vars,
+			started_at
+		FROM threads
+		WHERE
+		    id = $1
 		`,
-		rows,
-	))
+		threadID,
+	)
 	if err != nil {
-		l.ErrorContext(ctx, "failed to insert answer rows", slog.String("error", err.Error()))
-		return fmt.Errorf("inserting answer rows: %w", err)
+		l.ErrorContext(ctx, "failed to query thread row", slog.String("error", err.Error()))
+		return threadRow{}, fmt.Errorf("querying thread row: %w", err)
 	}
-	return nil
+	return row, nil
 }
 
-func (r *Repository) updateAnswerRow(
+// declareBotThreadsCursor открывает в транзакции курсор по тредам бота от поздних к ранним.
+func (r *Repository) declareBotThreadsCursor(
 	ctx context.Context,
-	ec sqlx.ExtContext,
-	row answerRow,
+	ec sqlx.ExecerContext,
+	botID string,
 ) error {
-	const op = "PostgresRepository.updateAnswerRow"
-	l := r.l.With(
-		slog.String("op", op),
-		slog.String("thread_id", row.ThreadID),
-		slog.Int("state", row.State),
+	_, err := pgutils.Exec(ctx, ec, `
+		DECLARE bot_threads_cursor NO SCROLL CURSOR FOR
+		SELECT
+			id,
+			bot_id,
+			chat_id,
+			user_id,
+			key,
+			state,
+			vars,
+			started_at
+		FROM threads
+		WHERE
+			bot_id = $1
+		ORDER BY
+			started_at DESC,
+			id DESC
+		`,
+		botID,
 	)
+	if err != nil {
+		return fmt.Errorf("declaring bot threads cursor: %w", err)
+	}
+	return nil
+}
+
+// fetchBotThreadsCursor читает из курсора, открытого declareBotThreadsCursor, не более n тредов.
+func (r *Repository) fetchBotThreadsCursor(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	n int,
+) ([]threadRow, error) {
+	var rows []threadRow
+	// FETCH не принимает параметров; n есть число, а не пользовательский ввод
+	err := pgutils.Select(ctx, qc, &rows, fmt.Sprintf("FETCH FORWARD %d FROM bot_threads_cursor", n))
+	if err != nil {
+		return nil, fmt.Errorf("fetching bot threads cursor: %w", err)
+	}
+	return rows, nil
+}
+
+// selectBotAnsweredStates выбирает по возрастанию состояния узлов, на которые в тредах бота есть ответы.
+func (r *Repository) selectBotAnsweredStates(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) ([]int, error) {
+	var states []int
+	err := pgutils.Select(ctx, qc, &states, `
+		SELECT DISTINCT
+			a.state
+		FROM answers a
+		JOIN threads t ON t.id = a.thread_id
+		WHERE
+			t.bot_id = $1
+		ORDER BY a.state
+		`,
+		botID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting bot answered states: %w", err)
+	}
+	return states, nil
+}
+
+// selectFilteredBotThreadRows выбирает страницу тредов бота. Нулевые значения фильтров не ограничивают
+// выборку; completed учитывается, только если не nil.
+func (r *Repository) selectFilteredBotThreadRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	filter threadFilterParams,
+) ([]threadRow, error) {
+	const op = "PostgresRepository.selectFilteredBotThreadRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+	)
+
+	// Направление сортировки не зависит от пользовательского ввода и подставляется в запрос напрямую
+	order, cmp := "DESC", "<"
+	if filter.Ascending {
+		order, cmp = "ASC", ">"
+	}
+
+	l.DebugContext(ctx, "querying filtered bot thread rows")
+	var rows []threadRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			t.id,
+			t.bot_id,
+			t.chat_id,
+			t.user_id,
+			t.key,
+			t.state,
+			t.vars,
+			t.started_at
+		FROM threads t
+		WHERE
+			t.bot_id = $1
+			AND ($2 = '' OR t.key = $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR t.started_at >= $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR t.started_at < $4)
+			AND ($5::BOOLEAN IS NULL OR (t.state = ANY($6::INTEGER[])) = $5)
+			AND ($7 = '' OR EXISTS (
+				SELECT 1
+				FROM answers a
+				WHERE
+					a.thread_id = t.id
+					AND a.text ILIKE '%' || $7 || '%'
+			))
+			AND ($8::TIMESTAMPTZ IS NULL OR (t.started_at, t.id) `+cmp+` ($8, $9))
+		ORDER BY
+			t.started_at `+order+`,
+			t.id `+order+`
+		LIMIT $10
+		`,
+		botID,
+		filter.EntryKey,
+		filter.StartedAfter,
+		filter.StartedBefore,
+		filter.Completed,
+		pq.Array(filter.FinalStates),
+		escapeLike(filter.Contains),
+		filter.AfterStartedAt,
+		filter.AfterID,
+		filter.Limit,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to query filtered bot thread rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("selecting filtered bot thread rows: %w", err)
+	}
+	return rows, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE, чтобы строка искалась буквально.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// selectUserParticipantRows выбирает участников - пользователя userID во всех чатах бота botID или, если
+// botID пуст, всех ботов, в том числе удалённых.
+func (r *Repository) selectUserParticipantRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	userID int64,
+	botID string,
+) ([]participantRow, error) {
+	var rows []participantRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			bot_id,
+			chat_id,
+			user_id,
+			active_thread,
+			blocked_at,
+			last_update_id
+		FROM participants
+		WHERE
+			user_id = $1
+			AND ($2 = '' OR bot_id = $2)
+		ORDER BY bot_id, chat_id
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting user participant rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) selectParticipantThreadRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	chatID int64,
+	userID int64,
+) ([]threadRow, error) {
+	var rows []threadRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			bot_id,
+			chat_id,
+			user_id,
+			key,
+			state,
+			vars,
+			started_at
+		FROM threads
+		WHERE
+			bot_id = $1
+			AND chat_id = $2
+			AND user_id = $3
+		ORDER BY started_at
+		`,
+		botID,
+		chatID,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting participant thread rows: %w", err)
+	}
+	return rows, nil
+}
+
+// reassignParticipantThreadRows передаёт треды участника from участнику (chatID, userID) того же бота и
+// очищает их переменные.
+func (r *Repository) reassignParticipantThreadRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	from participantRow,
+	chatID int64,
+	userID int64,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE threads
+		SET
+			chat_id = $4,
+			user_id = $5,
+			vars    = '{}'
+		WHERE
+			bot_id = $1
+			AND chat_id = $2
+			AND user_id = $3
+		`,
+		from.BotID,
+		from.ChatID,
+		from.UserID,
+		chatID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("reassigning participant thread rows: %w", err)
+	}
+	return nil
+}
+
+// deleteUserThreadRows удаляет треды пользователя userID в боте botID или, если botID пуст, во всех ботах;
+// ответы удаляются каскадно.
+func (r *Repository) deleteUserThreadRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	userID int64,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM threads
+		WHERE
+			user_id = $1
+			AND ($2 = '' OR bot_id = $2)
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting user thread rows: %w", err)
+	}
+	return nil
+}
+
+// deleteUserParticipantRows удаляет участников - пользователя userID в боте botID или, если botID пуст, во
+// всех ботах; профили удаляются каскадно.
+func (r *Repository) deleteUserParticipantRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	userID int64,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM participants
+		WHERE
+			user_id = $1
+			AND ($2 = '' OR bot_id = $2)
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting user participant rows: %w", err)
+	}
+	return nil
+}
+
+// deleteUserOutboxRows удаляет неотправленные сообщения пользователю userID от бота botID или, если botID
+// пуст, от всех ботов.
+func (r *Repository) deleteUserOutboxRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	userID int64,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM outbox_messages
+		WHERE
+			user_id = $1
+			AND ($2 = '' OR bot_id = $2)
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting user outbox rows: %w", err)
+	}
+	return nil
+}
+
+// replaceParticipantAnswerTexts заменяет на text тексты всех ответов в тредах участника prt.
+func (r *Repository) replaceParticipantAnswerTexts(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	prt participantRow,
+	text string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE answers
+		SET
+			text = $4
+		WHERE
+			thread_id IN (
+				SELECT id
+				FROM threads
+				WHERE
+					bot_id = $1
+					AND chat_id = $2
+					AND user_id = $3
+			)
+		`,
+		prt.BotID,
+		prt.ChatID,
+		prt.UserID,
+		text,
+	)
+	if err != nil {
+		return fmt.Errorf("replacing participant answer texts: %w", err)
+	}
+	return nil
+}
+
+// tableExists сообщает, существует ли таблица table; имя передаётся так же, как в запросах, в том числе
+// в кавычках.
+func (r *Repository) tableExists(ctx context.Context, qc sqlx.QueryerContext, table string) (bool, error) {
+	var exists bool
+	err := pgutils.Get(ctx, qc, &exists, `SELECT to_regclass($1) IS NOT NULL`, table)
+	if err != nil {
+		return false, fmt.Errorf("checking table %s: %w", table, err)
+	}
+	return exists, nil
+}
+
+// selectParticipantRecordRows выбирает записи источника src, относящиеся к участнику prt.
+func (r *Repository) selectParticipantRecordRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	src recordSource,
+	prt participantRow,
+) ([]participantRecordRow, error) {
+	var rows []participantRecordRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			$4 AS source,
+			`+src.doc+` AS payload,
+			created_at
+		FROM `+src.table+`
+		WHERE
+			`+src.botID+` = $1
+			AND `+src.doc+`->>'chatId' = $2::text
+			AND `+src.doc+`->>'userId' = $3::text
+		ORDER BY created_at
+		`,
+		prt.BotID,
+		prt.ChatID,
+		prt.UserID,
+		src.name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting participant record rows from %s: %w", src.name, err)
+	}
+	return rows, nil
+}
+
+// deleteUserRecordRows удаляет записи источника src о пользователе userID в боте botID или, если botID
+// пуст, во всех ботах.
+func (r *Repository) deleteUserRecordRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	src recordSource,
+	userID int64,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM `+src.table+`
+		WHERE
+			`+src.doc+`->>'userId' = $1::text
+			AND ($2 = '' OR `+src.botID+` = $2)
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting user record rows from %s: %w", src.name, err)
+	}
+	return nil
+}
+
+// selectUserAuditRecordRows выбирает записи журнала аудита бота botID, в которых упоминается пользователь
+// userID.
+func (r *Repository) selectUserAuditRecordRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	userID int64,
+	botID string,
+) ([]participantRecordRow, error) {
+	var rows []participantRecordRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			'audit_log' AS source,
+			payload,
+			created_at
+		FROM audit_log
+		WHERE
+			bot_id = $2
+			AND payload->>'UserID' = $1::text
+		ORDER BY id
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting user audit record rows: %w", err)
+	}
+	return rows, nil
+}
+
+// scrubUserAuditRows заменяет нулём ID пользователя userID в записях журнала аудита бота botID или, если
+// botID пуст, всех ботов. Сами записи сохраняются: журнал фиксирует действия владельцев ботов.
+func (r *Repository) scrubUserAuditRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	userID int64,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE audit_log
+		SET
+			payload = jsonb_set(payload, '{UserID}', '0')
+		WHERE
+			payload->>'UserID' = $1::text
+			AND ($2 = '' OR bot_id = $2)
+		`,
+		userID,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("scrubbing user audit rows: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) upsertThreadRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row threadRow,
+) error {
+	const op = "PostgresRepository.upsertThreadRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("id", row.ID),
+	)
+
+	l.DebugContext(ctx, "upserting thread row")
+	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
+		INSERT INTO 
+			threads (
+				id, 
+				bot_id,
+				chat_id,
+				user_id, 
+				key, 
+				state, 
+				vars,
+				started_at
+			)	 
+		VALUES (
+			:id,
+			:bot_id,
+			:chat_id,
+			:user_id,
+			:key,
+			:state,
+			:vars,
+			:started_at
+		)
+		ON CONFLICT (id)
+		DO UPDATE SET
+			state = :state,
+			vars  = :vars
+		`,
+		row,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to upsert thread row", slog.String("error", err.Error()))
+		return fmt.Errorf("upserting thread row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) selectAnswerRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	threadID string,
+) ([]answerRow, error) {
+	const op = "PostgresRepository.selectAnswerRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("thread_id", threadID),
+	)
+
+	l.DebugContext(ctx, "querying answer rows")
+	var rows []answerRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			thread_id,
+			state,
+			text
+		FROM answers
+		WHERE
+			thread_id = $1
+		`,
+		threadID,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to query answer rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("selecting answer rows: %w", err)
+	}
+	return rows, nil
+}
+
+// selectThreadsAnswerRows выбирает ответы сразу нескольких тредов.
+func (r *Repository) selectThreadsAnswerRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	threadIDs []string,
+) ([]answerRow, error) {
+	var rows []answerRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			thread_id,
+			state,
+			text
+		FROM answers
+		WHERE
+			thread_id = ANY($1)
+		`,
+		pq.Array(threadIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting threads answer rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) insertAnswerRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	rows []answerRow,
+) error {
+	const op = "PostgresRepository.insertAnswerRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int("rows", len(rows)),
+	)
+
+	l.DebugContext(ctx, "inserting answer rows")
+	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			answers (
+				thread_id, 
+			    state,
+				text
+			)
+		VALUES (
+			:thread_id,
+			:state,
+			:text
+		)
+		`,
+		rows,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to insert answer rows", slog.String("error", err.Error()))
+		return fmt.Errorf("inserting answer rows: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) updateAnswerRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row answerRow,
+) error {
+	const op = "PostgresRepository.updateAnswerRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("thread_id", row.ThreadID),
+		slog.Int("state", row.State),
+	)
+
+	l.DebugContext(ctx, "updating answer row")
+	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
+		UPDATE answers
+		SET
+			text = :text
+		WHERE
+			thread_id = :thread_id
+			AND state = :state
+		`,
+		row,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to update answer row", slog.String("error", err.Error()))
+		return fmt.Errorf("updating answer rows: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) deleteAnswerRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	rows []answerRow,
+) error {
+	const op = "PostgresRepository.deleteAnswerRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int("rows", len(rows)),
+	)
+
+	l.DebugContext(ctx, "deleting answer rows")
+	for _, row := range rows {
+		err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
+			DELETE FROM answers
+			WHERE
+				thread_id = :thread_id
+				AND state = :state
+			`,
+			row,
+		))
+		if err != nil {
+			l.ErrorContext(ctx, "failed to delete answer rows", slog.String("error", err.Error()))
+			return fmt.Errorf("deleting answer rows: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) softDeleteBotRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	const op = "PostgresRepository.softDeleteBotRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+	)
+
+	l.DebugContext(ctx, "deleting bot row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		UPDATE bots
+		SET deleted_at = now()
+		WHERE id = $1
+		`,
+		botID,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to soft delete bot", slog.String("error", err.Error()))
+		return fmt.Errorf("deleting bot row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) getDeletedBotRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) (deletedBotRow, error) {
+	var row deletedBotRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			id,
+			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
+			author,
+			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
+			created_at,
+			deleted_at
+		FROM bots
+		WHERE
+			id = $1
+			AND deleted_at IS NOT NULL
+		`,
+		botID,
+	)
+	if err != nil {
+		return row, fmt.Errorf("selecting deleted bot row: %w", err)
+	}
+	return row, nil
+}
+
+func (r *Repository) selectDeletedBotRowsByAccount(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	account string,
+) ([]deletedBotRow, error) {
+	var rows []deletedBotRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
+			author,
+			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
+			created_at,
+			deleted_at
+		FROM bots
+		WHERE
+			(
+				author = $1
+				OR id IN (
+					SELECT bot_id
+					FROM bot_collaborators
+					WHERE account_id = $1
+				)
+			)
+			AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		`,
+		account,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting deleted bot rows by account: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) selectBotIDsDeletedBefore(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	before time.Time,
+) ([]string, error) {
+	var ids []string
+	err := pgutils.Select(ctx, qc, &ids, `
+		SELECT id
+		FROM bots
+		WHERE deleted_at < $1
+		ORDER BY deleted_at
+		`,
+		before,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting bot ids deleted before: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *Repository) restoreBotRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	const op = "PostgresRepository.restoreBotRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+	)
+
+	l.DebugContext(ctx, "restoring bot row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		UPDATE bots
+		SET
+			deleted_at = NULL,
+			enabled    = false
+		WHERE
+			id = $1
+			AND deleted_at IS NOT NULL
+		`,
+		botID,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to restore bot", slog.String("error", err.Error()))
+		return fmt.Errorf("restoring bot row: %w", err)
+	}
+	return nil
+}
+
+// deleteBotThreadRows удаляет все треды бота; ответы удаляются каскадно.
+func (r *Repository) deleteBotThreadRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM threads
+		WHERE bot_id = $1
+		`,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting bot thread rows: %w", err)
+	}
+	return nil
+}
+
+// deleteBotParticipantRows удаляет всех участников бота; профили удаляются каскадно.
+func (r *Repository) deleteBotParticipantRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM participants
+		WHERE bot_id = $1
+		`,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting bot participant rows: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) deleteBotEntryRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM entries
+		WHERE bot_id = $1
+		`,
+		botID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting bot entry rows: %w", err)
+	}
+	return nil
+}
+
+// purgeBotRow удаляет строку удалённого бота. Узлы сценария, аренды, outbox, Webhook и соавторы
+// удаляются каскадно.
+func (r *Repository) purgeBotRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	const op = "PostgresRepository.purgeBotRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+	)
+
+	l.DebugContext(ctx, "purging bot row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		DELETE FROM bots
+		WHERE
+			id = $1
+			AND deleted_at IS NOT NULL
+		`,
+		botID,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to purge bot", slog.String("error", err.Error()))
+		return fmt.Errorf("purging bot row: %w", err)
+	}
+	return nil
+}
+
+// updateLegacyBotAuthorRows заменяет автора ботов с прежним числовым ID legacyID (хранится с префиксом
+// legacy:, см. миграцию 014) на account и возвращает количество изменённых строк.
+func (r *Repository) updateLegacyBotAuthorRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	legacyID string,
+	account string,
+) (int, error) {
+	const op = "PostgresRepository.updateLegacyBotAuthorRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("legacy_id", legacyID),
+	)
+
+	l.DebugContext(ctx, "updating legacy bot author rows")
+	res, err := pgutils.Exec(ctx, ec, `
+		UPDATE bots
+		SET
+			author = $2
+		WHERE
+			author = 'legacy:' || $1
+		`,
+		legacyID, account,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to update legacy bot author rows", slog.String("error", err.Error()))
+		return 0, fmt.Errorf("updating legacy bot author rows: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("updating legacy bot author rows: %w", err)
+	}
+	return int(n), nil
+}
+
+// selectLegacyBotAuthors выбирает различные прежние числовые ID авторов ботов, в том числе удалённых, без
+// префикса legacy:.
+func (r *Repository) selectLegacyBotAuthors(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+) ([]string, error) {
+	var authors []string
+	err := pgutils.Select(ctx, qc, &authors, `
+		SELECT DISTINCT
+			substring(author FROM 8) AS legacy_id
+		FROM bots
+		WHERE
+			author ~ '^legacy:[0-9]+$'
+		ORDER BY
+			legacy_id
+		`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting legacy bot authors: %w", err)
+	}
+	return authors, nil
+}
+
+// selectBotRowsToReencrypt выбирает ботов, в том числе удалённых, токены которых хранятся открыто
+// или зашифрованы не ключом keyID.
+func (r *Repository) selectBotRowsToReencrypt(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	keyID string,
+) ([]botRow, error) {
+	var rows []botRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			token,
+			token_key_id,
+			token_data_key,
+			token_ciphertext,
+			author,
+			enabled,
+			chat_policy,
+			telegram_id,
+			telegram_username,
+			created_at
+		FROM bots
+		WHERE
+			token_key_id IS DISTINCT FROM $1
+		`,
+		keyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting bot rows to reencrypt: %w", err)
+	}
+	return rows, nil
+}
+
+// updateBotTokenRow записывает перешифрованный токен бота. Возвращает sql.ErrNoRows, если токен
+// изменился после чтения: prevKeyID не совпадает с ключом в строке.
+func (r *Repository) updateBotTokenRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row botRow,
+	prevKeyID *string,
+) error {
+	const op = "PostgresRepository.updateBotTokenRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", row.ID),
+	)
+
+	l.DebugContext(ctx, "updating bot token row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		UPDATE bots
+		SET
+			token            = $2,
+			token_key_id     = $3,
+			token_data_key   = $4,
+			token_ciphertext = $5
+		WHERE
+			id = $1
+			AND token_key_id IS NOT DISTINCT FROM $6
+		`,
+		row.ID, row.Token, row.TokenKeyID, row.TokenDataKey, row.TokenCiphertext, prevKeyID,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to update bot token row", slog.String("error", err.Error()))
+		return fmt.Errorf("updating bot token row: %w", err)
+	}
+	return nil
+}
+
+// upsertLeaseRow захватывает аренду, если она свободна, истекла или уже принадлежит реплике.
+// Возвращает sql.ErrNoRows, если действующей арендой владеет другая реплика.
+func (r *Repository) upsertLeaseRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	replica string,
+	ttl time.Duration,
+) error {
+	const op = "PostgresRepository.upsertLeaseRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+		slog.String("replica", replica),
+	)
+
+	l.DebugContext(ctx, "upserting lease row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		INSERT INTO
+			bot_leases (
+				bot_id,
+				replica,
+				expires_at
+			)
+		VALUES (
+			$1,
+			$2,
+			now() + make_interval(secs => $3)
+		)
+		ON CONFLICT
+			(bot_id)
+		DO UPDATE
+		SET
+			replica            = EXCLUDED.replica,
+			expires_at         = EXCLUDED.expires_at,
+			renewed_at         = now(),
+			stop_requested     = FALSE,
+			applied_generation = bot_leases.generation
+		WHERE
+			bot_leases.replica = EXCLUDED.replica
+			OR bot_leases.expires_at < now()
+		`,
+		botID, replica, ttl.Seconds(),
+	))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		l.ErrorContext(ctx, "failed to upsert lease row", slog.String("error", err.Error()))
+		return fmt.Errorf("upserting lease row: %w", err)
+	}
+	return err
+}
+
+func (r *Repository) renewLeaseRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	replica string,
+	ttl time.Duration,
+) ([]string, error) {
+	const op = "PostgresRepository.renewLeaseRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("replica", replica),
+	)
+
+	l.DebugContext(ctx, "renewing lease rows")
+	var ids []string
+	err := pgutils.Select(ctx, qc, &ids, `
+		UPDATE bot_leases
+		SET
+			expires_at = now() + make_interval(secs => $2),
+			renewed_at = now()
+		WHERE
+			replica = $1
+		RETURNING
+			bot_id
+		`,
+		replica, ttl.Seconds(),
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to renew lease rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("renewing lease rows: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *Repository) takeOverExpiredLeaseRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	replica string,
+	ttl time.Duration,
+) ([]string, error) {
+	const op = "PostgresRepository.takeOverExpiredLeaseRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("replica", replica),
+	)
+
+	l.DebugContext(ctx, "taking over expired lease rows")
+	var ids []string
+	// Аренды ботов, остановка которых запрошена, не перехватываются, а удаляются. Перехватившая
+	// реплика запускает бота с актуальным токеном, поэтому запрошенные перезапуски считаются применёнными.
+	err := pgutils.Select(ctx, qc, &ids, `
+		WITH stopped AS (
+			DELETE FROM bot_leases
+			WHERE
+				expires_at < now()
+				AND stop_requested
+		)
+		UPDATE bot_leases
+		SET
+			replica            = $1,
+			expires_at         = now() + make_interval(secs => $2),
+			renewed_at         = now(),
+			applied_generation = generation
+		WHERE
+			bot_id IN (
+				SELECT bot_id
+				FROM bot_leases
+				WHERE
+					expires_at < now()
+					AND NOT stop_requested
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			bot_id
+		`,
+		replica, ttl.Seconds(),
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to take over expired lease rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("taking over expired lease rows: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *Repository) deleteLeaseRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	replica string,
+) error {
+	const op = "PostgresRepository.deleteLeaseRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+		slog.String("replica", replica),
+	)
+
+	l.DebugContext(ctx, "deleting lease row")
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM bot_leases
+		WHERE
+			bot_id = $1
+			AND replica = $2
+		`,
+		botID, replica,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to delete lease row", slog.String("error", err.Error()))
+		return fmt.Errorf("deleting lease row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) expireLeaseRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	replica string,
+) error {
+	const op = "PostgresRepository.expireLeaseRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+		slog.String("replica", replica),
+	)
+
+	l.DebugContext(ctx, "expiring lease row")
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE bot_leases
+		SET
+			expires_at = now()
+		WHERE
+			bot_id = $1
+			AND replica = $2
+		`,
+		botID, replica,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to expire lease row", slog.String("error", err.Error()))
+		return fmt.Errorf("expiring lease row: %w", err)
+	}
+	return nil
+}
+
+// updateLeaseRowGeneration увеличивает номер запрошенного перезапуска действующей аренды и возвращает его.
+func (r *Repository) updateLeaseRowGeneration(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) (int64, error) {
+	const op = "PostgresRepository.updateLeaseRowGeneration"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+	)
+
+	l.DebugContext(ctx, "requesting lease restart")
+	var generation int64
+	err := pgutils.Get(ctx, qc, &generation, `
+		UPDATE bot_leases
+		SET
+			generation     = generation + 1,
+			stop_requested = FALSE
+		WHERE
+			bot_id = $1
+			AND expires_at > now()
+		RETURNING
+			generation
+		`,
+		botID,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		l.ErrorContext(ctx, "failed to request lease restart", slog.String("error", err.Error()))
+		return 0, fmt.Errorf("updating lease row generation: %w", err)
+	}
+	return generation, err
+}
+
+func (r *Repository) updateLeaseRowStopRequested(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+) error {
+	const op = "PostgresRepository.updateLeaseRowStopRequested"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+	)
+
+	l.DebugContext(ctx, "requesting lease stop")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		UPDATE bot_leases
+		SET
+			stop_requested = TRUE
+		WHERE
+			bot_id = $1
+			AND expires_at > now()
+		`,
+		botID,
+	))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		l.ErrorContext(ctx, "failed to request lease stop", slog.String("error", err.Error()))
+		return fmt.Errorf("updating lease row stop requested: %w", err)
+	}
+	return err
+}
+
+func (r *Repository) selectLeaseChangeRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	replica string,
+) ([]leaseChangeRow, error) {
+	var rows []leaseChangeRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			bot_id,
+			stop_requested,
+			generation
+		FROM bot_leases
+		WHERE
+			replica = $1
+			AND (
+				stop_requested
+				OR generation > applied_generation
+			)
+		`,
+		replica,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting lease change rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) updateLeaseRowAppliedGeneration(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	replica string,
+	generation int64,
+) error {
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE bot_leases
+		SET
+			applied_generation = GREATEST(applied_generation, $3)
+		WHERE
+			bot_id = $1
+			AND replica = $2
+		`,
+		botID, replica, generation,
+	)
+	if err != nil {
+		return fmt.Errorf("updating lease row applied generation: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) getLeaseRestartApplied(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	generation int64,
+) (bool, error) {
+	var applied bool
+	err := pgutils.Get(ctx, qc, &applied, `
+		SELECT
+			applied_generation >= $2
+		FROM bot_leases
+		WHERE
+			bot_id = $1
+			AND expires_at > now()
+		`,
+		botID, generation,
+	)
+	if err != nil {
+		return false, fmt.Errorf("selecting lease applied generation: %w", err)
+	}
+	return applied, nil
+}
+
+func (r *Repository) getLeaseRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) (leaseRow, error) {
+	var row leaseRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			bot_id,
+			replica,
+			renewed_at,
+			expires_at
+		FROM bot_leases
+		WHERE
+			bot_id = $1
+		`,
+		botID,
+	)
+	if err != nil {
+		return leaseRow{}, fmt.Errorf("selecting lease row: %w", err)
+	}
+	return row, nil
+}
+
+func (r *Repository) getLeaseOwner(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) (string, error) {
+	var replica string
+	err := pgutils.Get(ctx, qc, &replica, `
+		SELECT
+			replica
+		FROM bot_leases
+		WHERE
+			bot_id = $1
+			AND expires_at > now()
+		`,
+		botID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("selecting lease owner: %w", err)
+	}
+	return replica, nil
+}
+
+func (r *Repository) getUpdateOffset(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) (int64, error) {
+	var offset int64
+	err := pgutils.Get(ctx, qc, &offset, `
+		SELECT
+			update_id
+		FROM bot_update_offsets
+		WHERE
+			bot_id = $1
+		`,
+		botID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("selecting update offset: %w", err)
+	}
+	return offset, nil
+}
+
+func (r *Repository) upsertUpdateOffset(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	offset int64,
+) error {
+	const op = "PostgresRepository.upsertUpdateOffset"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+		slog.Int64("update_id", offset),
+	)
+
+	l.DebugContext(ctx, "upserting update offset")
+	_, err := pgutils.Exec(ctx, ec, `
+		INSERT INTO
+			bot_update_offsets (
+				bot_id,
+				update_id,
+				updated_at
+			)
+		VALUES (
+			$1,
+			$2,
+			now()
+		)
+		ON CONFLICT
+			(bot_id)
+		DO UPDATE
+		SET
+			update_id  = GREATEST(bot_update_offsets.update_id, EXCLUDED.update_id),
+			updated_at = now()
+		`,
+		botID, offset,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to upsert update offset", slog.String("error", err.Error()))
+		return fmt.Errorf("upserting update offset: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) insertOutboxRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	rows []outboxRow,
+) error {
+	const op = "PostgresRepository.insertOutboxRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int("rows", len(rows)),
+	)
+
+	l.DebugContext(ctx, "inserting outbox rows")
+	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			outbox_messages (
+				bot_id,
+				chat_id,
+				user_id,
+				text,
+				options
+			)
+		VALUES (
+			:bot_id,
+			:chat_id,
+			:user_id,
+			:text,
+			:options
+		)
+		`,
+		rows,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to insert outbox rows", slog.String("error", err.Error()))
+		return fmt.Errorf("inserting outbox rows: %w", err)
+	}
+	return nil
+}
+
+// claimOutboxRows захватывает самые ранние ожидающие сообщения чатов. Повторная проверка
+// locked_until во внешнем WHERE не даёт двум репликам захватить одно и то же сообщение.
+func (r *Repository) claimOutboxRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	limit int,
+	lockFor time.Duration,
+) ([]outboxRow, error) {
+	const op = "PostgresRepository.claimOutboxRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int("limit", limit),
+	)
+
+	l.DebugContext(ctx, "claiming outbox rows")
+	var rows []outboxRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		UPDATE outbox_messages
+		SET
+			locked_until = now() + make_interval(secs => $2)
+		WHERE
+			id IN (
+				SELECT head.id
+				FROM (
+					SELECT DISTINCT ON (bot_id, chat_id)
+						id,
+						next_attempt_at,
+						locked_until
+					FROM outbox_messages
+					WHERE failed_at IS NULL
+					ORDER BY bot_id, chat_id, id
+				) head
+				WHERE
+					head.next_attempt_at <= now()
+					AND (head.locked_until IS NULL OR head.locked_until < now())
+				ORDER BY head.id
+				LIMIT $1
+			)
+			AND (locked_until IS NULL OR locked_until < now())
+		RETURNING
+			id,
+			bot_id,
+			chat_id,
+			user_id,
+			text,
+			options,
+			attempts
+		`,
+		limit, lockFor.Seconds(),
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to claim outbox rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("claiming outbox rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) deleteOutboxRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	id int64,
+) error {
+	const op = "PostgresRepository.deleteOutboxRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	l.DebugContext(ctx, "deleting outbox row")
+	_, err := pgutils.Exec(ctx, ec, `
+		DELETE FROM outbox_messages
+		WHERE
+			id = $1
+		`,
+		id,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to delete outbox row", slog.String("error", err.Error()))
+		return fmt.Errorf("deleting outbox row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) retryOutboxRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	id int64,
+	at time.Time,
+	reason string,
+) error {
+	const op = "PostgresRepository.retryOutboxRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	l.DebugContext(ctx, "rescheduling outbox row")
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE outbox_messages
+		SET
+			attempts        = attempts + 1,
+			last_error      = $2,
+			next_attempt_at = $3,
+			locked_until    = NULL
+		WHERE
+			id = $1
+		`,
+		id, reason, at,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to reschedule outbox row", slog.String("error", err.Error()))
+		return fmt.Errorf("rescheduling outbox row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) failOutboxRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	id int64,
+	reason string,
+) error {
+	const op = "PostgresRepository.failOutboxRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	l.DebugContext(ctx, "failing outbox row")
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE outbox_messages
+		SET
+			attempts     = attempts + 1,
+			last_error   = $2,
+			failed_at    = now(),
+			locked_until = NULL
+		WHERE
+			id = $1
+		`,
+		id, reason,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to fail outbox row", slog.String("error", err.Error()))
+		return fmt.Errorf("failing outbox row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) failChatOutboxRows(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	chatID int64,
+	userID int64,
+	reason string,
+) error {
+	const op = "PostgresRepository.failChatOutboxRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+		slog.Int64("chat_id", chatID),
+		slog.Int64("user_id", userID),
+	)
+
+	l.DebugContext(ctx, "failing chat outbox rows")
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE outbox_messages
+		SET
+			last_error   = $4,
+			failed_at    = now(),
+			locked_until = NULL
+		WHERE
+			bot_id = $1
+			AND chat_id = $2
+			AND user_id = $3
+			AND failed_at IS NULL
+		`,
+		botID, chatID, userID, reason,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to fail chat outbox rows", slog.String("error", err.Error()))
+		return fmt.Errorf("failing chat outbox rows: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) upsertCollaboratorRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row collaboratorRow,
+) error {
+	const op = "PostgresRepository.upsertCollaboratorRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", row.BotID),
+		slog.String("account_id", row.AccountID),
+	)
+
+	l.DebugContext(ctx, "upserting collaborator row")
+	_, err := pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			bot_collaborators (
+				bot_id,
+				account_id,
+				role,
+				added_at
+			)
+		VALUES (
+			:bot_id,
+			:account_id,
+			:role,
+			:added_at
+		)
+		ON CONFLICT
+			(bot_id, account_id)
+		DO UPDATE
+		SET
+			role = :role
+		`,
+		row,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to upsert collaborator row", slog.String("error", err.Error()))
+		return fmt.Errorf("upserting collaborator row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) getCollaboratorRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	accountID string,
+) (collaboratorRow, error) {
+	var row collaboratorRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			bot_id,
+			account_id,
+			role,
+			added_at
+		FROM bot_collaborators
+		WHERE
+			bot_id = $1
+			AND account_id = $2
+		`,
+		botID, accountID,
+	)
+	if err != nil {
+		return row, fmt.Errorf("selecting collaborator row: %w", err)
+	}
+	return row, nil
+}
+
+func (r *Repository) selectBotCollaboratorRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) ([]collaboratorRow, error) {
+	var rows []collaboratorRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			bot_id,
+			account_id,
+			role,
+			added_at
+		FROM bot_collaborators
+		WHERE
+			bot_id = $1
+		ORDER BY
+			added_at, account_id
+		`,
+		botID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting bot collaborator rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) deleteCollaboratorRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	botID string,
+	accountID string,
+) error {
+	const op = "PostgresRepository.deleteCollaboratorRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", botID),
+		slog.String("account_id", accountID),
+	)
+
+	l.DebugContext(ctx, "deleting collaborator row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		DELETE FROM bot_collaborators
+		WHERE
+			bot_id = $1
+			AND account_id = $2
+		`,
+		botID, accountID,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to delete collaborator row", slog.String("error", err.Error()))
+		return fmt.Errorf("deleting collaborator row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) upsertWebhookRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row webhookRow,
+) error {
+	const op = "PostgresRepository.upsertWebhookRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", row.BotID),
+		slog.String("webhook_id", row.ID),
+	)
+
+	l.DebugContext(ctx, "upserting webhook row")
+	_, err := pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			webhooks (
+				id,
+				bot_id,
+				url,
+				secret,
+				secret_key_id,
+				secret_data_key,
+				secret_ciphertext,
+				events,
+				created_at
+			)
+		VALUES (
+			:id,
+			:bot_id,
+			:url,
+			:secret,
+			:secret_key_id,
+			:secret_data_key,
+			:secret_ciphertext,
+			:events,
+			:created_at
+		)
+		ON CONFLICT
+			(bot_id, id)
+		DO UPDATE
+		SET
+			url               = :url,
+			secret            = :secret,
+			secret_key_id     = :secret_key_id,
+			secret_data_key   = :secret_data_key,
+			secret_ciphertext = :secret_ciphertext,
+			events            = :events
+		`,
+		row,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to upsert webhook row", slog.String("error", err.Error()))
+		return fmt.Errorf("upserting webhook row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) getWebhookRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	webhookID string,
+) (webhookRow, error) {
+	var row webhookRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			id,
+			bot_id,
+			url,
+			secret,
+			secret_key_id,
+			secret_data_key,
+			secret_ciphertext,
+			events,
+			created_at
+		FROM webhooks
+		WHERE
+			bot_id = $1
+			AND id = $2
+		`,
+		botID, webhookID,
+	)
+	if err != nil {
+		return row, fmt.Errorf("selecting webhook row: %w", err)
+	}
+	return row, nil
+}
+
+func (r *Repository) selectBotWebhookRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+) ([]webhookRow, error) {
+	var rows []webhookRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			bot_id,
+			url,
+			secret,
+			secret_key_id,
+			secret_data_key,
+			secret_ciphertext,
+			events,
+			created_at
+		FROM webhooks
+		WHERE
+			bot_id = $1
+		ORDER BY
+			created_at, id
+		`,
+		botID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting bot webhook rows: %w", err)
+	}
+	return rows, nil
+}
 
-	l.DebugContext(ctx, "updating answer row")
-	err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
-		UPDATE answers
+// selectWebhookRowsToReencrypt выбирает Webhook, секреты которых хранятся открыто или зашифрованы
+// не ключом keyID.
+func (r *Repository) selectWebhookRowsToReencrypt(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	keyID string,
+) ([]webhookRow, error) {
+	var rows []webhookRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			bot_id,
+			url,
+			secret,
+			secret_key_id,
+			secret_data_key,
+			secret_ciphertext,
+			events,
+			created_at
+		FROM webhooks
+		WHERE
+			secret_key_id IS DISTINCT FROM $1
+		`,
+		keyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting webhook rows to reencrypt: %w", err)
+	}
+	return rows, nil
+}
+
+// updateWebhookSecretRow записывает перешифрованный секрет Webhook. Возвращает sql.ErrNoRows, если
+// секрет изменился после чтения: prevKeyID не совпадает с ключом в строке.
+func (r *Repository) updateWebhookSecretRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row webhookRow,
+	prevKeyID *string,
+) error {
+	const op = "PostgresRepository.updateWebhookSecretRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", row.BotID),
+		slog.String("webhook_id", row.ID),
+	)
+
+	l.DebugContext(ctx, "updating webhook secret row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		UPDATE webhooks
 		SET
-			text = :text
+			secret            = $3,
+			secret_key_id     = $4,
+			secret_data_key   = $5,
+			secret_ciphertext = $6
 		WHERE
-			thread_id = :thread_id
-			AND state = :state
+			bot_id = $1
+			AND id = $2
+			AND secret_key_id IS NOT DISTINCT FROM $7
 		`,
-		row,
+		row.BotID, row.ID, row.Secret, row.SecretKeyID, row.SecretDataKey, row.SecretCiphertext, prevKeyID,
 	))
 	if err != nil {
-		l.ErrorContext(ctx, "failed to update answer row", slog.String("error", err.Error()))
-		return fmt.Errorf("updating answer rows: %w", err)
+		l.ErrorContext(ctx, "failed to update webhook secret row", slog.String("error", err.Error()))
+		return fmt.Errorf("updating webhook secret row: %w", err)
 	}
 	return nil
 }
 
-func (r *Repository) deleteAnswerRows(
+func (r *Repository) deleteWebhookRow(
 	ctx context.Context,
 	ec sqlx.ExtContext,
-	rows []answerRow,
+	botID string,
+	webhookID string,
 ) error {
-	const op = "PostgresRepository.deleteAnswerRows"
+	const op = "PostgresRepository.deleteWebhookRow"
 	l := r.l.With(
 		slog.String("op", op),
-		slog.Int("rows", len(rows)),
+		slog.String("bot_id", botID),
+		slog.String("webhook_id", webhookID),
 	)
 
-	l.DebugContext(ctx, "deleting answer rows")
-	for _, row := range rows {
-		err := pgutils.RequireAffected(pgutils.NamedExec(ctx, ec, `
-			DELETE FROM answers
-			WHERE
-				thread_id = :thread_id
-				AND state = :state
-			`,
-			row,
-		))
-		if err != nil {
-			l.ErrorContext(ctx, "failed to delete answer rows", slog.String("error", err.Error()))
-			return fmt.Errorf("deleting answer rows: %w", err)
-		}
+	l.DebugContext(ctx, "deleting webhook row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		DELETE FROM webhooks
+		WHERE
+			bot_id = $1
+			AND id = $2
+		`,
+		botID, webhookID,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to delete webhook row", slog.String("error", err.Error()))
+		return fmt.Errorf("deleting webhook row: %w", err)
 	}
 	return nil
 }
 
-func (r *Repository) softDeleteBotRow(
+func (r *Repository) insertWebhookDeliveryRow(
 	ctx context.Context,
 	ec sqlx.ExtContext,
 	botID string,
+	webhookID string,
+	eventUUID string,
+	event string,
+	payload []byte,
 ) error {
-	const op = "PostgresRepository.softDeleteBotRow"
+	const op = "PostgresRepository.insertWebhookDeliveryRow"
 	l := r.l.With(
 		slog.String("op", op),
 		slog.String("bot_id", botID),
+		slog.String("webhook_id", webhookID),
+		slog.String("event_uuid", eventUUID),
+		slog.String("event", event),
 	)
 
-	l.DebugContext(ctx, "deleting bot row")
+	l.DebugContext(ctx, "inserting webhook delivery row")
+	_, err := pgutils.Exec(ctx, ec, `
+		INSERT INTO
+			webhook_deliveries (
+				bot_id,
+				webhook_id,
+				event_uuid,
+				event,
+				payload
+			)
+		VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5
+		)
+		ON CONFLICT (webhook_id, event_uuid) DO NOTHING
+		`,
+		botID, webhookID, eventUUID, event, payload,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to insert webhook delivery row", slog.String("error", err.Error()))
+		return fmt.Errorf("inserting webhook delivery row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) claimWebhookDeliveryRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	limit int,
+	lockFor time.Duration,
+) ([]webhookDeliveryRow, error) {
+	const op = "PostgresRepository.claimWebhookDeliveryRows"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int("limit", limit),
+	)
+
+	l.DebugContext(ctx, "claiming webhook delivery rows")
+	var rows []webhookDeliveryRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		UPDATE webhook_deliveries
+		SET
+			locked_until = now() + make_interval(secs => $2)
+		WHERE
+			id IN (
+				SELECT id
+				FROM webhook_deliveries
+				WHERE
+					status = 'pending'
+					AND next_attempt_at <= now()
+					AND (locked_until IS NULL OR locked_until < now())
+				ORDER BY id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			id,
+			bot_id,
+			webhook_id,
+			event,
+			payload,
+			status,
+			attempts,
+			response_code,
+			last_error,
+			created_at,
+			next_attempt_at,
+			delivered_at
+		`,
+		limit, lockFor.Seconds(),
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to claim webhook delivery rows", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("claiming webhook delivery rows: %w", err)
+	}
+	return rows, nil
+}
+
+// updateWebhookDeliveryRow фиксирует результат попытки доставки. Для доставки, ожидающей
+// повторной попытки, nextAttemptAt задаёт её момент.
+func (r *Repository) updateWebhookDeliveryRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	id int64,
+	status string,
+	responseCode int,
+	reason string,
+	nextAttemptAt time.Time,
+) error {
+	const op = "PostgresRepository.updateWebhookDeliveryRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+		slog.String("status", status),
+	)
+
+	l.DebugContext(ctx, "updating webhook delivery row")
+	_, err := pgutils.Exec(ctx, ec, `
+		UPDATE webhook_deliveries
+		SET
+			status          = $2,
+			attempts        = attempts + 1,
+			response_code   = $3,
+			last_error      = $4,
+			next_attempt_at = $5,
+			locked_until    = NULL,
+			delivered_at    = CASE WHEN $2 = 'delivered' THEN now() END
+		WHERE
+			id = $1
+		`,
+		id, status, responseCode, reason, nextAttemptAt,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to update webhook delivery row", slog.String("error", err.Error()))
+		return fmt.Errorf("updating webhook delivery row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) selectWebhookDeliveryRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	webhookID string,
+	limit int,
+) ([]webhookDeliveryRow, error) {
+	var rows []webhookDeliveryRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			bot_id,
+			webhook_id,
+			event,
+			payload,
+			status,
+			attempts,
+			response_code,
+			last_error,
+			created_at,
+			next_attempt_at,
+			delivered_at
+		FROM webhook_deliveries
+		WHERE
+			bot_id = $1
+			AND webhook_id = $2
+		ORDER BY
+			id DESC
+		LIMIT $3
+		`,
+		botID, webhookID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting webhook delivery rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) insertAPIKeyRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row apiKeyRow,
+) error {
+	const op = "PostgresRepository.insertAPIKeyRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("id", row.ID),
+		slog.String("account_id", row.AccountID),
+	)
+
+	l.DebugContext(ctx, "inserting api key row")
+	_, err := pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			api_keys (
+				id,
+				account_id,
+				name,
+				hash,
+				bots,
+				actions,
+				expires_at,
+				last_used_at,
+				created_at
+			)
+		VALUES (
+			:id,
+			:account_id,
+			:name,
+			:hash,
+			:bots,
+			:actions,
+			:expires_at,
+			:last_used_at,
+			:created_at
+		)
+		`,
+		row,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to insert api key row", slog.String("error", err.Error()))
+		return fmt.Errorf("inserting api key row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) getAPIKeyRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	id string,
+) (apiKeyRow, error) {
+	var row apiKeyRow
+	err := pgutils.Get(ctx, qc, &row, `
+		SELECT
+			id,
+			account_id,
+			name,
+			hash,
+			bots,
+			actions,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM api_keys
+		WHERE
+			id = $1
+		`,
+		id,
+	)
+	if err != nil {
+		return row, fmt.Errorf("selecting api key row: %w", err)
+	}
+	return row, nil
+}
+
+func (r *Repository) selectAccountAPIKeyRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	accountID string,
+) ([]apiKeyRow, error) {
+	var rows []apiKeyRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			account_id,
+			name,
+			hash,
+			bots,
+			actions,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM api_keys
+		WHERE
+			account_id = $1
+		ORDER BY
+			created_at, id
+		`,
+		accountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting account api key rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *Repository) touchAPIKeyRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	id string,
+	usedAt time.Time,
+) error {
+	const op = "PostgresRepository.touchAPIKeyRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	l.DebugContext(ctx, "touching api key row")
 	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
-		UPDATE bots
-		SET deleted_at = now()
-		WHERE id = $1
+		UPDATE api_keys
+		SET
+			last_used_at = $2
+		WHERE
+			id = $1
 		`,
-		botID,
+		id, usedAt,
 	))
 	if err != nil {
-		l.ErrorContext(ctx, "failed to soft delete bot", slog.String("error", err.Error()))
-		return fmt.Errorf("deleting bot row: %w", err)
+		l.ErrorContext(ctx, "failed to touch api key row", slog.String("error", err.Error()))
+		return fmt.Errorf("touching api key row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) deleteAPIKeyRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	id string,
+) error {
+	const op = "PostgresRepository.deleteAPIKeyRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	l.DebugContext(ctx, "deleting api key row")
+	err := pgutils.RequireAffected(pgutils.Exec(ctx, ec, `
+		DELETE FROM api_keys
+		WHERE
+			id = $1
+		`,
+		id,
+	))
+	if err != nil {
+		l.ErrorContext(ctx, "failed to delete api key row", slog.String("error", err.Error()))
+		return fmt.Errorf("deleting api key row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) insertAuditRow(
+	ctx context.Context,
+	ec sqlx.ExtContext,
+	row auditRow,
+) error {
+	const op = "PostgresRepository.insertAuditRow"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("action", row.Action),
+	)
+
+	l.DebugContext(ctx, "inserting audit row")
+	_, err := pgutils.NamedExec(ctx, ec, `
+		INSERT INTO
+			audit_log (
+				actor,
+				action,
+				bot_id,
+				payload,
+				error,
+				created_at
+			)
+		VALUES (
+			:actor,
+			:action,
+			:bot_id,
+			:payload,
+			:error,
+			:created_at
+		)
+		`,
+		row,
+	)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to insert audit row", slog.String("error", err.Error()))
+		return fmt.Errorf("inserting audit row: %w", err)
 	}
 	return nil
 }
+
+// selectBotAuditRows выбирает записи журнала аудита бота с ID меньше before (если он задан), от новых к
+// старым. Пустой action не ограничивает выборку.
+func (r *Repository) selectBotAuditRows(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	botID string,
+	action string,
+	before int64,
+	limit int,
+) ([]auditRow, error) {
+	var rows []auditRow
+	err := pgutils.Select(ctx, qc, &rows, `
+		SELECT
+			id,
+			actor,
+			action,
+			bot_id,
+			payload,
+			error,
+			created_at
+		FROM audit_log
+		WHERE
+			bot_id = $1
+			AND ($2 = '' OR action = $2)
+			AND ($3::BIGINT = 0 OR id < $3)
+		ORDER BY
+			id DESC
+		LIMIT $4
+		`,
+		botID, action, before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting bot audit rows: %w", err)
+	}
+	return rows, nil
+}
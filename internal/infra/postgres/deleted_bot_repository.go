@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zhikh23/pgutils"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func (r *Repository) DeletedBot(ctx context.Context, id bots.BotID) (port.DeletedBot, error) {
+	var res port.DeletedBot
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		row, err := r.getDeletedBotRow(ctx, tx, string(id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+		} else if err != nil {
+			return err
+		}
+		res, err = r.deletedBotFromRow(ctx, tx, row)
+		return err
+	})
+	return res, err
+}
+
+func (r *Repository) UserDeletedBots(ctx context.Context, account bots.AccountID) ([]port.DeletedBot, error) {
+	var res []port.DeletedBot
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		rows, err := r.selectDeletedBotRowsByAccount(ctx, tx, string(account))
+		if err != nil {
+			return err
+		}
+		res = make([]port.DeletedBot, len(rows))
+		for i, row := range rows {
+			res[i], err = r.deletedBotFromRow(ctx, tx, row)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (r *Repository) BotsDeletedBefore(ctx context.Context, t time.Time) ([]bots.BotID, error) {
+	ids, err := r.selectBotIDsDeletedBefore(ctx, r.db, t)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]bots.BotID, len(ids))
+	for i, id := range ids {
+		res[i] = bots.BotID(id)
+	}
+	return res, nil
+}
+
+func (r *Repository) RestoreBot(ctx context.Context, id bots.BotID) error {
+	err := r.restoreBotRow(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+	}
+	return err
+}
+
+func (r *Repository) PurgeBot(ctx context.Context, id bots.BotID) error {
+	return pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		_, err := r.getDeletedBotRow(ctx, tx, string(id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+		} else if err != nil {
+			return err
+		}
+		// Треды, участники и точки входа ссылаются на бота без каскадного удаления
+		if err = r.deleteBotThreadRows(ctx, tx, string(id)); err != nil {
+			return err
+		}
+		if err = r.deleteBotParticipantRows(ctx, tx, string(id)); err != nil {
+			return err
+		}
+		if err = r.deleteBotEntryRows(ctx, tx, string(id)); err != nil {
+			return err
+		}
+		return r.purgeBotRow(ctx, tx, string(id))
+	})
+}
+
+func (r *Repository) deletedBotFromRow(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	row deletedBotRow,
+) (port.DeletedBot, error) {
+	bot, err := r.selectBotByRow(ctx, qc, row.botRow)
+	if err != nil {
+		return port.DeletedBot{}, err
+	}
+	return port.DeletedBot{Bot: bot, DeletedAt: row.DeletedAt.In(time.Local)}, nil
+}
@@ -0,0 +1,93 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func TestPostgresDeletedBotRepository_Restore(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+	bot, err := r.Bot(ctx, id)
+	require.NoError(t, err)
+	bot.Enable()
+	require.NoError(t, r.UpsertBot(ctx, bot))
+
+	_, err = r.DeletedBot(ctx, id)
+	require.ErrorIs(t, err, port.ErrBotNotFound)
+	require.NoError(t, r.DeleteBot(ctx, id))
+
+	deleted, err := r.DeletedBot(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, id, deleted.Bot.ID())
+	require.WithinDuration(t, time.Now(), deleted.DeletedAt, time.Minute)
+
+	ds, err := r.UserDeletedBots(ctx, "author")
+	require.NoError(t, err)
+	require.Contains(t, deletedBotIDs(ds), id)
+
+	// ID удалённого бота нельзя занять
+	require.ErrorIs(t, r.UpsertBot(ctx, bot), port.ErrBotIDTaken)
+
+	require.NoError(t, r.RestoreBot(ctx, id))
+	restored, err := r.Bot(ctx, id)
+	require.NoError(t, err)
+	require.False(t, restored.Enabled())
+	require.ErrorIs(t, r.RestoreBot(ctx, id), port.ErrBotNotFound)
+}
+
+func TestPostgresDeletedBotRepository_Purge(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+	prtID := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), id)
+	err := r.UpdateOrCreateParticipant(ctx, prtID, func(_ context.Context, prt *bots.Participant) error {
+		_, err := prt.StartThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		return err
+	})
+	require.NoError(t, err)
+
+	// Окончательно удалить можно только удалённого бота
+	require.ErrorIs(t, r.PurgeBot(ctx, id), port.ErrBotNotFound)
+	require.NoError(t, r.DeleteBot(ctx, id))
+
+	ids, err := r.BotsDeletedBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Contains(t, ids, id)
+
+	require.NoError(t, r.PurgeBot(ctx, id))
+	_, err = r.DeletedBot(ctx, id)
+	require.ErrorIs(t, err, port.ErrBotNotFound)
+
+	// После окончательного удаления ID свободен
+	require.NoError(t, r.UpsertBot(ctx, bots.MustNewBot(id, bots.Token(gofakeit.UUID()), "author", bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
+				bots.MustNewMessage("Hello, world!"),
+			}, nil),
+		},
+		[]bots.Entry{
+			bots.MustNewEntry("start", bots.MustNewState(1)),
+		},
+	))))
+}
+
+func deletedBotIDs(ds []port.DeletedBot) []bots.BotID {
+	res := make([]bots.BotID, len(ds))
+	for i, d := range ds {
+		res[i] = d.Bot.ID()
+	}
+	return res
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func (r *Repository) AcquireLease(ctx context.Context, id bots.BotID, replica string, ttl time.Duration) error {
+	err := r.upsertLeaseRow(ctx, r.db, string(id), replica, ttl)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrLeaseHeld, id)
+	}
+	return err
+}
+
+func (r *Repository) RenewLeases(ctx context.Context, replica string, ttl time.Duration) ([]bots.BotID, error) {
+	ids, err := r.renewLeaseRows(ctx, r.db, replica, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return botIDsFromStrings(ids), nil
+}
+
+func (r *Repository) TakeOverExpiredLeases(
+	ctx context.Context, replica string, ttl time.Duration,
+) ([]bots.BotID, error) {
+	ids, err := r.takeOverExpiredLeaseRows(ctx, r.db, replica, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return botIDsFromStrings(ids), nil
+}
+
+func (r *Repository) ReleaseLease(ctx context.Context, id bots.BotID, replica string) error {
+	return r.deleteLeaseRow(ctx, r.db, string(id), replica)
+}
+
+func (r *Repository) ExpireLease(ctx context.Context, id bots.BotID, replica string) error {
+	return r.expireLeaseRow(ctx, r.db, string(id), replica)
+}
+
+func (r *Repository) RequestLeaseRestart(ctx context.Context, id bots.BotID) (int64, error) {
+	generation, err := r.updateLeaseRowGeneration(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: %s", port.ErrLeaseNotFound, id)
+	}
+	return generation, err
+}
+
+func (r *Repository) RequestLeaseStop(ctx context.Context, id bots.BotID) error {
+	err := r.updateLeaseRowStopRequested(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrLeaseNotFound, id)
+	}
+	return err
+}
+
+func (r *Repository) LeaseChanges(ctx context.Context, replica string) ([]port.LeaseChange, error) {
+	rows, err := r.selectLeaseChangeRows(ctx, r.db, replica)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]port.LeaseChange, len(rows))
+	for i, row := range rows {
+		res[i] = port.LeaseChange{
+			BotID:      bots.BotID(row.BotID),
+			Stop:       row.StopRequested,
+			Generation: row.Generation,
+		}
+	}
+	return res, nil
+}
+
+func (r *Repository) ConfirmLeaseChange(
+	ctx context.Context, id bots.BotID, replica string, generation int64,
+) error {
+	return r.updateLeaseRowAppliedGeneration(ctx, r.db, string(id), replica, generation)
+}
+
+func (r *Repository) LeaseRestartApplied(ctx context.Context, id bots.BotID, generation int64) (bool, error) {
+	applied, err := r.getLeaseRestartApplied(ctx, r.db, string(id), generation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("%w: %s", port.ErrLeaseNotFound, id)
+	}
+	return applied, err
+}
+
+func (r *Repository) Lease(ctx context.Context, id bots.BotID) (port.Lease, error) {
+	row, err := r.getLeaseRow(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return port.Lease{}, fmt.Errorf("%w: %s", port.ErrLeaseNotFound, id)
+	} else if err != nil {
+		return port.Lease{}, err
+	}
+	return port.Lease{
+		Replica:   row.Replica,
+		RenewedAt: row.RenewedAt,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
+
+func (r *Repository) LeaseOwner(ctx context.Context, id bots.BotID) (string, bool, error) {
+	replica, err := r.getLeaseOwner(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return replica, true, nil
+}
+
+func botIDsFromStrings(ids []string) []bots.BotID {
+	res := make([]bots.BotID, len(ids))
+	for i, id := range ids {
+		res[i] = bots.BotID(id)
+	}
+	return res
+}
@@ -0,0 +1,196 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
+)
+
+func upsertLeaseTestBot(ctx context.Context, t testing.TB, r *postgres.Repository) bots.BotID {
+	id := bots.BotID(gofakeit.UUID())
+	bot := bots.MustNewBot(id, bots.Token(gofakeit.UUID()), bots.AccountID("author"), bots.MustNewScript(
+		[]bots.Node{
+			bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
+				bots.MustNewMessage("Hello, world!"),
+			}, nil),
+		},
+		[]bots.Entry{
+			bots.MustNewEntry("start", bots.MustNewState(1)),
+		},
+	))
+	require.NoError(t, r.UpsertBot(ctx, bot))
+	return id
+}
+
+func TestPostgresLeaseRepository_AcquireHeldByAnotherReplica(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+
+	err := r.AcquireLease(ctx, id, "replica-1", time.Minute)
+	require.NoError(t, err)
+
+	err = r.AcquireLease(ctx, id, "replica-1", time.Minute)
+	require.NoError(t, err)
+
+	err = r.AcquireLease(ctx, id, "replica-2", time.Minute)
+	require.ErrorIs(t, err, port.ErrLeaseHeld)
+
+	owner, found, err := r.LeaseOwner(ctx, id)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "replica-1", owner)
+}
+
+func TestPostgresLeaseRepository_TakeOverExpired(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+
+	err := r.AcquireLease(ctx, id, "replica-1", -time.Second)
+	require.NoError(t, err)
+
+	_, found, err := r.LeaseOwner(ctx, id)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	taken, err := r.TakeOverExpiredLeases(ctx, "replica-2", time.Minute)
+	require.NoError(t, err)
+	require.Contains(t, taken, id)
+
+	owned, err := r.RenewLeases(ctx, "replica-1", time.Minute)
+	require.NoError(t, err)
+	require.NotContains(t, owned, id)
+
+	owner, found, err := r.LeaseOwner(ctx, id)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "replica-2", owner)
+}
+
+func TestPostgresLeaseRepository_Release(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+
+	err := r.AcquireLease(ctx, id, "replica-1", time.Minute)
+	require.NoError(t, err)
+
+	err = r.ReleaseLease(ctx, id, "replica-2")
+	require.NoError(t, err)
+	_, found, err := r.LeaseOwner(ctx, id)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	err = r.ReleaseLease(ctx, id, "replica-1")
+	require.NoError(t, err)
+	_, found, err = r.LeaseOwner(ctx, id)
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestPostgresLeaseRepository_Changes(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+
+	_, err := r.RequestLeaseRestart(ctx, id)
+	require.ErrorIs(t, err, port.ErrLeaseNotFound)
+
+	require.NoError(t, r.AcquireLease(ctx, id, "replica-1", time.Minute))
+
+	generation, err := r.RequestLeaseRestart(ctx, id)
+	require.NoError(t, err)
+	applied, err := r.LeaseRestartApplied(ctx, id, generation)
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	changes, err := r.LeaseChanges(ctx, "replica-1")
+	require.NoError(t, err)
+	require.Equal(t, []port.LeaseChange{{BotID: id, Generation: generation}}, changes)
+	changes, err = r.LeaseChanges(ctx, "replica-2")
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	require.NoError(t, r.ConfirmLeaseChange(ctx, id, "replica-1", generation))
+	applied, err = r.LeaseRestartApplied(ctx, id, generation)
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	require.NoError(t, r.RequestLeaseStop(ctx, id))
+	changes, err = r.LeaseChanges(ctx, "replica-1")
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.True(t, changes[0].Stop)
+}
+
+func TestPostgresLeaseRepository_TakeOverSkipsStopped(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := upsertLeaseTestBot(ctx, t, r)
+
+	require.NoError(t, r.AcquireLease(ctx, id, "replica-1", time.Minute))
+	require.NoError(t, r.RequestLeaseStop(ctx, id))
+	// Владелец упал, не применив остановку
+	db := tests.ConnectPostgresDB()
+	t.Cleanup(func() { _ = db.Close() })
+	_, err := db.ExecContext(ctx,
+		`UPDATE bot_leases SET expires_at = now() - interval '1 second' WHERE bot_id = $1`, string(id),
+	)
+	require.NoError(t, err)
+
+	taken, err := r.TakeOverExpiredLeases(ctx, "replica-2", time.Minute)
+	require.NoError(t, err)
+	require.NotContains(t, taken, id)
+	_, found, err := r.LeaseOwner(ctx, id)
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestPostgresLeaseRepository_ShutdownHandsOverBots(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	ids := []bots.BotID{upsertLeaseTestBot(ctx, t, r), upsertLeaseTestBot(ctx, t, r)}
+	for _, id := range ids {
+		require.NoError(t, r.AcquireLease(ctx, id, "replica-1", time.Minute))
+	}
+
+	// replica-1 останавливается при выкатке и завершает свои аренды
+	for _, id := range ids {
+		require.NoError(t, r.ExpireLease(ctx, id, "replica-1"))
+	}
+
+	// replica-2 перехватывает ботов при ближайшей синхронизации
+	owned, err := r.RenewLeases(ctx, "replica-2", time.Minute)
+	require.NoError(t, err)
+	taken, err := r.TakeOverExpiredLeases(ctx, "replica-2", time.Minute)
+	require.NoError(t, err)
+	for _, id := range ids {
+		require.NotContains(t, owned, id)
+		require.Contains(t, taken, id)
+		owner, found, err2 := r.LeaseOwner(ctx, id)
+		require.NoError(t, err2)
+		require.True(t, found)
+		require.Equal(t, "replica-2", owner)
+	}
+}
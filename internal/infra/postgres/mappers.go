@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
 )
 
 func operationToString(op bots.Operation) string {
@@ -64,13 +66,20 @@ func predicateFromStrings(ptype string, pdata string) (bots.Predicate, error) {
 }
 
 func botToRow(bot *bots.Bot) botRow {
-	return botRow{
-		ID:        string(bot.ID()),
-		Token:     string(bot.Token()),
-		Author:    int64(bot.Author()),
-		Enabled:   bot.Enabled(),
-		CreatedAt: bot.CreatedAt().In(time.UTC),
+	row := botRow{
+		ID:         string(bot.ID()),
+		Token:      string(bot.Token()),
+		Author:     string(bot.Author()),
+		Enabled:    bot.Enabled(),
+		ChatPolicy: bot.ChatPolicy().String(),
+		CreatedAt:  bot.CreatedAt().In(time.UTC),
 	}
+	if a := bot.Telegram(); !a.IsZero() {
+		id, username := a.ID(), a.Username()
+		row.TelegramID = &id
+		row.TelegramUsername = &username
+	}
+	return row
 }
 
 func entryToRow(botID bots.BotID, entry bots.Entry) entryRow {
@@ -105,6 +114,37 @@ func nodesToRows(botID bots.BotID, nodes []bots.Node) []nodeRow {
 	return res
 }
 
+// requestsToRows возвращает строку запроса для узла-запроса или пустой список для обычного узла.
+func requestsToRows(botID bots.BotID, node bots.Node) []requestRow {
+	if !node.IsRequest() {
+		return nil
+	}
+	req := node.Request()
+	return []requestRow{{
+		BotID:     string(botID),
+		State:     node.State().Int(),
+		Method:    req.Method(),
+		URL:       req.URL(),
+		Headers:   req.Headers(),
+		Body:      req.Body(),
+		Save:      req.Save(),
+		OnSuccess: req.OnSuccess().Int(),
+		OnFailure: req.OnFailure().Int(),
+	}}
+}
+
+func requestFromRow(row requestRow) (bots.Request, error) {
+	onSuccess, err := bots.NewState(row.OnSuccess)
+	if err != nil {
+		return bots.Request{}, err
+	}
+	onFailure, err := bots.NewState(row.OnFailure)
+	if err != nil {
+		return bots.Request{}, err
+	}
+	return bots.NewRequest(row.Method, row.URL, row.Headers, row.Body, row.Save, onSuccess, onFailure)
+}
+
 func edgeToRow(botID bots.BotID, state bots.State, edge bots.Edge) edgeRow {
 	ptype, pdata := predicateToStrings(edge.Predicate)
 	return edgeRow{
@@ -163,20 +203,46 @@ func participantToRow(prt *bots.Participant) participantRow {
 		s := string(thread.ID())
 		activeThreadID = &s
 	}
+	var blockedAt *time.Time
+	if prt.IsBlocked() {
+		t := prt.BlockedAt()
+		blockedAt = &t
+	}
 	return participantRow{
 		BotID:        string(prt.ID().BotID()),
+		ChatID:       int64(prt.ID().ChatID()),
 		UserID:       int64(prt.ID().UserID()),
 		ActiveThread: activeThreadID,
+		BlockedAt:    blockedAt,
+		LastUpdateID: int64(prt.LastUpdateID()),
 	}
 }
 
-func threadToRow(botID bots.BotID, userID bots.UserID, thread *bots.Thread) threadRow {
+func profileToRow(id bots.ParticipantID, profile bots.Profile) profileRow {
+	return profileRow{
+		BotID:        string(id.BotID()),
+		ChatID:       int64(id.ChatID()),
+		UserID:       int64(id.UserID()),
+		Username:     string(profile.Username()),
+		FirstName:    profile.FirstName(),
+		LastName:     profile.LastName(),
+		LanguageCode: profile.LanguageCode(),
+	}
+}
+
+func profileFromRow(row profileRow) bots.Profile {
+	return bots.NewProfile(bots.Username(row.Username), row.FirstName, row.LastName, row.LanguageCode)
+}
+
+func threadToRow(id bots.ParticipantID, thread *bots.Thread) threadRow {
 	return threadRow{
 		ID:        string(thread.ID()),
-		BotID:     string(botID),
-		UserID:    int64(userID),
+		BotID:     string(id.BotID()),
+		ChatID:    int64(id.ChatID()),
+		UserID:    int64(id.UserID()),
 		Key:       string(thread.Key()),
 		State:     thread.State().Int(),
+		Vars:      thread.Vars(),
 		StartedAt: thread.StartedAt(),
 	}
 }
@@ -196,3 +262,160 @@ func answersToRows(threadID bots.ThreadID, answers map[bots.State]bots.Message)
 	}
 	return res
 }
+
+func outboxToRows(id bots.ParticipantID, msgs []bots.BotMessage) []outboxRow {
+	res := make([]outboxRow, len(msgs))
+	for i, msg := range msgs {
+		opts := make([]string, len(msg.Options()))
+		for j, opt := range msg.Options() {
+			opts[j] = opt.String()
+		}
+		res[i] = outboxRow{
+			BotID:   string(id.BotID()),
+			ChatID:  int64(id.ChatID()),
+			UserID:  int64(id.UserID()),
+			Text:    msg.Text(),
+			Options: opts,
+		}
+	}
+	return res
+}
+
+func outboxFromRow(row outboxRow) (port.OutboxMessage, error) {
+	msg, err := bots.NewMessage(row.Text)
+	if err != nil {
+		return port.OutboxMessage{}, err
+	}
+	opts := make([]bots.Option, len(row.Options))
+	for i, s := range row.Options {
+		opts[i], err = bots.NewOption(s)
+		if err != nil {
+			return port.OutboxMessage{}, err
+		}
+	}
+	return port.OutboxMessage{
+		ID: row.ID,
+		ParticipantID: bots.NewChatParticipantID(
+			bots.ChatID(row.ChatID), bots.UserID(row.UserID), bots.BotID(row.BotID),
+		),
+		Message:  msg.PromoteToBotMessage(opts),
+		Attempts: row.Attempts,
+	}, nil
+}
+
+func collaboratorToRow(c *bots.Collaborator) collaboratorRow {
+	return collaboratorRow{
+		BotID:     string(c.BotID()),
+		AccountID: string(c.AccountID()),
+		Role:      c.Role().String(),
+		AddedAt:   c.AddedAt(),
+	}
+}
+
+func collaboratorFromRow(row collaboratorRow) (*bots.Collaborator, error) {
+	return bots.UnmarshallCollaborator(row.BotID, row.AccountID, row.Role, row.AddedAt)
+}
+
+func apiKeyToRow(k *bots.APIKey) apiKeyRow {
+	scope := k.Scope()
+	bs := make([]string, len(scope.Bots()))
+	for i, b := range scope.Bots() {
+		bs[i] = string(b)
+	}
+	as := make([]string, len(scope.Permissions()))
+	for i, p := range scope.Permissions() {
+		as[i] = p.String()
+	}
+	row := apiKeyRow{
+		ID:        string(k.ID()),
+		AccountID: string(k.AccountID()),
+		Name:      k.Name(),
+		Hash:      k.Hash(),
+		Bots:      bs,
+		Actions:   as,
+		ExpiresAt: k.ExpiresAt(),
+		CreatedAt: k.CreatedAt(),
+	}
+	if t := k.LastUsedAt(); !t.IsZero() {
+		row.LastUsedAt = &t
+	}
+	return row
+}
+
+func apiKeyFromRow(row apiKeyRow) (*bots.APIKey, error) {
+	var lastUsedAt time.Time
+	if row.LastUsedAt != nil {
+		lastUsedAt = *row.LastUsedAt
+	}
+	return bots.UnmarshallAPIKey(
+		row.ID, row.AccountID, row.Name, row.Hash, row.Bots, row.Actions, row.ExpiresAt, lastUsedAt, row.CreatedAt,
+	)
+}
+
+func webhookToRow(w *bots.Webhook) webhookRow {
+	return webhookRow{
+		ID:        string(w.ID()),
+		BotID:     string(w.BotID()),
+		URL:       w.URL(),
+		Secret:    w.Secret(),
+		Events:    w.Events(),
+		CreatedAt: w.CreatedAt(),
+	}
+}
+
+func webhookFromRow(row webhookRow) (*bots.Webhook, error) {
+	return bots.UnmarshallWebhook(row.ID, row.BotID, row.URL, row.Secret, row.Events, row.CreatedAt)
+}
+
+func webhookDeliveryFromRow(row webhookDeliveryRow) port.WebhookDelivery {
+	d := port.WebhookDelivery{
+		ID:            row.ID,
+		BotID:         bots.BotID(row.BotID),
+		WebhookID:     bots.WebhookID(row.WebhookID),
+		Event:         row.Event,
+		Payload:       row.Payload,
+		Status:        port.WebhookDeliveryStatus(row.Status),
+		Attempts:      row.Attempts,
+		ResponseCode:  row.ResponseCode,
+		LastError:     row.LastError,
+		CreatedAt:     row.CreatedAt,
+		NextAttemptAt: row.NextAttemptAt,
+	}
+	if row.DeliveredAt != nil {
+		d.DeliveredAt = *row.DeliveredAt
+	}
+	return d
+}
+
+func auditEntryToRow(e decorator.AuditEntry) auditRow {
+	row := auditRow{
+		Actor:     e.Actor,
+		Action:    e.Action,
+		Payload:   e.Payload,
+		CreatedAt: e.At.In(time.UTC),
+	}
+	if e.BotID != "" {
+		row.BotID = &e.BotID
+	}
+	if e.Error != "" {
+		row.Error = &e.Error
+	}
+	return row
+}
+
+func auditRecordFromRow(row auditRow) port.AuditRecord {
+	rec := port.AuditRecord{
+		ID:        row.ID,
+		Actor:     bots.AccountID(row.Actor),
+		Action:    row.Action,
+		Payload:   row.Payload,
+		CreatedAt: row.CreatedAt.In(time.Local),
+	}
+	if row.BotID != nil {
+		rec.BotID = bots.BotID(*row.BotID)
+	}
+	if row.Error != nil {
+		rec.Error = *row.Error
+	}
+	return rec
+}
@@ -1,14 +1,61 @@
 package postgres
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"maps"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// stringMap хранится в столбце JSONB как JSON-объект со строковыми значениями.
+type stringMap map[string]string
+
+func (m stringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(m))
+}
+
+func (m *stringMap) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*m = nil
+		return nil
+	default:
+		return errors.New("unsupported type for stringMap")
+	}
+	return json.Unmarshal(b, (*map[string]string)(m))
+}
 
 type botRow struct {
 	// PK (ID)
-	ID        string    `db:"id"`
-	Token     string    `db:"token"`
-	Author    int64     `db:"author"`
-	Enabled   bool      `db:"enabled"`
-	CreatedAt time.Time `db:"created_at"`
+	ID         string    `db:"id"`
+	Token      string    `db:"token"`
+	Author     string    `db:"author"`
+	Enabled    bool      `db:"enabled"`
+	ChatPolicy string    `db:"chat_policy"`
+	CreatedAt  time.Time `db:"created_at"`
+	// TokenKeyID равен nil, если токен хранится открыто в Token.
+	TokenKeyID      *string `db:"token_key_id"`
+	TokenDataKey    []byte  `db:"token_data_key"`
+	TokenCiphertext []byte  `db:"token_ciphertext"`
+	// TelegramID равен nil, если токен бота ещё не проверялся.
+	TelegramID       *int64  `db:"telegram_id"`
+	TelegramUsername *string `db:"telegram_username"`
+}
+
+type deletedBotRow struct {
+	botRow
+	DeletedAt time.Time `db:"deleted_at"`
 }
 
 type entryRow struct {
@@ -33,6 +80,31 @@ func nodeIdentity(lhs, rhs nodeRow) bool {
 	return lhs.BotID == rhs.BotID && lhs.State == rhs.State
 }
 
+type requestRow struct {
+	// PK (BotID, State)
+	BotID     string    `db:"bot_id"`
+	State     int       `db:"state"`
+	Method    string    `db:"method"`
+	URL       string    `db:"url"`
+	Headers   stringMap `db:"headers"`
+	Body      string    `db:"body"`
+	Save      stringMap `db:"save"`
+	OnSuccess int       `db:"on_success"`
+	OnFailure int       `db:"on_failure"`
+}
+
+func requestEqual(lhs, rhs requestRow) bool {
+	return lhs.BotID == rhs.BotID &&
+		lhs.State == rhs.State &&
+		lhs.Method == rhs.Method &&
+		lhs.URL == rhs.URL &&
+		maps.Equal(lhs.Headers, rhs.Headers) &&
+		lhs.Body == rhs.Body &&
+		maps.Equal(lhs.Save, rhs.Save) &&
+		lhs.OnSuccess == rhs.OnSuccess &&
+		lhs.OnFailure == rhs.OnFailure
+}
+
 type edgeRow struct {
 	BotID     string `db:"bot_id"`
 	State     int    `db:"state"`
@@ -55,22 +127,57 @@ type optionRow struct {
 }
 
 type participantRow struct {
-	// PK(BotID, UserID)
-	BotID        string  `db:"bot_id"`
-	UserID       int64   `db:"user_id"`
-	ActiveThread *string `db:"active_thread"`
+	// PK(BotID, ChatID, UserID)
+	BotID        string     `db:"bot_id"`
+	ChatID       int64      `db:"chat_id"`
+	UserID       int64      `db:"user_id"`
+	ActiveThread *string    `db:"active_thread"`
+	BlockedAt    *time.Time `db:"blocked_at"`
+	LastUpdateID int64      `db:"last_update_id"`
+}
+
+type profileRow struct {
+	// PK(BotID, ChatID, UserID)
+	BotID        string `db:"bot_id"`
+	ChatID       int64  `db:"chat_id"`
+	UserID       int64  `db:"user_id"`
+	Username     string `db:"username"`
+	FirstName    string `db:"first_name"`
+	LastName     string `db:"last_name"`
+	LanguageCode string `db:"language_code"`
+}
+
+type participantStatsRow struct {
+	Total   int `db:"total"`
+	Blocked int `db:"blocked"`
 }
 
 type threadRow struct {
 	// PK(ID)
 	ID        string    `db:"id"`
 	BotID     string    `db:"bot_id"`
+	ChatID    int64     `db:"chat_id"`
 	UserID    int64     `db:"user_id"`
 	Key       string    `db:"key"`
 	State     int       `db:"state"`
+	Vars      stringMap `db:"vars"`
 	StartedAt time.Time `db:"started_at"`
 }
 
+// threadFilterParams есть параметры выборки тредов; nil-указатели не ограничивают выборку.
+type threadFilterParams struct {
+	EntryKey       string
+	StartedAfter   *time.Time
+	StartedBefore  *time.Time
+	Completed      *bool
+	FinalStates    []int64
+	Contains       string
+	Ascending      bool
+	AfterStartedAt *time.Time
+	AfterID        string
+	Limit          int
+}
+
 type answerRow struct {
 	// PK(ThreadID)
 	ThreadID string `db:"thread_id"`
@@ -81,3 +188,97 @@ type answerRow struct {
 func answerIdentity(lhs, rhs answerRow) bool {
 	return lhs.ThreadID == rhs.ThreadID && lhs.State == rhs.State
 }
+
+type participantRecordRow struct {
+	Source    string    `db:"source"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type outboxRow struct {
+	// PK(ID)
+	ID       int64          `db:"id"`
+	BotID    string         `db:"bot_id"`
+	ChatID   int64          `db:"chat_id"`
+	UserID   int64          `db:"user_id"`
+	Text     string         `db:"text"`
+	Options  pq.StringArray `db:"options"`
+	Attempts int            `db:"attempts"`
+}
+
+type leaseRow struct {
+	// PK(BotID)
+	BotID     string    `db:"bot_id"`
+	Replica   string    `db:"replica"`
+	RenewedAt time.Time `db:"renewed_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+type leaseChangeRow struct {
+	// PK(BotID)
+	BotID         string `db:"bot_id"`
+	StopRequested bool   `db:"stop_requested"`
+	Generation    int64  `db:"generation"`
+}
+
+type collaboratorRow struct {
+	// PK(BotID, AccountID)
+	BotID     string    `db:"bot_id"`
+	AccountID string    `db:"account_id"`
+	Role      string    `db:"role"`
+	AddedAt   time.Time `db:"added_at"`
+}
+
+type apiKeyRow struct {
+	// PK(ID)
+	ID         string         `db:"id"`
+	AccountID  string         `db:"account_id"`
+	Name       string         `db:"name"`
+	Hash       string         `db:"hash"`
+	Bots       pq.StringArray `db:"bots"`
+	Actions    pq.StringArray `db:"actions"`
+	ExpiresAt  time.Time      `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+type webhookRow struct {
+	// PK(BotID, ID)
+	ID        string         `db:"id"`
+	BotID     string         `db:"bot_id"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Events    pq.StringArray `db:"events"`
+	CreatedAt time.Time      `db:"created_at"`
+	// SecretKeyID равен nil, если секрет хранится открыто в Secret.
+	SecretKeyID      *string `db:"secret_key_id"`
+	SecretDataKey    []byte  `db:"secret_data_key"`
+	SecretCiphertext []byte  `db:"secret_ciphertext"`
+}
+
+type webhookDeliveryRow struct {
+	// PK(ID)
+	ID            int64      `db:"id"`
+	BotID         string     `db:"bot_id"`
+	WebhookID     string     `db:"webhook_id"`
+	Event         string     `db:"event"`
+	Payload       []byte     `db:"payload"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	ResponseCode  int        `db:"response_code"`
+	LastError     string     `db:"last_error"`
+	CreatedAt     time.Time  `db:"created_at"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+}
+
+type auditRow struct {
+	// PK(ID)
+	ID        int64     `db:"id"`
+	Actor     string    `db:"actor"`
+	Action    string    `db:"action"`
+	BotID     *string   `db:"bot_id"`
+	Payload   []byte    `db:"payload"`
+	Error     *string   `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zhikh23/pgutils"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func (r *Repository) UpdateOrCreateParticipantWithOutbox(
+	ctx context.Context,
+	id bots.ParticipantID,
+	updateFn func(context.Context, *bots.Participant) ([]bots.BotMessage, error),
+) error {
+	const op = "PostgresRepository.UpdateOrCreateParticipantWithOutbox"
+	l := r.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", string(id.BotID())),
+		slog.Int64("user_id", int64(id.UserID())),
+	)
+
+	return pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		prt, found, err := r.findParticipant(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			l.InfoContext(ctx, "participant not found, creating a new one")
+			prt, err = bots.NewParticipant(id)
+			if err != nil {
+				return err
+			}
+		} else {
+			l.DebugContext(ctx, "participant found")
+		}
+
+		msgs, err := updateFn(ctx, prt)
+		if err != nil {
+			return err
+		}
+
+		if err = r.upsertParticipant(ctx, tx, prt); err != nil {
+			return err
+		}
+
+		if r.events != nil {
+			if err = r.events.PublishTx(ctx, tx.Tx, prt.Events()...); err != nil {
+				return err
+			}
+		}
+
+		if len(msgs) == 0 {
+			return nil
+		}
+		return r.insertOutboxRows(ctx, tx, outboxToRows(id, msgs))
+	})
+}
+
+func (r *Repository) ClaimOutboxMessages(
+	ctx context.Context,
+	limit int,
+	lockFor time.Duration,
+) ([]port.OutboxMessage, error) {
+	rows, err := r.claimOutboxRows(ctx, r.db, limit, lockFor)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]port.OutboxMessage, len(rows))
+	for i, row := range rows {
+		res[i], err = outboxFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *Repository) DeleteOutboxMessage(ctx context.Context, id int64) error {
+	return r.deleteOutboxRow(ctx, r.db, id)
+}
+
+func (r *Repository) RetryOutboxMessage(ctx context.Context, id int64, at time.Time, reason string) error {
+	return r.retryOutboxRow(ctx, r.db, id, at, reason)
+}
+
+func (r *Repository) FailOutboxMessage(ctx context.Context, id int64, reason string) error {
+	return r.failOutboxRow(ctx, r.db, id, reason)
+}
+
+func (r *Repository) FailChatOutboxMessages(ctx context.Context, id bots.ParticipantID, reason string) error {
+	return r.failChatOutboxRows(
+		ctx, r.db, string(id.BotID()), int64(id.ChatID()), int64(id.UserID()), reason,
+	)
+}
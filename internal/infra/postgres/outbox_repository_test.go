@@ -0,0 +1,165 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
+)
+
+func enqueueOutboxTestMessages(
+	ctx context.Context, t *testing.T, r *postgres.Repository, texts ...string,
+) bots.ParticipantID {
+	botID := upsertLeaseTestBot(ctx, t, r)
+	prtID := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), botID)
+
+	msgs := make([]bots.BotMessage, len(texts))
+	for i, text := range texts {
+		msgs[i] = bots.MustNewMessage(text).PromoteToBotMessage([]bots.Option{bots.MustNewOption("Да")})
+	}
+	err := r.UpdateOrCreateParticipantWithOutbox(ctx, prtID, func(
+		_ context.Context, _ *bots.Participant,
+	) ([]bots.BotMessage, error) {
+		return msgs, nil
+	})
+	require.NoError(t, err)
+	return prtID
+}
+
+func claimParticipantOutbox(
+	ctx context.Context, t *testing.T, r *postgres.Repository, prtID bots.ParticipantID,
+) []port.OutboxMessage {
+	msgs, err := r.ClaimOutboxMessages(ctx, 1000, time.Minute)
+	require.NoError(t, err)
+	var res []port.OutboxMessage
+	for _, msg := range msgs {
+		if msg.ParticipantID == prtID {
+			res = append(res, msg)
+		}
+	}
+	return res
+}
+
+func TestPostgresOutboxRepository_ClaimsInChatOrder(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	prtID := enqueueOutboxTestMessages(ctx, t, r, "first", "second")
+
+	claimed := claimParticipantOutbox(ctx, t, r, prtID)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "first", claimed[0].Message.Text())
+	require.Equal(t, []bots.Option{bots.MustNewOption("Да")}, claimed[0].Message.Options())
+
+	// Захваченное сообщение не захватывается повторно, а следующее ждёт его отправки
+	require.Empty(t, claimParticipantOutbox(ctx, t, r, prtID))
+
+	require.NoError(t, r.DeleteOutboxMessage(ctx, claimed[0].ID))
+	claimed = claimParticipantOutbox(ctx, t, r, prtID)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "second", claimed[0].Message.Text())
+}
+
+func TestPostgresOutboxRepository_Retry(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	prtID := enqueueOutboxTestMessages(ctx, t, r, "first")
+
+	claimed := claimParticipantOutbox(ctx, t, r, prtID)
+	require.Len(t, claimed, 1)
+
+	err := r.RetryOutboxMessage(ctx, claimed[0].ID, time.Now().Add(time.Hour), "timeout")
+	require.NoError(t, err)
+	require.Empty(t, claimParticipantOutbox(ctx, t, r, prtID))
+
+	err = r.RetryOutboxMessage(ctx, claimed[0].ID, time.Now().Add(-time.Second), "timeout")
+	require.NoError(t, err)
+	claimed = claimParticipantOutbox(ctx, t, r, prtID)
+	require.Len(t, claimed, 1)
+	require.Equal(t, 2, claimed[0].Attempts)
+}
+
+func TestPostgresOutboxRepository_FailChat(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	prtID := enqueueOutboxTestMessages(ctx, t, r, "first", "second")
+
+	err := r.FailChatOutboxMessages(ctx, prtID, "user blocked bot")
+	require.NoError(t, err)
+	require.Empty(t, claimParticipantOutbox(ctx, t, r, prtID))
+}
+
+func TestPostgresOutboxRepository_Events(t *testing.T) {
+	db := tests.ConnectPostgresDB()
+	t.Cleanup(func() { _ = db.Close() })
+	l := logs.DefaultLogger()
+	r := postgres.NewRepository(db, nil, events.NewOutbox(l), l)
+
+	bus := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	t.Cleanup(func() { _ = bus.Close() })
+	fwd, err := events.NewForwarder(db.DB, l, bus)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	sub, err := bus.Subscribe(ctx, events.Topic("thread_started"))
+	require.NoError(t, err)
+	go func() { _ = fwd.Run(ctx) }()
+	<-fwd.Running()
+
+	botID := upsertLeaseTestBot(ctx, t, r)
+	start := bots.MustNewEntry("start", bots.MustNewState(1))
+
+	// События откаченной транзакции не публикуются
+	rolledBack := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), botID)
+	err = r.UpdateOrCreateParticipantWithOutbox(ctx, rolledBack, func(
+		_ context.Context, prt *bots.Participant,
+	) ([]bots.BotMessage, error) {
+		_, err2 := prt.StartThread(start)
+		require.NoError(t, err2)
+		return nil, errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	prtID := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), botID)
+	err = r.UpdateOrCreateParticipantWithOutbox(ctx, prtID, func(
+		_ context.Context, prt *bots.Participant,
+	) ([]bots.BotMessage, error) {
+		_, err2 := prt.StartThread(start)
+		return nil, err2
+	})
+	require.NoError(t, err)
+
+	for {
+		select {
+		case msg := <-sub:
+			msg.Ack()
+			var payload events.ThreadStarted
+			require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+			require.NotEqual(t, int64(rolledBack.UserID()), payload.UserID)
+			if payload.UserID == int64(prtID.UserID()) {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("event was not forwarded")
+		}
+	}
+}
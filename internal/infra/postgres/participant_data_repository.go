@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+
+	wsql "github.com/ThreeDotsLabs/watermill-sql/v3/pkg/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/zhikh23/pgutils"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+)
+
+// anonymousAnswerText заменяет тексты ответов обезличенных участников: ответ может содержать
+// персональные данные, а пустой текст ответа недопустим.
+const anonymousAnswerText = "[anonymized]"
+
+// recordSource есть таблица, в которой вне тредов хранятся копии данных участников. Поля doc и botID
+// есть SQL-выражения над строкой таблицы.
+type recordSource struct {
+	name  string
+	table string
+	// optional означает, что таблицу создаёт Watermill и её может не быть.
+	optional bool
+	// doc есть JSONB-представление события с полями chatId и userId.
+	doc   string
+	botID string
+}
+
+// recordSources возвращает очередь доставки Webhook, таблицу outbox с событиями в конвертах
+// форвардера и таблицы топиков транспорта postgres (см. events.NewPostgresPublisher).
+func recordSources() []recordSource {
+	schema := wsql.DefaultPostgreSQLSchema{}
+	outbox := "(convert_from(decode(payload->>'payload', 'base64'), 'UTF8')::jsonb)"
+	sources := []recordSource{
+		{name: "webhook_deliveries", table: "webhook_deliveries", doc: "(payload->'data')", botID: "bot_id"},
+		{
+			name:     "watermill_" + events.OutboxTopic,
+			table:    schema.MessagesTable(events.OutboxTopic),
+			optional: true,
+			doc:      outbox,
+			botID:    "(" + outbox + "->>'botId')",
+		},
+	}
+	for _, name := range bots.EventNames() {
+		topic := events.Topic(name)
+		sources = append(sources, recordSource{
+			name:     "watermill_" + topic,
+			table:    schema.MessagesTable(topic),
+			optional: true,
+			doc:      "(payload::jsonb)",
+			botID:    "(payload::jsonb->>'botId')",
+		})
+	}
+	return sources
+}
+
+// existingRecordSources возвращает источники, таблицы которых существуют.
+func (r *Repository) existingRecordSources(ctx context.Context, qc sqlx.QueryerContext) ([]recordSource, error) {
+	var res []recordSource
+	for _, src := range recordSources() {
+		if src.optional {
+			exists, err := r.tableExists(ctx, qc, src.table)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+		}
+		res = append(res, src)
+	}
+	return res, nil
+}
+
+func (r *Repository) ParticipantData(
+	ctx context.Context, userID bots.UserID, botID bots.BotID,
+) ([]port.ParticipantData, error) {
+	var res []port.ParticipantData
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		rows, err := r.selectUserParticipantRows(ctx, tx, int64(userID), string(botID))
+		if err != nil {
+			return err
+		}
+		sources, err := r.existingRecordSources(ctx, tx)
+		if err != nil {
+			return err
+		}
+		res = make([]port.ParticipantData, len(rows))
+		for i, row := range rows {
+			id := bots.NewChatParticipantID(bots.ChatID(row.ChatID), bots.UserID(row.UserID), bots.BotID(row.BotID))
+			prt, _, err2 := r.findParticipant(ctx, tx, id)
+			if err2 != nil {
+				return err2
+			}
+			threads, err2 := r.selectParticipantThreads(ctx, tx, row)
+			if err2 != nil {
+				return err2
+			}
+			records, err2 := r.selectParticipantRecords(ctx, tx, sources, row)
+			if err2 != nil {
+				return err2
+			}
+			res[i] = port.ParticipantData{Participant: prt, Threads: threads, Records: records}
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (r *Repository) EraseParticipantData(ctx context.Context, userID bots.UserID, botID bots.BotID) (int, error) {
+	var n int
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		rows, err := r.selectUserParticipantRows(ctx, tx, int64(userID), string(botID))
+		if err != nil {
+			return err
+		}
+		n = len(rows)
+		if err = r.deleteUserThreadRows(ctx, tx, int64(userID), string(botID)); err != nil {
+			return err
+		}
+		if err = r.deleteUserParticipantRows(ctx, tx, int64(userID), string(botID)); err != nil {
+			return err
+		}
+		if err = r.deleteUserOutboxRows(ctx, tx, int64(userID), string(botID)); err != nil {
+			return err
+		}
+		return r.eraseUserRecords(ctx, tx, int64(userID), string(botID))
+	})
+	return n, err
+}
+
+func (r *Repository) AnonymizeParticipantData(
+	ctx context.Context, userID bots.UserID, botID bots.BotID,
+) (int, error) {
+	var n int
+	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		rows, err := r.selectUserParticipantRows(ctx, tx, int64(userID), string(botID))
+		if err != nil {
+			return err
+		}
+		n = len(rows)
+		for _, row := range rows {
+			anonID := anonymousUserID()
+			anon := participantRow{
+				BotID:     row.BotID,
+				ChatID:    row.ChatID,
+				UserID:    anonID,
+				BlockedAt: row.BlockedAt,
+			}
+			// ID личного чата совпадает с ID пользователя
+			if row.ChatID == row.UserID {
+				anon.ChatID = anonID
+			}
+			if err = r.upsertParticipantRow(ctx, tx, anon); err != nil {
+				return err
+			}
+			if err = r.replaceParticipantAnswerTexts(ctx, tx, row, anonymousAnswerText); err != nil {
+				return err
+			}
+			if err = r.reassignParticipantThreadRows(ctx, tx, row, anon.ChatID, anon.UserID); err != nil {
+				return err
+			}
+		}
+		if err = r.deleteUserParticipantRows(ctx, tx, int64(userID), string(botID)); err != nil {
+			return err
+		}
+		if err = r.deleteUserOutboxRows(ctx, tx, int64(userID), string(botID)); err != nil {
+			return err
+		}
+		return r.eraseUserRecords(ctx, tx, int64(userID), string(botID))
+	})
+	return n, err
+}
+
+// selectParticipantRecords выбирает записи участника prt из источников sources и журнала аудита.
+func (r *Repository) selectParticipantRecords(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	sources []recordSource,
+	prt participantRow,
+) ([]port.ParticipantRecord, error) {
+	var rows []participantRecordRow
+	for _, src := range sources {
+		srcRows, err := r.selectParticipantRecordRows(ctx, qc, src, prt)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, srcRows...)
+	}
+	auditRows, err := r.selectUserAuditRecordRows(ctx, qc, prt.UserID, prt.BotID)
+	if err != nil {
+		return nil, err
+	}
+	rows = append(rows, auditRows...)
+
+	res := make([]port.ParticipantRecord, len(rows))
+	for i, row := range rows {
+		res[i] = port.ParticipantRecord{Source: row.Source, Payload: row.Payload, CreatedAt: row.CreatedAt}
+	}
+	return res, nil
+}
+
+// eraseUserRecords удаляет события и тела Webhook с данными пользователя userID в боте botID или, если
+// botID пуст, во всех ботах, а из журнала аудита удаляет его ID.
+func (r *Repository) eraseUserRecords(ctx context.Context, ec sqlx.ExtContext, userID int64, botID string) error {
+	sources, err := r.existingRecordSources(ctx, ec)
+	if err != nil {
+		return err
+	}
+	for _, src := range sources {
+		if err = r.deleteUserRecordRows(ctx, ec, src, userID, botID); err != nil {
+			return err
+		}
+	}
+	return r.scrubUserAuditRows(ctx, ec, userID, botID)
+}
+
+// anonymousUserID возвращает случайный отрицательный ID: ID пользователей Telegram положительны.
+func anonymousUserID() int64 {
+	return -(rand.Int64N(math.MaxInt64) + 1)
+}
+
+func (r *Repository) selectParticipantThreads(
+	ctx context.Context,
+	qc sqlx.QueryerContext,
+	prt participantRow,
+) ([]*bots.Thread, error) {
+	rows, err := r.selectParticipantThreadRows(ctx, qc, prt.BotID, prt.ChatID, prt.UserID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*bots.Thread, len(rows))
+	for i, row := range rows {
+		answers, err2 := r.selectAnswers(ctx, qc, bots.ThreadID(row.ID))
+		if err2 != nil {
+			return nil, err2
+		}
+		res[i], err2 = bots.UnmarshallThread(row.ID, row.Key, row.State, answers, row.Vars, row.StartedAt)
+		if err2 != nil {
+			return nil, err2
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,219 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
+)
+
+func TestPostgresParticipantDataRepository_Export(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	userID := bots.UserID(gofakeit.Int64())
+	saveParticipantAnswer(ctx, t, r, bots.NewParticipantID(userID, botID), "hello")
+
+	data, err := r.ParticipantData(ctx, userID, botID)
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	require.Equal(t, userID, data[0].Participant.ID().UserID())
+	require.Len(t, data[0].Threads, 1)
+	require.Equal(t, bots.MustNewMessage("hello"), data[0].Threads[0].Answers()[bots.MustNewState(1)])
+
+	// Пустой ID бота означает все боты пользователя
+	all, err := r.ParticipantData(ctx, userID, "")
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	other, err := r.ParticipantData(ctx, bots.UserID(gofakeit.Int64()), botID)
+	require.NoError(t, err)
+	require.Empty(t, other)
+}
+
+func TestPostgresParticipantDataRepository_Erase(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	userID := bots.UserID(gofakeit.Int64())
+	saveParticipantAnswer(ctx, t, r, bots.NewParticipantID(userID, botID), "hello")
+
+	n, err := r.EraseParticipantData(ctx, userID, botID)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	data, err := r.ParticipantData(ctx, userID, botID)
+	require.NoError(t, err)
+	require.Empty(t, data)
+	threads, err := r.FilteredBotThreads(ctx, botID, port.ThreadFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, threads)
+
+	n, err = r.EraseParticipantData(ctx, userID, botID)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestPostgresParticipantDataRepository_Anonymize(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	userID := bots.UserID(gofakeit.Int64())
+	saveParticipantAnswer(ctx, t, r, bots.NewParticipantID(userID, botID), "hello")
+
+	n, err := r.AnonymizeParticipantData(ctx, userID, botID)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	data, err := r.ParticipantData(ctx, userID, botID)
+	require.NoError(t, err)
+	require.Empty(t, data)
+
+	// Треды остаются, но принадлежат анонимному участнику, а тексты ответов заменены
+	threads, err := r.FilteredBotThreads(ctx, botID, port.ThreadFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	require.Negative(t, int64(threads[0].UserID()))
+	require.Equal(t, bots.MustNewMessage("[anonymized]"), threads[0].Thread().Answers()[bots.MustNewState(1)])
+}
+
+func TestPostgresParticipantDataRepository_Records(t *testing.T) {
+	for _, anonymize := range []bool{false, true} {
+		t.Run(fmt.Sprintf("anonymize=%t", anonymize), func(t *testing.T) {
+			db := tests.ConnectPostgresDB()
+			t.Cleanup(func() { _ = db.Close() })
+			l := logs.DefaultLogger()
+			r := postgres.NewRepository(db, nil, events.NewOutbox(l), l)
+
+			ctx := context.Background()
+			botID := upsertLeaseTestBot(ctx, t, r)
+			prtID := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), botID)
+			seedParticipantRecords(ctx, t, db, r, prtID)
+
+			data, err := r.ParticipantData(ctx, prtID.UserID(), botID)
+			require.NoError(t, err)
+			require.Len(t, data, 1)
+			sources := make(map[string]bool)
+			for _, record := range data[0].Records {
+				sources[record.Source] = true
+			}
+			require.Equal(t, map[string]bool{
+				"webhook_deliveries":                                 true,
+				"watermill_" + events.OutboxTopic:                    true,
+				"watermill_" + events.Topic(bots.EventThreadStarted): true,
+				"audit_log": true,
+			}, sources)
+
+			if anonymize {
+				_, err = r.AnonymizeParticipantData(ctx, prtID.UserID(), botID)
+			} else {
+				_, err = r.EraseParticipantData(ctx, prtID.UserID(), botID)
+			}
+			require.NoError(t, err)
+			require.Zero(t, countUserRecords(ctx, t, db, prtID.UserID()))
+		})
+	}
+}
+
+// seedParticipantRecords сохраняет ответ участника prtID, публикуя события в таблицу outbox, а также
+// событие в таблице топика транспорта postgres, тело Webhook и запись журнала аудита с его ID.
+func seedParticipantRecords(
+	ctx context.Context, t *testing.T, db *sqlx.DB, r *postgres.Repository, prtID bots.ParticipantID,
+) {
+	t.Helper()
+	l := logs.DefaultLogger()
+
+	// Форвардер создаёт таблицу outbox, в которую пишет транзакция участника
+	_, err := events.NewForwarder(db.DB, l)
+	require.NoError(t, err)
+	err = r.UpdateOrCreateParticipantWithOutbox(ctx, prtID, func(
+		_ context.Context, prt *bots.Participant,
+	) ([]bots.BotMessage, error) {
+		thread, err2 := prt.StartThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		if err2 != nil {
+			return nil, err2
+		}
+		thread.SaveAnswer(bots.MustNewMessage("hello"))
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	pub, err := events.NewPostgresPublisher(db.DB, l)
+	require.NoError(t, err)
+	err = events.NewPublisher(l, pub).Publish(ctx, bots.ThreadStarted{
+		ParticipantID: prtID, ThreadID: bots.ThreadID(gofakeit.UUID()), Key: "start", StartedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	w := bots.MustNewWebhook(
+		bots.WebhookID(gofakeit.UUID()), prtID.BotID(), "https://example.com/hook", "0123456789abcdef",
+		[]string{bots.EventThreadStarted},
+	)
+	require.NoError(t, r.UpsertWebhook(ctx, w))
+	body := fmt.Sprintf(`{"event":"thread_started","botId":%q,"data":{"chatId":%d,"userId":%d}}`,
+		prtID.BotID(), prtID.ChatID(), prtID.UserID())
+	require.NoError(t, r.EnqueueWebhookDeliveries(
+		ctx, []*bots.Webhook{w}, gofakeit.UUID(), bots.EventThreadStarted, []byte(body),
+	))
+
+	require.NoError(t, r.RecordAudit(ctx, decorator.AuditEntry{
+		Actor:   "author",
+		Action:  "EraseParticipantDataCommand",
+		BotID:   string(prtID.BotID()),
+		Payload: []byte(fmt.Sprintf(`{"BotID":%q,"UserID":%d}`, prtID.BotID(), prtID.UserID())),
+		At:      time.Now(),
+	}))
+}
+
+// countUserRecords возвращает количество строк таблиц событий, очереди Webhook и журнала аудита,
+// содержащих ID пользователя userID.
+func countUserRecords(ctx context.Context, t *testing.T, db *sqlx.DB, userID bots.UserID) int {
+	t.Helper()
+	id := fmt.Sprint(int64(userID))
+	total := 0
+	for _, q := range []string{
+		`SELECT count(*) FROM webhook_deliveries WHERE payload::text LIKE '%' || $1 || '%'`,
+		`SELECT count(*) FROM audit_log WHERE payload::text LIKE '%' || $1 || '%'`,
+		`SELECT count(*) FROM "watermill_bots.thread_started" WHERE payload::text LIKE '%' || $1 || '%'`,
+		`SELECT count(*) FROM "watermill_bots_outbox"
+			WHERE convert_from(decode(payload->>'payload', 'base64'), 'UTF8') LIKE '%' || $1 || '%'`,
+	} {
+		var n int
+		require.NoError(t, db.GetContext(ctx, &n, q, id))
+		total += n
+	}
+	return total
+}
+
+func saveParticipantAnswer(
+	ctx context.Context, t *testing.T, r *postgres.Repository, id bots.ParticipantID, text string,
+) {
+	t.Helper()
+	err := r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		thread, err := prt.StartThread(bots.MustNewEntry("start", bots.MustNewState(1)))
+		if err != nil {
+			return err
+		}
+		thread.SaveAnswer(bots.MustNewMessage(text))
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
@@ -27,7 +29,7 @@ func setupRepositoryWithParticipantFixtures() (*postgres.Repository, func()) {
 	db.MustExecContext(
 		context.Background(),
 		`INSERT INTO bots (id, token, author) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
-		testBotID, "token", 1,
+		testBotID, "token", "author",
 	)
 
 	db.MustExecContext(
@@ -42,7 +44,7 @@ func setupRepositoryWithParticipantFixtures() (*postgres.Repository, func()) {
 		testBotID, testEntryKey, testEntryKeyAlt, testStartState, testStartState,
 	)
 
-	return postgres.NewRepository(db, slog.Default()), func() {
+	return postgres.NewRepository(db, nil, nil, slog.Default()), func() {
 		_ = db.Close()
 	}
 }
@@ -145,3 +147,153 @@ func TestPostgresParticipantRepository_CreateNewThread(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestPostgresParticipantRepository_Block(t *testing.T) {
+	r, closeFn := setupRepositoryWithParticipantFixtures()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), testBotID)
+
+	before, err := r.ParticipantStats(ctx, testBotID)
+	require.NoError(t, err)
+
+	err = r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		prt.Block()
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		require.True(t, prt.IsBlocked())
+		return nil
+	})
+	require.NoError(t, err)
+
+	after, err := r.ParticipantStats(ctx, testBotID)
+	require.NoError(t, err)
+	require.Equal(t, before.Total()+1, after.Total())
+	require.Equal(t, before.Blocked()+1, after.Blocked())
+
+	err = r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		prt.Unblock()
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		require.False(t, prt.IsBlocked())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostgresParticipantRepository_Profile(t *testing.T) {
+	r, closeFn := setupRepositoryWithParticipantFixtures()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	userID := bots.UserID(gofakeit.Int64())
+	id := bots.NewParticipantID(userID, testBotID)
+	profile := bots.NewProfile("durov", "Pavel", "Durov", "en")
+
+	err := r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		prt.UpdateProfile(profile)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		require.Equal(t, profile, prt.Profile())
+		return nil
+	})
+	require.NoError(t, err)
+
+	profiles, err := r.ParticipantProfiles(ctx, testBotID)
+	require.NoError(t, err)
+	require.Equal(t, profile, profiles[userID])
+}
+
+func TestPostgresParticipantRepository_LastUpdateID(t *testing.T) {
+	r, closeFn := setupRepositoryWithParticipantFixtures()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	id := bots.NewParticipantID(bots.UserID(gofakeit.Int64()), testBotID)
+
+	err := r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		return prt.ApplyUpdate(42)
+	})
+	require.NoError(t, err)
+
+	err = r.UpdateOrCreateParticipant(ctx, id, func(_ context.Context, prt *bots.Participant) error {
+		require.Equal(t, bots.UpdateID(42), prt.LastUpdateID())
+		require.ErrorIs(t, prt.ApplyUpdate(42), bots.ErrUpdateAlreadyApplied)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostgresUpdateOffsetRepository(t *testing.T) {
+	r, closeFn := setupRepositoryWithParticipantFixtures()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+
+	err := r.SaveUpdateOffset(ctx, testBotID, 100)
+	require.NoError(t, err)
+
+	// Меньший номер не должен откатывать сохранённый
+	err = r.SaveUpdateOffset(ctx, testBotID, 50)
+	require.NoError(t, err)
+
+	offset, err := r.UpdateOffset(ctx, testBotID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, offset, bots.UpdateID(100))
+}
+
+func TestPostgresParticipantRepository_FilteredBotThreads(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	botID := upsertLeaseTestBot(ctx, t, r)
+	for _, text := range []string{"Alpha", "beta", "100% gamma"} {
+		saveParticipantAnswer(ctx, t, r, bots.NewParticipantID(bots.UserID(gofakeit.Int64()), botID), text)
+	}
+
+	all, err := r.FilteredBotThreads(ctx, botID, port.ThreadFilter{Ascending: true, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, bots.MustNewMessage("Alpha"), all[0].Thread().Answers()[bots.MustNewState(1)])
+
+	// Постраничная выборка от поздних к ранним без пропусков и повторов
+	page1, err := r.FilteredBotThreads(ctx, botID, port.ThreadFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	last := page1[1].Thread()
+	page2, err := r.FilteredBotThreads(ctx, botID, port.ThreadFilter{
+		After: &port.ThreadCursor{StartedAt: last.StartedAt(), ThreadID: last.ID()},
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Equal(t, all[0].Thread().ID(), page2[0].Thread().ID())
+
+	count := func(filter port.ThreadFilter) int {
+		filter.Limit = 10
+		threads, err2 := r.FilteredBotThreads(ctx, botID, filter)
+		require.NoError(t, err2)
+		return len(threads)
+	}
+	completed, notCompleted := true, false
+	final := []bots.State{bots.MustNewState(1)}
+	require.Equal(t, 1, count(port.ThreadFilter{Contains: "ALP"}))
+	require.Equal(t, 1, count(port.ThreadFilter{Contains: "%"}))
+	require.Equal(t, 3, count(port.ThreadFilter{EntryKey: "start"}))
+	require.Zero(t, count(port.ThreadFilter{EntryKey: "other"}))
+	require.Zero(t, count(port.ThreadFilter{StartedAfter: time.Now().Add(time.Hour)}))
+	require.Equal(t, 3, count(port.ThreadFilter{StartedBefore: time.Now().Add(time.Hour)}))
+	require.Equal(t, 3, count(port.ThreadFilter{Completed: &completed, FinalStates: final}))
+	require.Zero(t, count(port.ThreadFilter{Completed: &notCompleted, FinalStates: final}))
+}
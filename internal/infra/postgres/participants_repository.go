@@ -10,6 +10,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/zhikh23/pgutils"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/diffcalc"
 )
@@ -52,16 +53,126 @@ func (r *Repository) UpdateOrCreateParticipant(
 	})
 }
 
-func (r *Repository) BotThreads(ctx context.Context, botID bots.BotID) ([]bots.BotThread, error) {
+func (r *Repository) StreamBotThreads(
+	ctx context.Context, botID bots.BotID, batchSize int, fn func([]bots.BotThread) error,
+) error {
+	// Курсор существует только внутри транзакции
+	return pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if err := r.declareBotThreadsCursor(ctx, tx, string(botID)); err != nil {
+			return err
+		}
+		for {
+			rows, err := r.fetchBotThreadsCursor(ctx, tx, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			threads, err := r.botThreadsFromRows(ctx, tx, botID, rows)
+			if err != nil {
+				return err
+			}
+			if err = fn(threads); err != nil {
+				return err
+			}
+			if len(rows) < batchSize {
+				return nil
+			}
+		}
+	})
+}
+
+func (r *Repository) AnsweredStates(ctx context.Context, botID bots.BotID) ([]bots.State, error) {
+	states, err := r.selectBotAnsweredStates(ctx, r.db, string(botID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]bots.State, len(states))
+	for i, state := range states {
+		res[i], err = bots.NewState(state)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *Repository) FilteredBotThreads(
+	ctx context.Context, botID bots.BotID, filter port.ThreadFilter,
+) ([]bots.BotThread, error) {
+	params := threadFilterParams{
+		EntryKey:  string(filter.EntryKey),
+		Completed: filter.Completed,
+		Contains:  filter.Contains,
+		Ascending: filter.Ascending,
+		Limit:     filter.Limit,
+	}
+	if !filter.StartedAfter.IsZero() {
+		params.StartedAfter = &filter.StartedAfter
+	}
+	if !filter.StartedBefore.IsZero() {
+		params.StartedBefore = &filter.StartedBefore
+	}
+	params.FinalStates = make([]int64, len(filter.FinalStates))
+	for i, state := range filter.FinalStates {
+		params.FinalStates[i] = int64(state.Int())
+	}
+	if filter.After != nil {
+		params.AfterStartedAt = &filter.After.StartedAt
+		params.AfterID = string(filter.After.ThreadID)
+	}
+
 	var res []bots.BotThread
 	err := pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
-		var err error
-		res, err = r.selectBotThreads(ctx, tx, botID)
+		rows, err := r.selectFilteredBotThreadRows(ctx, tx, string(botID), params)
+		if err != nil {
+			return err
+		}
+		res, err = r.botThreadsFromRows(ctx, tx, botID, rows)
 		return err
 	})
 	return res, err
 }
 
+func (r *Repository) ParticipantStats(ctx context.Context, botID bots.BotID) (bots.ParticipantStats, error) {
+	row, err := r.getParticipantStatsRow(ctx, r.db, string(botID))
+	if err != nil {
+		return bots.ParticipantStats{}, err
+	}
+	return bots.NewParticipantStats(row.Total, row.Blocked)
+}
+
+func (r *Repository) ParticipantProfiles(ctx context.Context, botID bots.BotID) (map[bots.UserID]bots.Profile, error) {
+	rows, err := r.selectBotProfileRows(ctx, r.db, string(botID))
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[bots.UserID]bots.Profile, len(rows))
+	for _, row := range rows {
+		res[bots.UserID(row.UserID)] = profileFromRow(row)
+	}
+	return res, nil
+}
+
+func (r *Repository) ParticipantProfilesByUserIDs(
+	ctx context.Context, botID bots.BotID, userIDs []bots.UserID,
+) (map[bots.UserID]bots.Profile, error) {
+	ids := make([]int64, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = int64(id)
+	}
+	rows, err := r.selectUsersProfileRows(ctx, r.db, string(botID), ids)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[bots.UserID]bots.Profile, len(rows))
+	for _, row := range rows {
+		res[bots.UserID(row.UserID)] = profileFromRow(row)
+	}
+	return res, nil
+}
+
 //
 //
 // ОПЕРАЦИИ НАД СУЩНОСТЯМИ ВНУТРИ АГГРЕГАТА
@@ -74,9 +185,10 @@ func (r *Repository) findParticipant(
 	id bots.ParticipantID,
 ) (*bots.Participant, bool, error) {
 	botID := string(id.BotID())
+	chatID := int64(id.ChatID())
 	userID := int64(id.UserID())
 
-	row, err := r.getParticipantRow(ctx, qc, botID, userID)
+	row, err := r.getParticipantRow(ctx, qc, botID, chatID, userID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, false, nil
 	} else if err != nil {
@@ -92,7 +204,17 @@ func (r *Repository) findParticipant(
 		}
 	}
 
-	prt, err := bots.UnmarshallParticipant(row.BotID, row.UserID, thread)
+	var profile bots.Profile
+	pRow, err := r.getProfileRow(ctx, qc, botID, chatID, userID)
+	if err == nil {
+		profile = profileFromRow(pRow)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, true, err
+	}
+
+	prt, err := bots.UnmarshallParticipant(
+		row.BotID, row.ChatID, row.UserID, thread, row.BlockedAt, profile, row.LastUpdateID,
+	)
 	if err != nil {
 		return nil, false, err
 	}
@@ -100,22 +222,44 @@ func (r *Repository) findParticipant(
 	return prt, true, nil
 }
 
-func (r *Repository) selectBotThreads(
+// botThreadsFromRows восстанавливает треды, выбирая ответы всех тредов одним запросом.
+func (r *Repository) botThreadsFromRows(
 	ctx context.Context,
 	qc sqlx.QueryerContext,
 	botID bots.BotID,
+	rows []threadRow,
 ) ([]bots.BotThread, error) {
-	rows, err := r.selectBotThreadsRows(ctx, qc, string(botID))
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	answerRows, err := r.selectThreadsAnswerRows(ctx, qc, ids)
 	if err != nil {
 		return nil, err
 	}
-	res := make([]bots.BotThread, len(rows))
-	for i, row := range rows {
-		answers, err2 := r.selectAnswers(ctx, qc, bots.ThreadID(row.ID))
+	answers := make(map[string]map[bots.State]bots.Message, len(rows))
+	for _, row := range answerRows {
+		msg, err2 := bots.NewMessage(row.Text)
+		if err2 != nil {
+			return nil, err2
+		}
+		state, err2 := bots.NewState(row.State)
 		if err2 != nil {
 			return nil, err2
 		}
-		thread, err2 := bots.UnmarshallThread(row.ID, row.Key, row.State, answers, row.StartedAt)
+		if answers[row.ThreadID] == nil {
+			answers[row.ThreadID] = make(map[bots.State]bots.Message)
+		}
+		answers[row.ThreadID][state] = msg
+	}
+
+	res := make([]bots.BotThread, len(rows))
+	for i, row := range rows {
+		threadAnswers := answers[row.ID]
+		if threadAnswers == nil {
+			threadAnswers = make(map[bots.State]bots.Message)
+		}
+		thread, err2 := bots.UnmarshallThread(row.ID, row.Key, row.State, threadAnswers, row.Vars, row.StartedAt)
 		if err2 != nil {
 			return nil, err2
 		}
@@ -137,7 +281,7 @@ func (r *Repository) getThread(
 	if err != nil {
 		return nil, err
 	}
-	return bots.UnmarshallThread(row.ID, row.Key, row.State, answers, row.StartedAt)
+	return bots.UnmarshallThread(row.ID, row.Key, row.State, answers, row.Vars, row.StartedAt)
 }
 
 func (r *Repository) selectAnswers(
@@ -169,17 +313,21 @@ func (r *Repository) upsertParticipant(
 	ec sqlx.ExtContext,
 	prt *bots.Participant,
 ) error {
-	botID := prt.ID().BotID()
-	userID := prt.ID().UserID()
 	prtRow := participantToRow(prt)
 
 	if err := r.upsertParticipantRow(ctx, ec, prtRow); err != nil {
 		return err
 	}
 
+	if profile := prt.Profile(); !profile.IsZero() {
+		if err := r.upsertProfileRow(ctx, ec, profileToRow(prt.ID(), profile)); err != nil {
+			return err
+		}
+	}
+
 	thread := prt.ActiveThread()
 	if thread != nil {
-		thrRow := threadToRow(botID, userID, thread)
+		thrRow := threadToRow(prt.ID(), thread)
 		if err := r.upsertThreadRow(ctx, ec, thrRow); err != nil {
 			return err
 		}
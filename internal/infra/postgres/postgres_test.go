@@ -1,14 +1,27 @@
 package postgres_test
 
 import (
+	"bytes"
+
 	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
 	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
 )
 
 func setupRepository() (*postgres.Repository, func()) {
+	keys, err := envelope.NewKeyring("test", map[string][]byte{
+		"test": bytes.Repeat([]byte{1}, envelope.KeySize),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return setupRepositoryWithKeys(keys)
+}
+
+func setupRepositoryWithKeys(keys *envelope.Keyring) (*postgres.Repository, func()) {
 	db := tests.ConnectPostgresDB()
-	return postgres.NewRepository(db, logs.DefaultLogger()), func() {
+	return postgres.NewRepository(db, keys, nil, logs.DefaultLogger()), func() {
 		_ = db.Close()
 	}
 }
@@ -1,19 +1,35 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"log/slog"
 
 	"github.com/jmoiron/sqlx"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
 )
 
+// EventOutbox сохраняет доменные события в транзакции tx, в которой сохраняются породившие их изменения.
+type EventOutbox interface {
+	PublishTx(ctx context.Context, tx *sql.Tx, events ...bots.Event) error
+}
+
 type Repository struct {
 	db *sqlx.DB
-	l  *slog.Logger
+	// keys шифрует токены ботов и секреты Webhook; если nil, они хранятся открыто.
+	keys *envelope.Keyring
+	// events сохраняет события участников вместе с участником; если nil, события не сохраняются.
+	events EventOutbox
+	l      *slog.Logger
 }
 
-func NewRepository(db *sqlx.DB, l *slog.Logger) *Repository {
+func NewRepository(db *sqlx.DB, keys *envelope.Keyring, events EventOutbox, l *slog.Logger) *Repository {
 	return &Repository{
-		db: db,
-		l:  l,
+		db:     db,
+		keys:   keys,
+		events: events,
+		l:      l,
 	}
 }
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+func (r *Repository) UpdateOffset(ctx context.Context, id bots.BotID) (bots.UpdateID, error) {
+	offset, err := r.getUpdateOffset(ctx, r.db, string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return bots.UpdateID(offset), nil
+}
+
+func (r *Repository) SaveUpdateOffset(ctx context.Context, id bots.BotID, offset bots.UpdateID) error {
+	return r.upsertUpdateOffset(ctx, r.db, string(id), int64(offset))
+}
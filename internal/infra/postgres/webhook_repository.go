@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zhikh23/pgutils"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
+)
+
+func (r *Repository) UpsertWebhook(ctx context.Context, w *bots.Webhook) error {
+	row, err := r.sealWebhookSecret(webhookToRow(w))
+	if err != nil {
+		return err
+	}
+	return r.upsertWebhookRow(ctx, r.db, row)
+}
+
+func (r *Repository) Webhook(ctx context.Context, botID bots.BotID, id bots.WebhookID) (*bots.Webhook, error) {
+	row, err := r.getWebhookRow(ctx, r.db, string(botID), string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", port.ErrWebhookNotFound, string(id))
+	} else if err != nil {
+		return nil, err
+	}
+	return r.webhookFromRow(row)
+}
+
+func (r *Repository) BotWebhooks(ctx context.Context, botID bots.BotID) ([]*bots.Webhook, error) {
+	rows, err := r.selectBotWebhookRows(ctx, r.db, string(botID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*bots.Webhook, len(rows))
+	for i, row := range rows {
+		res[i], err = r.webhookFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, botID bots.BotID, id bots.WebhookID) error {
+	err := r.deleteWebhookRow(ctx, r.db, string(botID), string(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", port.ErrWebhookNotFound, string(id))
+	}
+	return err
+}
+
+func (r *Repository) EnqueueWebhookDeliveries(
+	ctx context.Context, ws []*bots.Webhook, eventUUID string, event string, payload []byte,
+) error {
+	return pgutils.RunTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		for _, w := range ws {
+			err := r.insertWebhookDeliveryRow(ctx, tx, string(w.BotID()), string(w.ID()), eventUUID, event, payload)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) ClaimWebhookDeliveries(
+	ctx context.Context, limit int, lockFor time.Duration,
+) ([]port.WebhookDelivery, error) {
+	rows, err := r.claimWebhookDeliveryRows(ctx, r.db, limit, lockFor)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]port.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		res[i] = webhookDeliveryFromRow(row)
+	}
+	return res, nil
+}
+
+func (r *Repository) MarkWebhookDelivered(ctx context.Context, id int64, responseCode int) error {
+	return r.updateWebhookDeliveryRow(
+		ctx, r.db, id, string(port.DeliveryDelivered), responseCode, "", time.Now(),
+	)
+}
+
+func (r *Repository) RetryWebhookDelivery(
+	ctx context.Context, id int64, at time.Time, responseCode int, reason string,
+) error {
+	return r.updateWebhookDeliveryRow(
+		ctx, r.db, id, string(port.DeliveryPending), responseCode, reason, at,
+	)
+}
+
+func (r *Repository) FailWebhookDelivery(ctx context.Context, id int64, responseCode int, reason string) error {
+	return r.updateWebhookDeliveryRow(
+		ctx, r.db, id, string(port.DeliveryFailed), responseCode, reason, time.Now(),
+	)
+}
+
+func (r *Repository) WebhookDeliveries(
+	ctx context.Context, botID bots.BotID, id bots.WebhookID, limit int,
+) ([]port.WebhookDelivery, error) {
+	rows, err := r.selectWebhookDeliveryRows(ctx, r.db, string(botID), string(id), limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]port.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		res[i] = webhookDeliveryFromRow(row)
+	}
+	return res, nil
+}
+
+// ReencryptWebhookSecrets шифрует активным ключом секреты всех Webhook, которые хранятся открыто или
+// зашифрованы другим ключом, и возвращает количество перешифрованных секретов.
+func (r *Repository) ReencryptWebhookSecrets(ctx context.Context) (int, error) {
+	const op = "PostgresRepository.ReencryptWebhookSecrets"
+	l := r.l.With(slog.String("op", op))
+
+	if r.keys == nil {
+		return 0, errors.New("encryption keys are not configured")
+	}
+
+	rows, err := r.selectWebhookRowsToReencrypt(ctx, r.db, r.keys.ActiveKeyID())
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, row := range rows {
+		secret, err2 := r.openWebhookSecret(row)
+		if err2 != nil {
+			return n, err2
+		}
+		prevKeyID := row.SecretKeyID
+		row.Secret = secret
+		row, err2 = r.sealWebhookSecret(row)
+		if err2 != nil {
+			return n, err2
+		}
+		err2 = r.updateWebhookSecretRow(ctx, r.db, row, prevKeyID)
+		if errors.Is(err2, sql.ErrNoRows) {
+			// Webhook удалён или сохранён заново после выборки: его секрет уже зашифрован активным ключом
+			l.InfoContext(ctx, "webhook secret changed concurrently, skipping",
+				slog.String("bot_id", row.BotID), slog.String("webhook_id", row.ID))
+			continue
+		} else if err2 != nil {
+			return n, err2
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (r *Repository) webhookFromRow(row webhookRow) (*bots.Webhook, error) {
+	secret, err := r.openWebhookSecret(row)
+	if err != nil {
+		return nil, err
+	}
+	row.Secret = secret
+	return webhookFromRow(row)
+}
+
+// sealWebhookSecret шифрует открытый секрет строки, если настроены ключи шифрования.
+func (r *Repository) sealWebhookSecret(row webhookRow) (webhookRow, error) {
+	if r.keys == nil {
+		return row, nil
+	}
+	sealed, err := r.keys.Seal([]byte(row.Secret), webhookSecretAAD(row))
+	if err != nil {
+		return row, fmt.Errorf("encrypting secret of webhook %s: %w", row.ID, err)
+	}
+	row.Secret = ""
+	row.SecretKeyID = &sealed.KeyID
+	row.SecretDataKey = sealed.DataKey
+	row.SecretCiphertext = sealed.Ciphertext
+	return row, nil
+}
+
+// openWebhookSecret возвращает секрет Webhook, расшифровывая его, если он хранится зашифрованным.
+func (r *Repository) openWebhookSecret(row webhookRow) (string, error) {
+	if row.SecretKeyID == nil {
+		return row.Secret, nil
+	}
+	if r.keys == nil {
+		return "", fmt.Errorf("secret of webhook %s is encrypted, but encryption keys are not configured", row.ID)
+	}
+	secret, err := r.keys.Open(envelope.Sealed{
+		KeyID:      *row.SecretKeyID,
+		DataKey:    row.SecretDataKey,
+		Ciphertext: row.SecretCiphertext,
+	}, webhookSecretAAD(row))
+	if err != nil {
+		return "", fmt.Errorf("decrypting secret of webhook %s: %w", row.ID, err)
+	}
+	return string(secret), nil
+}
+
+// webhookSecretAAD привязывает шифротекст секрета к Webhook: ID уникален только в пределах бота.
+func webhookSecretAAD(row webhookRow) []byte {
+	return []byte(row.BotID + "/" + row.ID)
+}
@@ -0,0 +1,140 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/postgres"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/tests"
+)
+
+func upsertWebhookTestWebhook(ctx context.Context, t *testing.T, r *postgres.Repository) *bots.Webhook {
+	botID := upsertLeaseTestBot(ctx, t, r)
+	w := bots.MustNewWebhook(
+		bots.WebhookID(gofakeit.UUID()), botID, "https://example.com/hook", "0123456789abcdef",
+		[]string{bots.EventThreadCompleted},
+	)
+	require.NoError(t, r.UpsertWebhook(ctx, w))
+	return w
+}
+
+func claimWebhookDeliveries(
+	ctx context.Context, t *testing.T, r *postgres.Repository, w *bots.Webhook,
+) []port.WebhookDelivery {
+	ds, err := r.ClaimWebhookDeliveries(ctx, 1000, time.Minute)
+	require.NoError(t, err)
+	var res []port.WebhookDelivery
+	for _, d := range ds {
+		if d.BotID == w.BotID() && d.WebhookID == w.ID() {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func TestPostgresWebhookRepository_CRUD(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	w := upsertWebhookTestWebhook(ctx, t, r)
+
+	got, err := r.Webhook(ctx, w.BotID(), w.ID())
+	require.NoError(t, err)
+	require.Equal(t, w, got)
+
+	require.NoError(t, w.Update("https://example.com/other", "fedcba9876543210", []string{bots.EventBotEnabled}))
+	require.NoError(t, r.UpsertWebhook(ctx, w))
+
+	ws, err := r.BotWebhooks(ctx, w.BotID())
+	require.NoError(t, err)
+	require.Equal(t, []*bots.Webhook{w}, ws)
+
+	require.NoError(t, r.DeleteWebhook(ctx, w.BotID(), w.ID()))
+	_, err = r.Webhook(ctx, w.BotID(), w.ID())
+	require.True(t, errors.Is(err, port.ErrWebhookNotFound))
+	require.True(t, errors.Is(r.DeleteWebhook(ctx, w.BotID(), w.ID()), port.ErrWebhookNotFound))
+}
+
+func TestPostgresWebhookRepository_Deliveries(t *testing.T) {
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+	w := upsertWebhookTestWebhook(ctx, t, r)
+	payload := []byte(`{"event": "thread_completed"}`)
+	eventUUID := gofakeit.UUID()
+	enqueue := func() error {
+		return r.EnqueueWebhookDeliveries(ctx, []*bots.Webhook{w}, eventUUID, bots.EventThreadCompleted, payload)
+	}
+	require.NoError(t, enqueue())
+	// Повторно доставленное событие не создаёт второй доставки
+	require.NoError(t, enqueue())
+
+	claimed := claimWebhookDeliveries(ctx, t, r, w)
+	require.Len(t, claimed, 1)
+	require.Equal(t, port.DeliveryPending, claimed[0].Status)
+	require.JSONEq(t, string(payload), string(claimed[0].Payload))
+
+	// Захваченная доставка не захватывается повторно
+	require.Empty(t, claimWebhookDeliveries(ctx, t, r, w))
+
+	err := r.RetryWebhookDelivery(ctx, claimed[0].ID, time.Now().Add(-time.Second), 500, "internal error")
+	require.NoError(t, err)
+	claimed = claimWebhookDeliveries(ctx, t, r, w)
+	require.Len(t, claimed, 1)
+	require.Equal(t, 1, claimed[0].Attempts)
+
+	require.NoError(t, r.MarkWebhookDelivered(ctx, claimed[0].ID, 200))
+	ds, err := r.WebhookDeliveries(ctx, w.BotID(), w.ID(), 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, port.DeliveryDelivered, ds[0].Status)
+	require.Equal(t, 2, ds[0].Attempts)
+	require.Equal(t, 200, ds[0].ResponseCode)
+	require.False(t, ds[0].DeliveredAt.IsZero())
+	require.Empty(t, claimWebhookDeliveries(ctx, t, r, w))
+}
+
+func TestPostgresWebhookRepository_ReencryptWebhookSecrets(t *testing.T) {
+	plain, closePlain := setupRepositoryWithKeys(nil)
+	t.Cleanup(closePlain)
+	r, closeFn := setupRepository()
+	t.Cleanup(closeFn)
+
+	ctx := context.Background()
+
+	// Открыто сохранённый секрет читается и после настройки шифрования
+	w := upsertWebhookTestWebhook(ctx, t, plain)
+	got, err := r.Webhook(ctx, w.BotID(), w.ID())
+	require.NoError(t, err)
+	require.Equal(t, w.Secret(), got.Secret())
+
+	n, err := r.ReencryptWebhookSecrets(ctx)
+	require.NoError(t, err)
+	require.Positive(t, n)
+
+	got, err = r.Webhook(ctx, w.BotID(), w.ID())
+	require.NoError(t, err)
+	require.Equal(t, w.Secret(), got.Secret())
+
+	// Открытый секрет в таблице не остаётся
+	db := tests.ConnectPostgresDB()
+	t.Cleanup(func() { _ = db.Close() })
+	var secret string
+	err = db.GetContext(ctx, &secret, `SELECT secret FROM webhooks WHERE bot_id = $1 AND id = $2`,
+		string(w.BotID()), string(w.ID()))
+	require.NoError(t, err)
+	require.Empty(t, secret)
+
+	// Без ключей зашифрованный секрет прочитать нельзя
+	_, err = plain.Webhook(ctx, w.BotID(), w.ID())
+	require.Error(t, err)
+}
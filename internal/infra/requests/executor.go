@@ -0,0 +1,92 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/safehttp"
+)
+
+const (
+	// executeTimeout ограничивает время выполнения запроса. Запрос выполняется во время обработки
+	// сообщения участника, поэтому медленный внешний сервис задерживает ответ бота.
+	executeTimeout = 10 * time.Second
+	// maxResponseBody ограничивает объём читаемого тела ответа.
+	maxResponseBody = 1 << 20
+)
+
+// Executor выполняет запросы узлов-запросов по HTTP.
+type Executor struct {
+	client *http.Client
+	l      *slog.Logger
+}
+
+// NewExecutor возвращает Executor, который не выполняет запросы во внутренние сети (см. safehttp):
+// адрес запроса задаёт автор сценария.
+func NewExecutor(l *slog.Logger) *Executor {
+	return NewExecutorWithClient(safehttp.NewClient(executeTimeout), l)
+}
+
+// NewExecutorWithClient возвращает Executor, выполняющий запросы клиентом client.
+func NewExecutorWithClient(client *http.Client, l *slog.Logger) *Executor {
+	return &Executor{
+		client: client,
+		l:      l,
+	}
+}
+
+func (e *Executor) Execute(ctx context.Context, req bots.RenderedRequest) (bots.RequestResult, error) {
+	const op = "requests.Executor.Execute"
+
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	hreq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return bots.RequestResult{}, fmt.Errorf("building request: %w", err)
+	}
+	// Путь и параметры URL могут содержать ответы участника и ключи внешних систем, поэтому в журнал
+	// попадает только хост
+	l := e.l.With(
+		slog.String("op", op),
+		slog.String("method", req.Method),
+		slog.String("host", hreq.URL.Host),
+	)
+	if req.Body != "" {
+		hreq.Header.Set("Content-Type", "application/json")
+	}
+	hreq.Header.Set("User-Agent", "itsreg-bots")
+	for name, value := range req.Headers {
+		hreq.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(hreq)
+	if err != nil {
+		// *url.Error повторяет полный URL запроса
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = fmt.Errorf("%s %s: %w", req.Method, hreq.URL.Host, uerr.Err)
+		}
+		l.WarnContext(ctx, "failed to execute request", slog.String("error", err.Error()))
+		return bots.RequestResult{}, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		l.WarnContext(ctx, "failed to read response", slog.String("error", err.Error()))
+		return bots.RequestResult{}, fmt.Errorf("reading response: %w", err)
+	}
+
+	l.DebugContext(ctx, "request executed", slog.Int("status", resp.StatusCode))
+	return bots.RequestResult{StatusCode: resp.StatusCode, Body: b}, nil
+}
@@ -0,0 +1,73 @@
+package requests_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/requests"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/safehttp"
+)
+
+func TestExecutor_Execute(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "not found"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	executor := requests.NewExecutorWithClient(srv.Client(), logs.DefaultLogger())
+	res, err := executor.Execute(context.Background(), bots.RenderedRequest{
+		Method:  http.MethodPost,
+		URL:     srv.URL + "/students?id=42",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Body:    `{"id": "42"}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+	require.JSONEq(t, `{"error": "not found"}`, string(res.Body))
+
+	require.Equal(t, http.MethodPost, got.Method)
+	require.Equal(t, "/students", got.URL.Path)
+	require.Equal(t, "42", got.URL.Query().Get("id"))
+	require.Equal(t, "Bearer token", got.Header.Get("Authorization"))
+	require.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	require.JSONEq(t, `{"id": "42"}`, string(gotBody))
+}
+
+func TestExecutor_Execute_NoResponse(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	executor := requests.NewExecutorWithClient(srv.Client(), logs.DefaultLogger())
+	_, err := executor.Execute(context.Background(), bots.RenderedRequest{
+		Method: http.MethodGet,
+		URL:    srv.URL + "/students?key=secret",
+	})
+	require.Error(t, err)
+	// Параметры URL могут содержать ключи внешних систем
+	require.NotContains(t, err.Error(), "secret")
+}
+
+func TestExecutor_Execute_InternalAddress(t *testing.T) {
+	executor := requests.NewExecutor(logs.DefaultLogger())
+	for _, url := range []string{
+		"http://127.0.0.1:8080/", "http://169.254.169.254/latest/meta-data/", "http://10.0.0.1/",
+	} {
+		_, err := executor.Execute(context.Background(), bots.RenderedRequest{
+			Method: http.MethodGet,
+			URL:    url,
+		})
+		require.ErrorIs(t, err, safehttp.ErrForbiddenAddress, url)
+	}
+}
@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+// ClientCache хранит клиенты Telegram Bot API по токену бота.
+// tgbotapi.NewBotAPI выполняет запрос getMe при каждом создании клиента, поэтому
+// клиент создаётся единожды и переиспользуется MessageSender и InstanceManager.
+type ClientCache struct {
+	mu      sync.Mutex
+	clients map[bots.Token]*tgbotapi.BotAPI
+	mc      decorator.MetricsClient
+}
+
+func NewClientCache(mc decorator.MetricsClient) *ClientCache {
+	return &ClientCache{
+		clients: make(map[bots.Token]*tgbotapi.BotAPI),
+		mc:      mc,
+	}
+}
+
+// Client возвращает закэшированный клиент для токена или создаёт новый.
+func (c *ClientCache) Client(token bots.Token) (*tgbotapi.BotAPI, error) {
+	c.mu.Lock()
+	api, ok := c.clients[token]
+	c.mu.Unlock()
+	if ok {
+		c.mc.Inc("telegram.clients.hit", 1)
+		return api, nil
+	}
+	c.mc.Inc("telegram.clients.miss", 1)
+
+	// Запрос getMe выполняется без блокировки, чтобы не задерживать клиентов других ботов.
+	api, err := tgbotapi.NewBotAPI(string(token))
+	if err != nil {
+		return nil, sanitizeError(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, found := c.clients[token]; found {
+		// Клиент успел создать параллельный вызов, используем его.
+		return cached, nil
+	}
+	c.clients[token] = api
+	return api, nil
+}
+
+// Invalidate удаляет клиент из кэша. Вызывается при смене токена бота или его удалении.
+func (c *ClientCache) Invalidate(_ context.Context, token bots.Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, token)
+}
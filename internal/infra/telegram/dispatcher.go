@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// dispatcherQueueSize есть размер очереди обновлений одного обработчика.
+const dispatcherQueueSize = 64
+
+// dispatcher обрабатывает обновления в нескольких горутинах. Обновления одного чата попадают
+// в одну и ту же очередь, поэтому обрабатываются строго в порядке получения.
+type dispatcher struct {
+	mu     sync.RWMutex
+	closed bool
+	queues []chan tgbotapi.Update
+	wg     sync.WaitGroup
+}
+
+func newDispatcher(workers int, handle func(tgbotapi.Update)) *dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	d := &dispatcher{
+		queues: make([]chan tgbotapi.Update, workers),
+	}
+	for i := range d.queues {
+		q := make(chan tgbotapi.Update, dispatcherQueueSize)
+		d.queues[i] = q
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for upd := range q {
+				handle(upd)
+			}
+		}()
+	}
+	return d
+}
+
+// Dispatch ставит обновление в очередь его чата. Блокируется, если очередь заполнена.
+// Возвращает false, если dispatcher уже закрыт.
+func (d *dispatcher) Dispatch(upd tgbotapi.Update) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return false
+	}
+	d.queues[d.queueIndex(upd)] <- upd
+	return true
+}
+
+// Close перестаёт принимать обновления и дожидается обработки уже поставленных в очередь.
+func (d *dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, q := range d.queues {
+			close(q)
+		}
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *dispatcher) queueIndex(upd tgbotapi.Update) int {
+	var chatID int64
+	if upd.Message != nil && upd.Message.Chat != nil {
+		chatID = upd.Message.Chat.ID
+	}
+	n := int64(len(d.queues))
+	return int(((chatID % n) + n) % n)
+}
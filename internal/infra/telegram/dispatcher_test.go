@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUpdate(id int, chatID int64) tgbotapi.Update {
+	return tgbotapi.Update{
+		UpdateID: id,
+		Message:  &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}},
+	}
+}
+
+func TestDispatcher_PreservesOrderWithinChat(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[int64][]int)
+
+	d := newDispatcher(4, func(upd tgbotapi.Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		chatID := upd.Message.Chat.ID
+		got[chatID] = append(got[chatID], upd.UpdateID)
+	})
+
+	chats := []int64{1, 2, 3, -100500, 7}
+	for i := 0; i < 100; i++ {
+		for _, chatID := range chats {
+			require.True(t, d.Dispatch(newTestUpdate(i, chatID)))
+		}
+	}
+	d.Close()
+
+	for _, chatID := range chats {
+		require.Len(t, got[chatID], 100)
+		for i, id := range got[chatID] {
+			require.Equal(t, i, id)
+		}
+	}
+}
+
+func TestDispatcher_RejectsAfterClose(t *testing.T) {
+	d := newDispatcher(2, func(tgbotapi.Update) {})
+	d.Close()
+	require.False(t, d.Dispatch(newTestUpdate(1, 1)))
+}
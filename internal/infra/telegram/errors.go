@@ -0,0 +1,29 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// tokenInPath соответствует токену бота в пути запроса к Bot API: /bot<token>/<method>.
+var tokenInPath = regexp.MustCompile(`bot\d+:[A-Za-z0-9_-]+`)
+
+// sanitizeError убирает токен бота из ошибки tgbotapi. Ошибка транспорта (*url.Error) содержит URL
+// запроса вместе с токеном, поэтому от неё остаётся только причина. Ошибки Bot API (tgbotapi.Error)
+// токена не содержат и возвращаются как есть, чтобы их можно было различать по errors.As.
+// Ошибки, текст которых всё же содержит токен, заменяются текстом с замаскированным токеном.
+func sanitizeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = fmt.Errorf("%s telegram bot api: %w", urlErr.Op, urlErr.Err)
+	}
+	if msg := err.Error(); tokenInPath.MatchString(msg) {
+		return errors.New(tokenInPath.ReplaceAllString(msg, "bot***"))
+	}
+	return err
+}
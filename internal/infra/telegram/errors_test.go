@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+const secretToken = bots.Token("123:secret-token")
+
+// newUnreachableClientCache возвращает кэш с клиентом для secretToken, Bot API которого после
+// создания клиента становится недоступен.
+func newUnreachableClientCache(t *testing.T) *ClientCache {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"Test","username":"test_bot"}}`))
+	}))
+	target, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	api, err := tgbotapi.NewBotAPIWithClient(string(secretToken), &http.Client{Transport: redirectTransport{target}})
+	require.NoError(t, err)
+	srv.Close()
+
+	cc := NewClientCache(metrics.NoOp{})
+	cc.clients[secretToken] = api
+	return cc
+}
+
+func TestMessageSender_HidesTokenInErrors(t *testing.T) {
+	cc := newUnreachableClientCache(t)
+	s := NewMessageSender(cc, NewSentCounter(), logs.DefaultLogger())
+
+	// Текст ошибки отправки сохраняется в last_error строки outbox_messages
+	err := s.Send(context.Background(), secretToken, 1, bots.MustNewMessage("Hello").PromoteToBotMessage(nil))
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestBotInstance_HidesTokenInLastError(t *testing.T) {
+	cc := newUnreachableClientCache(t)
+	api, err := cc.Client(secretToken)
+	require.NoError(t, err)
+
+	i := startBotInstance(
+		"bot", secretToken, api, func() (*tgbotapi.BotAPI, error) { return cc.Client(secretToken) },
+		0, nopOffsets{}, 1, nil, nil, logs.DefaultLogger(),
+	)
+	t.Cleanup(i.Stop)
+
+	require.Eventually(t, func() bool {
+		return i.Stats().lastError != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotContains(t, i.Stats().lastError, "secret-token")
+}
+
+func TestSanitizeError(t *testing.T) {
+	err := sanitizeError(&url.Error{
+		Op:  "Post",
+		URL: "https://api.telegram.org/bot" + string(secretToken) + "/getUpdates",
+		Err: context.DeadlineExceeded,
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotContains(t, err.Error(), "secret-token")
+
+	tgErr := tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"}
+	require.Equal(t, tgErr, sanitizeError(tgErr))
+	require.NoError(t, sanitizeError(nil))
+}
@@ -2,9 +2,11 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 
@@ -12,126 +14,708 @@ import (
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
+// leaseTTL есть время, в течение которого аренда бота действительна без продления.
+const leaseTTL = 30 * time.Second
+
+// leaseRenewInterval есть период продления аренд и перехвата истёкших аренд других реплик.
+const leaseRenewInterval = 10 * time.Second
+
+// InstanceManager запускает экземпляры ботов, аренду которых удерживает реплика replica.
+// Аренды хранятся в port.LeaseRepository, поэтому каждый бот опрашивается ровно одной репликой.
 type InstanceManager struct {
 	m       sync.Map // map[string]*botInstance
+	cc      *ClientCache
+	sent    *SentCounter
+	leases  port.LeaseRepository
+	offsets port.UpdateOffsetRepository
+	bp      port.BotProvider
+	replica string
+	workers int
 	l       *slog.Logger
 	process port.ProcessHandler
 	entry   port.EntryHandler
 }
 
-func NewInstanceManager(log *slog.Logger, process port.ProcessHandler, entry port.EntryHandler) *InstanceManager {
+func NewInstanceManager(
+	cc *ClientCache,
+	sent *SentCounter,
+	leases port.LeaseRepository,
+	offsets port.UpdateOffsetRepository,
+	bp port.BotProvider,
+	replica string,
+	workers int,
+	log *slog.Logger,
+	process port.ProcessHandler,
+	entry port.EntryHandler,
+) *InstanceManager {
 	return &InstanceManager{
+		cc:      cc,
+		sent:    sent,
+		leases:  leases,
+		offsets: offsets,
+		bp:      bp,
+		replica: replica,
+		workers: workers,
 		l:       log,
 		process: process,
 		entry:   entry,
 	}
 }
 
+// startAttempts ограничивает количество попыток захватить аренду, если она освобождается, пока
+// другая реплика применяет запрошенный перезапуск.
+const startAttempts = 3
+
+// startRetryDelay есть пауза перед повторной попыткой захватить аренду.
+const startRetryDelay = 200 * time.Millisecond
+
 func (m *InstanceManager) Start(ctx context.Context, id bots.BotID, token bots.Token) error {
 	const op = "InstanceManager.Start"
 	l := m.l.With(
 		slog.String("op", op),
 		slog.String("bot_id", string(id)),
+		slog.String("replica", m.replica),
 	)
 
-	_, ok := m.m.Load(id)
-	if ok {
-		// Перезапускаем бота, если он уже запущен
-		l.InfoContext(ctx, "bot already exists; stopping instance...")
-		err := m.Stop(ctx, id)
+	for attempt := 1; ; attempt++ {
+		err := m.leases.AcquireLease(ctx, id, m.replica, leaseTTL)
+		if errors.Is(err, port.ErrLeaseHeld) {
+			// Бот уже опрашивается другой репликой: она перезапустит его с токеном из хранилища
+			l.InfoContext(ctx, "bot is owned by another replica; requesting restart")
+			err = m.requestRestart(ctx, id)
+			if !errors.Is(err, port.ErrLeaseNotFound) {
+				return err
+			}
+			// Аренда истекла или освобождена до применения перезапуска
+			if attempt == startAttempts {
+				return fmt.Errorf("failed to start bot instance %s: lease changed owner %d times", id, attempt)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(startRetryDelay):
+			}
+			continue
+		} else if err != nil {
+			l.ErrorContext(ctx, "failed to acquire bot lease", slog.String("error", err.Error()))
+			return fmt.Errorf("failed to start bot instance %s: %w", id, err)
+		}
+
+		err = m.startInstance(ctx, id, token)
 		if err != nil {
-			l.ErrorContext(ctx, "failed to stop previous instance while starting", slog.String("error", err.Error()))
+			if err2 := m.leases.ReleaseLease(ctx, id, m.replica); err2 != nil {
+				l.ErrorContext(ctx, "failed to release bot lease", slog.String("error", err2.Error()))
+			}
+			return err
 		}
+		return nil
+	}
+}
+
+func (m *InstanceManager) Stop(ctx context.Context, id bots.BotID) error {
+	if m.stopInstance(id) {
+		return m.leases.ReleaseLease(ctx, id, m.replica)
+	}
+
+	err := m.leases.RequestLeaseStop(ctx, id)
+	if errors.Is(err, port.ErrLeaseNotFound) {
+		return fmt.Errorf("%w: %s", port.ErrRunningInstanceNotFound, id)
+	} else if err != nil {
+		return err
+	}
+	// Владелец аренды освобождает её после остановки бота
+	return m.awaitLeaseChange(ctx, id, func() (bool, error) {
+		_, found, err2 := m.leases.LeaseOwner(ctx, id)
+		return !found, err2
+	})
+}
+
+func (m *InstanceManager) Reload(ctx context.Context, id bots.BotID, token bots.Token) error {
+	if _, ok := m.m.Load(id); ok {
+		return m.startInstance(ctx, id, token)
+	}
+	err := m.requestRestart(ctx, id)
+	if errors.Is(err, port.ErrLeaseNotFound) {
+		// Бот не запущен ни одной репликой; перехватившая аренду реплика запустит его с новым токеном
+		return nil
+	}
+	return err
+}
+
+// requestRestart запрашивает перезапуск бота у реплики-владельца аренды и дожидается его.
+// Возвращает ErrLeaseNotFound, если действующей аренды нет или она освободилась до перезапуска.
+func (m *InstanceManager) requestRestart(ctx context.Context, id bots.BotID) error {
+	generation, err := m.leases.RequestLeaseRestart(ctx, id)
+	if err != nil {
+		return err
+	}
+	return m.awaitLeaseChange(ctx, id, func() (bool, error) {
+		return m.leases.LeaseRestartApplied(ctx, id, generation)
+	})
+}
+
+// leaseChangeInterval есть период применения изменений, запрошенных у реплики, и проверки их применения.
+const leaseChangeInterval = time.Second
+
+// leaseChangeTimeout есть время, за которое владелец аренды должен применить запрошенное изменение.
+const leaseChangeTimeout = 10 * time.Second
+
+// awaitLeaseChange проверяет applied с периодом leaseChangeInterval, пока изменение не будет применено.
+// Возвращает ErrInstanceChangeTimeout, если изменение не применено за leaseChangeTimeout.
+func (m *InstanceManager) awaitLeaseChange(ctx context.Context, id bots.BotID, applied func() (bool, error)) error {
+	ticker := time.NewTicker(leaseChangeInterval)
+	defer ticker.Stop()
+	timeout := time.After(leaseChangeTimeout)
+	for {
+		ok, err := applied()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return fmt.Errorf("%w: %s", port.ErrInstanceChangeTimeout, id)
+		case <-ticker.C:
+		}
+	}
+}
+
+// MaintainLeases продлевает аренды реплики, перехватывает истёкшие аренды упавших реплик и применяет
+// запрошенные у реплики изменения, пока не будет отменён ctx.
+func (m *InstanceManager) MaintainLeases(ctx context.Context) {
+	ticker := time.NewTicker(leaseRenewInterval)
+	defer ticker.Stop()
+	changes := time.NewTicker(leaseChangeInterval)
+	defer changes.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes.C:
+			m.applyLeaseChanges(ctx)
+			continue
+		case <-ticker.C:
+		}
+
+		if m.syncLeases(ctx) {
+			renewedAt = time.Now()
+		} else if time.Since(renewedAt) > leaseTTL {
+			// Аренды могли быть перехвачены другими репликами; продолжение опроса приведёт к конфликту.
+			m.stopAll(ctx)
+		}
+	}
+}
+
+// applyLeaseChanges останавливает и перезапускает ботов реплики по запросам других реплик.
+func (m *InstanceManager) applyLeaseChanges(ctx context.Context) {
+	const op = "InstanceManager.applyLeaseChanges"
+	l := m.l.With(
+		slog.String("op", op),
+		slog.String("replica", m.replica),
+	)
+
+	changes, err := m.leases.LeaseChanges(ctx, m.replica)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to load lease changes", slog.String("error", err.Error()))
+		return
+	}
+	for _, change := range changes {
+		l := l.With(slog.String("bot_id", string(change.BotID)))
+		if change.Stop {
+			m.stopInstance(change.BotID)
+			if err = m.leases.ReleaseLease(ctx, change.BotID, m.replica); err != nil {
+				l.ErrorContext(ctx, "failed to release bot lease", slog.String("error", err.Error()))
+				continue
+			}
+			l.InfoContext(ctx, "bot instance stopped on request")
+			continue
+		}
+
+		bot, err := m.bp.Bot(ctx, change.BotID)
+		if err != nil {
+			l.ErrorContext(ctx, "failed to load bot for restart", slog.String("error", err.Error()))
+			continue
+		}
+		if err = m.startInstance(ctx, change.BotID, bot.Token()); err != nil {
+			continue
+		}
+		err = m.leases.ConfirmLeaseChange(ctx, change.BotID, m.replica, change.Generation)
+		if err != nil {
+			l.ErrorContext(ctx, "failed to confirm bot restart", slog.String("error", err.Error()))
+			continue
+		}
+		l.InfoContext(ctx, "bot instance restarted on request")
+	}
+}
+
+// syncLeases продлевает аренды, останавливает ботов с потерянной арендой и запускает захваченных.
+// Возвращает false, если продлить аренды не удалось.
+func (m *InstanceManager) syncLeases(ctx context.Context) bool {
+	const op = "InstanceManager.syncLeases"
+	l := m.l.With(
+		slog.String("op", op),
+		slog.String("replica", m.replica),
+	)
+
+	owned, err := m.leases.RenewLeases(ctx, m.replica, leaseTTL)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to renew bot leases", slog.String("error", err.Error()))
+		return false
+	}
+
+	ownedSet := make(map[bots.BotID]struct{}, len(owned))
+	for _, id := range owned {
+		ownedSet[id] = struct{}{}
+	}
+
+	m.m.Range(func(key, _ any) bool {
+		id, _ := key.(bots.BotID)
+		if _, ok := ownedSet[id]; !ok {
+			l.WarnContext(ctx, "bot lease lost; stopping instance", slog.String("bot_id", string(id)))
+			m.stopInstance(id)
+		}
+		return true
+	})
+
+	taken, err := m.leases.TakeOverExpiredLeases(ctx, m.replica, leaseTTL)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to take over expired bot leases", slog.String("error", err.Error()))
+	}
+	for _, id := range taken {
+		l.InfoContext(ctx, "took over bot lease", slog.String("bot_id", string(id)))
+	}
+
+	for _, id := range append(owned, taken...) {
+		if _, ok := m.m.Load(id); !ok {
+			m.startLeased(ctx, id)
+		}
+	}
+
+	return true
+}
+
+// startLeased запускает бота, аренда которого уже принадлежит реплике.
+func (m *InstanceManager) startLeased(ctx context.Context, id bots.BotID) {
+	const op = "InstanceManager.startLeased"
+	l := m.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", string(id)),
+		slog.String("replica", m.replica),
+	)
+
+	bot, err := m.bp.Bot(ctx, id)
+	if err == nil && bot.Enabled() {
+		err = m.startInstance(ctx, id, bot.Token())
+		if err == nil {
+			return
+		}
+	}
+	if err != nil && !errors.Is(err, port.ErrBotNotFound) {
+		l.ErrorContext(ctx, "failed to start leased bot", slog.String("error", err.Error()))
+	}
+
+	// Удалённый, выключенный или не запустившийся бот не должен удерживаться репликой
+	if err = m.leases.ReleaseLease(ctx, id, m.replica); err != nil {
+		l.ErrorContext(ctx, "failed to release bot lease", slog.String("error", err.Error()))
+	}
+}
+
+func (m *InstanceManager) startInstance(ctx context.Context, id bots.BotID, token bots.Token) error {
+	const op = "InstanceManager.startInstance"
+	l := m.l.With(
+		slog.String("op", op),
+		slog.String("bot_id", string(id)),
+	)
+
+	if m.stopInstance(id) {
+		// Перезапускаем бота, если он уже запущен
+		l.InfoContext(ctx, "previous bot instance stopped")
+	}
+
+	api, err := m.cc.Client(token)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to create bot api client", slog.String("error", err.Error()))
+		return fmt.Errorf("failed to start bot instance %s: %w", id, err)
 	}
 
-	ins, err := startBotInstance(id, token, m.process, m.entry, m.l)
-	m.m.Store(id, ins) // В любом случае сохраняем, чтобы иметь status = dead
+	offset, err := m.offsets.UpdateOffset(ctx, id)
 	if err != nil {
-		l.ErrorContext(ctx, "failed to start bot", slog.String("error", err.Error()))
+		l.ErrorContext(ctx, "failed to load update offset", slog.String("error", err.Error()))
 		return fmt.Errorf("failed to start bot instance %s: %w", id, err)
 	}
+
+	client := func() (*tgbotapi.BotAPI, error) {
+		m.cc.Invalidate(context.Background(), token)
+		return m.cc.Client(token)
+	}
+	ins := startBotInstance(id, token, api, client, offset, m.offsets, m.workers, m.process, m.entry, m.l)
+	m.m.Store(id, ins)
 	l.InfoContext(ctx, "bot instance started")
 
 	return nil
 }
 
-func (m *InstanceManager) Stop(_ context.Context, id bots.BotID) error {
-	r, ok := m.m.Load(id)
-	ins, _ := r.(*botInstance)
+// stopInstance останавливает экземпляр бота на этой реплике, не освобождая аренду.
+// Возвращает false, если экземпляр не запущен.
+func (m *InstanceManager) stopInstance(id bots.BotID) bool {
+	r, ok := m.m.LoadAndDelete(id)
 	if !ok {
-		return fmt.Errorf("%w: %s", port.ErrRunningInstanceNotFound, id)
+		return false
 	}
+	ins, _ := r.(*botInstance)
 	ins.Stop()
-	m.m.Delete(id)
-	return nil
+	return true
+}
+
+// Shutdown останавливает опрос всех ботов реплики, дожидается обработки полученных обновлений
+// и завершает аренды, чтобы другие реплики перехватили ботов при ближайшей синхронизации, не дожидаясь
+// истечения. Возвращает ошибку ctx, если обновления не успели обработаться до его отмены.
+func (m *InstanceManager) Shutdown(ctx context.Context) error {
+	const op = "InstanceManager.Shutdown"
+	l := m.l.With(
+		slog.String("op", op),
+		slog.String("replica", m.replica),
+	)
+
+	var wg sync.WaitGroup
+	m.m.Range(func(key, _ any) bool {
+		id, _ := key.(bots.BotID)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.stopInstance(id)
+			// Аренда завершается даже после дедлайна: иначе бот простаивает до её истечения. Удалённую
+			// аренду никто бы не перехватил
+			if err := m.leases.ExpireLease(context.WithoutCancel(ctx), id, m.replica); err != nil {
+				l.ErrorContext(ctx, "failed to expire bot lease",
+					slog.String("bot_id", string(id)),
+					slog.String("error", err.Error()),
+				)
+			}
+		}()
+		return true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		l.InfoContext(ctx, "all bot instances stopped")
+		return nil
+	case <-ctx.Done():
+		l.WarnContext(ctx, "bot instances did not stop before deadline")
+		return ctx.Err()
+	}
+}
+
+func (m *InstanceManager) stopAll(ctx context.Context) {
+	m.m.Range(func(key, _ any) bool {
+		id, _ := key.(bots.BotID)
+		m.l.WarnContext(ctx, "bot lease is not renewed; stopping instance",
+			slog.String("bot_id", string(id)),
+			slog.String("replica", m.replica),
+		)
+		m.stopInstance(id)
+		return true
+	})
 }
 
 type botInstance struct {
 	botID   bots.BotID
 	token   bots.Token
 	api     *tgbotapi.BotAPI
+	client  func() (*tgbotapi.BotAPI, error)
 	stopCh  chan struct{}
+	updates *dispatcher
+	offsets *offsetTracker
+	repo    port.UpdateOffsetRepository
 	process port.ProcessHandler
 	entry   port.EntryHandler
 	log     *slog.Logger
-	dead    bool
+
+	mu               sync.Mutex
+	dead             bool
+	self             tgbotapi.User
+	startedAt        time.Time
+	lastUpdateAt     time.Time
+	updatesProcessed int
+	restarts         int
+	lastError        string
+	lastErrorAt      time.Time
+
+	offsetMu    sync.Mutex
+	savedOffset int
 }
 
+// startBotInstance запускает опрос бота под наблюдением супервизора. Функция client используется
+// для повторного создания клиента при перезапуске: токен мог быть отозван или заменён.
+// Обновления обрабатываются в workers горутинах с сохранением порядка внутри каждого чата.
+// Опрос продолжается с обновления offset; номер подтверждённых обновлений сохраняется в repo.
 func startBotInstance(
 	botID bots.BotID,
 	token bots.Token,
+	api *tgbotapi.BotAPI,
+	client func() (*tgbotapi.BotAPI, error),
+	offset bots.UpdateID,
+	repo port.UpdateOffsetRepository,
+	workers int,
 	process port.ProcessHandler,
 	entry port.EntryHandler,
 	log *slog.Logger,
-) (*botInstance, error) {
-	api, err := tgbotapi.NewBotAPI(string(token))
-	if err != nil {
-		return nil, err
+) *botInstance {
+	i := &botInstance{
+		botID:       botID,
+		token:       token,
+		api:         api,
+		client:      client,
+		stopCh:      make(chan struct{}),
+		offsets:     newOffsetTracker(int(offset)),
+		repo:        repo,
+		process:     process,
+		entry:       entry,
+		log:         log,
+		dead:        false,
+		self:        api.Self,
+		startedAt:   time.Now(),
+		savedOffset: int(offset),
 	}
+	i.updates = newDispatcher(workers, i.processUpdate)
 
-	i := &botInstance{
-		botID:   botID,
-		token:   token,
-		api:     api,
-		stopCh:  make(chan struct{}),
-		process: process,
-		entry:   entry,
-		log:     log,
-		dead:    false,
+	go i.supervise()
+
+	return i
+}
+
+// Stats возвращает снимок счётчиков экземпляра.
+func (i *botInstance) Stats() instanceStats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return instanceStats{
+		dead:             i.dead,
+		self:             i.self,
+		startedAt:        i.startedAt,
+		lastUpdateAt:     i.lastUpdateAt,
+		updatesProcessed: i.updatesProcessed,
+		restarts:         i.restarts,
+		lastError:        i.lastError,
+		lastErrorAt:      i.lastErrorAt,
 	}
+}
 
-	conf := tgbotapi.NewUpdate(0)
-	updates, err := api.GetUpdatesChan(conf)
+type instanceStats struct {
+	dead             bool
+	self             tgbotapi.User
+	startedAt        time.Time
+	lastUpdateAt     time.Time
+	updatesProcessed int
+	restarts         int
+	lastError        string
+	lastErrorAt      time.Time
+}
+
+// Stop останавливает опрос и дожидается обработки уже полученных обновлений.
+func (i *botInstance) Stop() {
+	i.mu.Lock()
+	i.dead = false
+	i.mu.Unlock()
+	close(i.stopCh)
+	i.updates.Close()
+	i.saveOffset()
+}
+
+// pollTimeout есть время long polling запроса getUpdates.
+const pollTimeout = 30
+
+// pollRetryInterval есть пауза перед повторным запросом getUpdates после ошибки.
+const pollRetryInterval = 3 * time.Second
+
+// maxPollFailures есть количество ошибок getUpdates подряд, после которого опрос считается упавшим.
+const maxPollFailures = 5
+
+// restartBackoffMin и restartBackoffMax ограничивают экспоненциальную паузу перед перезапуском.
+const (
+	restartBackoffMin = 5 * time.Second
+	restartBackoffMax = 5 * time.Minute
+)
+
+// restartBackoffReset есть время стабильной работы, после которого пауза перезапуска сбрасывается.
+const restartBackoffReset = 10 * time.Minute
+
+// supervise перезапускает упавший опрос с экспоненциально растущей паузой, пока экземпляр не остановлен.
+func (i *botInstance) supervise() {
+	backoff := restartBackoffMin
+	for {
+		startedAt := time.Now()
+		err := i.run()
+		if err == nil {
+			return
+		}
+		if time.Since(startedAt) > restartBackoffReset {
+			backoff = restartBackoffMin
+		}
+
+		for err != nil {
+			i.fail(err, backoff)
+			select {
+			case <-i.stopCh:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, restartBackoffMax)
+			err = i.restart()
+		}
+	}
+}
+
+// restart пересоздаёт клиент Telegram Bot API перед повторным запуском опроса.
+func (i *botInstance) restart() error {
+	api, err := i.client()
 	if err != nil {
-		i.dead = true
-		return nil, err
+		return fmt.Errorf("failed to recreate bot api client: %w", err)
 	}
-	go i.run(updates)
+	i.api = api
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.self = api.Self
+	i.dead = false
+	i.restarts++
+	i.log.Info("bot instance restarted",
+		slog.String("bot_id", string(i.botID)),
+		slog.Int("restarts", i.restarts),
+	)
+	return nil
+}
+
+func (i *botInstance) fail(err error, backoff time.Duration) {
+	i.log.Error("bot instance failed; restarting",
+		slog.String("bot_id", string(i.botID)),
+		slog.String("error", err.Error()),
+		slog.Duration("backoff", backoff),
+	)
 
-	return i, nil
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.dead = true
+	i.lastError = err.Error()
+	i.lastErrorAt = time.Now()
 }
 
-func (i *botInstance) IsDead() bool {
-	return i.dead
+func (i *botInstance) recordError(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lastError = err.Error()
+	i.lastErrorAt = time.Now()
 }
 
-func (i *botInstance) Stop() {
-	i.dead = false
-	i.stopCh <- struct{}{}
+func (i *botInstance) processUpdate(upd tgbotapi.Update) {
+	i.handleUpdate(context.Background(), upd)
+	i.offsets.Done(upd.UpdateID)
+	i.recordUpdate()
+}
+
+// saveOffset сохраняет номер, с которого следует продолжить опрос после перезапуска.
+func (i *botInstance) saveOffset() {
+	ack := i.offsets.Ack()
+
+	i.offsetMu.Lock()
+	defer i.offsetMu.Unlock()
+	if ack <= i.savedOffset {
+		return
+	}
+	err := i.repo.SaveUpdateOffset(context.Background(), i.botID, bots.UpdateID(ack))
+	if err != nil {
+		i.log.Error("failed to save update offset",
+			slog.String("bot_id", string(i.botID)),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	i.savedOffset = ack
+}
+
+func (i *botInstance) botUser() tgbotapi.User {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.self
+}
+
+func (i *botInstance) recordUpdate() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lastUpdateAt = time.Now()
+	i.updatesProcessed++
 }
 
-func (i *botInstance) run(updates tgbotapi.UpdatesChannel) {
-	run := true
-	for run {
+// run получает обновления через getUpdates самостоятельно, а не через BotAPI.GetUpdatesChan:
+// клиент разделяется через ClientCache, а BotAPI.StopReceivingUpdates делает его непригодным
+// для повторного запуска. Возвращает nil после остановки экземпляра или ошибку, если
+// getUpdates завершился ошибкой maxPollFailures раз подряд.
+func (i *botInstance) run() error {
+	conf := tgbotapi.NewUpdate(0)
+	conf.Timeout = pollTimeout
+
+	failures := 0
+	for {
+		select {
+		case <-i.stopCh:
+			return nil
+		default:
+		}
+
+		// Необработанные обновления не подтверждаются и возвращаются повторно, см. offsetTracker
+		conf.Offset = i.offsets.Ack()
+		updates, err := i.api.GetUpdates(conf)
+		err = sanitizeError(err)
+
 		select {
-		case update := <-updates:
-			i.handleUpdate(context.Background(), update)
 		case <-i.stopCh:
-			run = false
+			// Полученные после остановки обновления не подтверждены и будут получены повторно.
+			return nil
+		default:
+		}
+
+		if err != nil {
+			i.log.Error("failed to get updates",
+				slog.String("bot_id", string(i.botID)),
+				slog.String("error", err.Error()),
+			)
+			i.recordError(err)
+			failures++
+			if failures >= maxPollFailures {
+				return fmt.Errorf("polling failed %d times in a row: %w", failures, err)
+			}
+			select {
+			case <-i.stopCh:
+				return nil
+			case <-time.After(pollRetryInterval):
+			}
+			continue
+		}
+		failures = 0
+
+		for _, update := range updates {
+			if !i.offsets.Receive(update.UpdateID) {
+				// Обновление уже получено и ещё обрабатывается
+				continue
+			}
+			if !i.updates.Dispatch(update) {
+				// Экземпляр остановлен; неподтверждённые обновления будут получены повторно.
+				return nil
+			}
 		}
+		i.saveOffset()
 	}
-	close(i.stopCh)
-	i.api.StopReceivingUpdates()
 }
 
 func (i *botInstance) handleUpdate(ctx context.Context, upd tgbotapi.Update) {
@@ -141,18 +725,32 @@ func (i *botInstance) handleUpdate(ctx context.Context, upd tgbotapi.Update) {
 		slog.String("bot_id", string(i.botID)),
 	)
 
-	if upd.Message == nil {
+	m := upd.Message
+	if m == nil || m.Chat == nil || m.Chat.IsChannel() {
+		return
+	}
+
+	if m.From == nil {
+		l.WarnContext(ctx, "message without sender", slog.Int64("chat_id", m.Chat.ID))
 		return
 	}
 
+	self := i.botUser()
+	origin := originFromMessage(m, self)
+	origin.UpdateID = bots.UpdateID(upd.UpdateID)
+
 	var err error
-	if upd.Message.IsCommand() {
-		err = i.entry.Entry(ctx, i.botID, bots.UserID(upd.Message.Chat.ID), bots.EntryKey(upd.Message.Command()))
+	if m.IsCommand() {
+		if !isCommandForBot(m, self) {
+			// Команда адресована другому боту в группе
+			return
+		}
+		err = i.entry.Entry(ctx, i.botID, origin, bots.EntryKey(m.Command()))
 	} else {
-		if msg, err2 := bots.NewMessage(upd.Message.Text); err2 == nil {
-			err = i.process.Process(ctx, i.botID, bots.UserID(upd.Message.Chat.ID), msg)
+		if msg, err2 := bots.NewMessage(m.Text); err2 == nil {
+			err = i.process.Process(ctx, i.botID, origin, msg)
 		} else {
-			l.WarnContext(ctx, "unhandled message", slog.String("message", fmt.Sprintf("%v", upd.Message)))
+			l.WarnContext(ctx, "unhandled message", slog.String("message", fmt.Sprintf("%v", m)))
 		}
 	}
 
@@ -0,0 +1,223 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/metrics"
+)
+
+// memLeases хранит аренды в памяти, как общая таблица bot_leases нескольких реплик.
+type memLeases struct {
+	port.LeaseRepository
+	mu     sync.Mutex
+	leases map[bots.BotID]port.Lease
+}
+
+func newMemLeases() *memLeases {
+	return &memLeases{leases: make(map[bots.BotID]port.Lease)}
+}
+
+func (m *memLeases) AcquireLease(_ context.Context, id bots.BotID, replica string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lease, ok := m.leases[id]
+	if ok && lease.Replica != replica && lease.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: %s", port.ErrLeaseHeld, id)
+	}
+	m.leases[id] = port.Lease{Replica: replica, RenewedAt: time.Now(), ExpiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (m *memLeases) RenewLeases(_ context.Context, replica string, ttl time.Duration) ([]bots.BotID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []bots.BotID
+	for id, lease := range m.leases {
+		if lease.Replica == replica {
+			m.leases[id] = port.Lease{Replica: replica, RenewedAt: time.Now(), ExpiresAt: time.Now().Add(ttl)}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memLeases) TakeOverExpiredLeases(_ context.Context, replica string, ttl time.Duration) ([]bots.BotID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []bots.BotID
+	for id, lease := range m.leases {
+		if !lease.ExpiresAt.After(time.Now()) {
+			m.leases[id] = port.Lease{Replica: replica, RenewedAt: time.Now(), ExpiresAt: time.Now().Add(ttl)}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memLeases) ReleaseLease(_ context.Context, id bots.BotID, replica string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leases[id].Replica == replica {
+		delete(m.leases, id)
+	}
+	return nil
+}
+
+func (m *memLeases) ExpireLease(_ context.Context, id bots.BotID, replica string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lease, ok := m.leases[id]; ok && lease.Replica == replica {
+		lease.ExpiresAt = time.Now()
+		m.leases[id] = lease
+	}
+	return nil
+}
+
+func (m *memLeases) LeaseOwner(_ context.Context, id bots.BotID) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lease, ok := m.leases[id]
+	if !ok || !lease.ExpiresAt.After(time.Now()) {
+		return "", false, nil
+	}
+	return lease.Replica, true, nil
+}
+
+type memBots map[bots.BotID]*bots.Bot
+
+func (m memBots) Bot(_ context.Context, id bots.BotID) (*bots.Bot, error) {
+	if bot, ok := m[id]; ok {
+		return bot, nil
+	}
+	return nil, fmt.Errorf("%w: %s", port.ErrBotNotFound, id)
+}
+
+func (m memBots) UserBots(context.Context, bots.AccountID) ([]*bots.Bot, error) {
+	return nil, nil
+}
+
+func (m memBots) EnabledBots(context.Context) ([]*bots.Bot, error) {
+	return nil, nil
+}
+
+func (m memBots) BotByTelegramID(context.Context, int64) (*bots.Bot, error) {
+	return nil, port.ErrBotNotFound
+}
+
+type nopOffsets struct{}
+
+func (nopOffsets) UpdateOffset(context.Context, bots.BotID) (bots.UpdateID, error) {
+	return 0, nil
+}
+
+func (nopOffsets) SaveUpdateOffset(context.Context, bots.BotID, bots.UpdateID) error {
+	return nil
+}
+
+// newTestClientCache возвращает кэш с клиентом для token, запросы которого обслуживает тестовый Bot API
+// без обновлений.
+func newTestClientCache(t *testing.T, token bots.Token) *ClientCache {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/getUpdates") {
+			time.Sleep(10 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	target, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	api, err := tgbotapi.NewBotAPIWithClient(string(token), &http.Client{Transport: redirectTransport{target}})
+	require.NoError(t, err)
+
+	cc := NewClientCache(metrics.NoOp{})
+	cc.clients[token] = api
+	return cc
+}
+
+func TestInstanceManager_ShutdownHandsOverBots(t *testing.T) {
+	ctx := context.Background()
+	token := bots.Token("1:token")
+	bot := bots.MustNewBot("bot", token, "author", bots.MustNewScript(
+		[]bots.Node{bots.MustNewNode(bots.MustNewState(1), "Greeting", nil, []bots.Message{
+			bots.MustNewMessage("Hello, world!"),
+		}, nil)},
+		[]bots.Entry{bots.MustNewEntry("start", bots.MustNewState(1))},
+	))
+	bot.Enable()
+	bp := memBots{bot.ID(): bot}
+	cc := newTestClientCache(t, token)
+	leases := newMemLeases()
+	replica := func(name string) *InstanceManager {
+		return NewInstanceManager(
+			cc, NewSentCounter(), leases, nopOffsets{}, bp, name, 1, logs.DefaultLogger(), nil, nil,
+		)
+	}
+	r1, r2 := replica("replica-1"), replica("replica-2")
+
+	require.NoError(t, r1.Start(ctx, bot.ID(), token))
+	require.True(t, r2.syncLeases(ctx))
+	_, running := r2.m.Load(bot.ID())
+	require.False(t, running)
+
+	// replica-1 останавливается при выкатке; replica-2 подхватывает бота при ближайшей синхронизации
+	require.NoError(t, r1.Shutdown(ctx))
+	require.True(t, r2.syncLeases(ctx))
+	t.Cleanup(func() { _ = r2.Shutdown(ctx) })
+
+	_, running = r2.m.Load(bot.ID())
+	require.True(t, running)
+	owner, found, err := leases.LeaseOwner(ctx, bot.ID())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "replica-2", owner)
+}
+
+// flappingLeases всегда занята другой репликой, но освобождается к моменту запроса перезапуска.
+type flappingLeases struct {
+	port.LeaseRepository
+	acquired atomic.Int32
+}
+
+func (f *flappingLeases) AcquireLease(_ context.Context, id bots.BotID, _ string, _ time.Duration) error {
+	f.acquired.Add(1)
+	return fmt.Errorf("%w: %s", port.ErrLeaseHeld, id)
+}
+
+func (f *flappingLeases) RequestLeaseRestart(_ context.Context, id bots.BotID) (int64, error) {
+	return 0, fmt.Errorf("%w: %s", port.ErrLeaseNotFound, id)
+}
+
+func TestInstanceManager_StartGivesUpWhenLeaseFlaps(t *testing.T) {
+	leases := &flappingLeases{}
+	m := NewInstanceManager(
+		NewClientCache(metrics.NoOp{}), NewSentCounter(), leases, nopOffsets{}, memBots{}, "replica-1", 1,
+		logs.DefaultLogger(), nil, nil,
+	)
+
+	err := m.Start(context.Background(), "bot", "1:token")
+	require.Error(t, err)
+	require.Equal(t, int32(startAttempts), leases.acquired.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, m.Start(ctx, "bot", "1:token"), context.Canceled)
+}
@@ -13,30 +13,35 @@ import (
 )
 
 type MessageSender struct {
-	l *slog.Logger
+	cc   *ClientCache
+	sent *SentCounter
+	l    *slog.Logger
 }
 
-func NewMessageSender(l *slog.Logger) *MessageSender {
+func NewMessageSender(cc *ClientCache, sent *SentCounter, l *slog.Logger) *MessageSender {
 	return &MessageSender{
-		l: l,
+		cc:   cc,
+		sent: sent,
+		l:    l,
 	}
 }
 
 func (s *MessageSender) Send(
-	ctx context.Context, token bots.Token, userID bots.UserID, msg bots.BotMessage,
+	ctx context.Context, token bots.Token, chatID bots.ChatID, msg bots.BotMessage,
 ) error {
 	const op = "MessageSender.Send"
 	l := s.l.With(
 		slog.String("op", op),
+		slog.Int64("chat_id", int64(chatID)),
 		slog.String("message", msg.String()),
 	)
 
-	api, err := tgbotapi.NewBotAPI(string(token))
+	api, err := s.cc.Client(token)
 	if err != nil {
 		return err
 	}
 
-	m := tgbotapi.NewMessage(int64(userID), msg.Text())
+	m := tgbotapi.NewMessage(int64(chatID), msg.Text())
 	m.ParseMode = tgbotapi.ModeHTML // Меньше головной боли с пользовательским вводом
 	if opts := msg.Options(); len(opts) > 0 {
 		m.ReplyMarkup = buildInlineKeyboardMarkup(opts)
@@ -45,6 +50,7 @@ func (s *MessageSender) Send(
 	}
 
 	_, err = api.Send(m)
+	err = sanitizeError(err)
 	if err != nil {
 		if isCantParseEntitiesError(err) {
 			l.WarnContext(ctx, "can't parse HTML entities in message, send message without formatting",
@@ -52,13 +58,20 @@ func (s *MessageSender) Send(
 			)
 			m.ParseMode = ""
 			_, err = api.Send(m)
+			err = sanitizeError(err)
 		} else if isForbiddenError(err) {
 			l.WarnContext(ctx, "user blocked bot, can't send message",
 				slog.String("error", err.Error()),
 			)
-			err = fmt.Errorf("%w: %d", port.ErrUserBlockedBot, userID)
+			err = fmt.Errorf("%w: %d", port.ErrUserBlockedBot, chatID)
+		} else if isUnauthorizedError(err) {
+			// Токен был отозван: закэшированный клиент больше не пригоден
+			s.cc.Invalidate(ctx, token)
 		}
 	}
+	if err == nil {
+		s.sent.Inc(token)
+	}
 
 	return err
 }
@@ -71,6 +84,10 @@ func isForbiddenError(err error) bool {
 	return strings.Contains(err.Error(), "Forbidden")
 }
 
+func isUnauthorizedError(err error) bool {
+	return strings.Contains(err.Error(), "Unauthorized")
+}
+
 func buildInlineKeyboardMarkup(opts []bots.Option) tgbotapi.ReplyKeyboardMarkup {
 	rows := make([][]tgbotapi.KeyboardButton, len(opts))
 	for i, opt := range opts {
@@ -0,0 +1,53 @@
+package telegram
+
+import "sync"
+
+// offsetTracker отслеживает полученные от Telegram, но ещё не обработанные обновления.
+// Telegram считает подтверждёнными все обновления с номером меньше offset запроса getUpdates,
+// поэтому подтверждается только номер наименьшего необработанного обновления: после сбоя
+// необработанные обновления будут получены повторно.
+type offsetTracker struct {
+	mu       sync.Mutex
+	next     int // Номер, следующий за последним полученным обновлением
+	inflight map[int]struct{}
+}
+
+func newOffsetTracker(offset int) *offsetTracker {
+	return &offsetTracker{
+		next:     offset,
+		inflight: make(map[int]struct{}),
+	}
+}
+
+// Receive отмечает обновление полученным. Возвращает false, если обновление уже было получено
+// ранее: неподтверждённые обновления Telegram возвращает повторно.
+func (t *offsetTracker) Receive(id int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id < t.next {
+		return false
+	}
+	t.inflight[id] = struct{}{}
+	t.next = id + 1
+	return true
+}
+
+// Done отмечает обновление обработанным.
+func (t *offsetTracker) Done(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.inflight, id)
+}
+
+// Ack возвращает номер, меньше которого все полученные обновления обработаны.
+func (t *offsetTracker) Ack() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ack := t.next
+	for id := range t.inflight {
+		if id < ack {
+			ack = id
+		}
+	}
+	return ack
+}
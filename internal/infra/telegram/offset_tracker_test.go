@@ -0,0 +1,34 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffsetTracker_AckWaitsForOldestInflight(t *testing.T) {
+	tr := newOffsetTracker(10)
+	require.Equal(t, 10, tr.Ack())
+
+	require.True(t, tr.Receive(10))
+	require.True(t, tr.Receive(11))
+	require.True(t, tr.Receive(13))
+	require.Equal(t, 10, tr.Ack())
+
+	tr.Done(11)
+	tr.Done(13)
+	require.Equal(t, 10, tr.Ack())
+
+	tr.Done(10)
+	require.Equal(t, 14, tr.Ack())
+}
+
+func TestOffsetTracker_SkipsAlreadyReceived(t *testing.T) {
+	tr := newOffsetTracker(10)
+
+	require.False(t, tr.Receive(9))
+	require.True(t, tr.Receive(10))
+	require.False(t, tr.Receive(10))
+	require.True(t, tr.Receive(12))
+	require.False(t, tr.Receive(11))
+}
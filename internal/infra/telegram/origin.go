@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// originFromMessage разделяет чат, в который пришло сообщение, и его отправителя.
+// Ожидает m.From != nil.
+func originFromMessage(m *tgbotapi.Message, self tgbotapi.User) port.Origin {
+	group := m.Chat.IsGroup() || m.Chat.IsSuperGroup()
+	return port.Origin{
+		ChatID:    bots.ChatID(m.Chat.ID),
+		UserID:    bots.UserID(m.From.ID),
+		Profile:   profileFromUser(m.From),
+		Group:     group,
+		Addressed: group && isAddressedToBot(m, self),
+	}
+}
+
+// profileFromUser возвращает нулевой профиль, если отправитель неизвестен, например, для сообщений каналов.
+func profileFromUser(u *tgbotapi.User) bots.Profile {
+	if u == nil {
+		return bots.Profile{}
+	}
+	return bots.NewProfile(bots.Username(u.UserName), u.FirstName, u.LastName, u.LanguageCode)
+}
+
+// isAddressedToBot сообщает, что сообщение является командой для бота, упоминает его
+// или отвечает на его сообщение.
+func isAddressedToBot(m *tgbotapi.Message, self tgbotapi.User) bool {
+	if m.IsCommand() {
+		return isCommandForBot(m, self)
+	}
+
+	if m.ReplyToMessage != nil && m.ReplyToMessage.From != nil && m.ReplyToMessage.From.ID == self.ID {
+		return true
+	}
+
+	if m.Entities == nil {
+		return false
+	}
+	mention := "@" + strings.ToLower(self.UserName)
+	for _, e := range *m.Entities {
+		switch e.Type {
+		case "mention":
+			if self.UserName != "" && strings.Contains(strings.ToLower(m.Text), mention) {
+				return true
+			}
+		case "text_mention":
+			if e.User != nil && e.User.ID == self.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCommandForBot отбрасывает команды вида /start@other_bot, адресованные другим ботам группы.
+func isCommandForBot(m *tgbotapi.Message, self tgbotapi.User) bool {
+	cmd := m.CommandWithAt()
+	i := strings.Index(cmd, "@")
+	if i == -1 {
+		return true
+	}
+	return strings.EqualFold(cmd[i+1:], self.UserName)
+}
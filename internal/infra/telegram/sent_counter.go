@@ -0,0 +1,33 @@
+package telegram
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// SentCounter считает сообщения, отправленные MessageSender, по токену бота.
+// Разделяется MessageSender и InstanceManager, который возвращает счётчик в статусе бота.
+type SentCounter struct {
+	m sync.Map // map[bots.Token]*atomic.Int64
+}
+
+func NewSentCounter() *SentCounter {
+	return &SentCounter{}
+}
+
+func (c *SentCounter) Inc(token bots.Token) {
+	v, _ := c.m.LoadOrStore(token, new(atomic.Int64))
+	n, _ := v.(*atomic.Int64)
+	n.Add(1)
+}
+
+func (c *SentCounter) Count(token bots.Token) int64 {
+	v, ok := c.m.Load(token)
+	if !ok {
+		return 0
+	}
+	n, _ := v.(*atomic.Int64)
+	return n.Load()
+}
@@ -2,19 +2,54 @@ package telegram
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
 	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
 )
 
-func (m *InstanceManager) Status(_ context.Context, id bots.BotID) (bots.Status, error) {
-	r, ok := m.m.Load(id)
-	if !ok {
-		return bots.Idle, nil
+// Status строит статус бота по аренде из общего хранилища, поэтому любая реплика видит, какая реплика
+// опрашивает бота и когда она последний раз продлила аренду. Счётчики экземпляра добавляются, только
+// если бота опрашивает эта реплика.
+func (m *InstanceManager) Status(ctx context.Context, id bots.BotID) (port.InstanceStatus, error) {
+	lease, err := m.leases.Lease(ctx, id)
+	if errors.Is(err, port.ErrLeaseNotFound) {
+		return port.InstanceStatus{Status: bots.Idle}, nil
+	} else if err != nil {
+		return port.InstanceStatus{}, err
+	}
+
+	res := port.InstanceStatus{
+		Status:      bots.Running,
+		Replica:     lease.Replica,
+		Mode:        port.ModePolling,
+		HeartbeatAt: lease.RenewedAt,
+	}
+	if lease.ExpiresAt.Before(time.Now()) {
+		// Владелец перестал продлевать аренду и ещё не заменён другой репликой
+		res.Status = bots.Dead
+		return res, nil
 	}
 
+	r, ok := m.m.Load(id)
+	if !ok || lease.Replica != m.replica {
+		return res, nil
+	}
 	ins, _ := r.(*botInstance)
-	if ins.IsDead() {
-		return bots.Dead, nil
+	stats := ins.Stats()
+	res.StatsReplica = m.replica
+	res.TelegramID = int64(stats.self.ID)
+	res.Username = stats.self.UserName
+	res.StartedAt = stats.startedAt
+	res.LastUpdateAt = stats.lastUpdateAt
+	res.UpdatesProcessed = stats.updatesProcessed
+	res.MessagesSent = int(m.sent.Count(ins.token))
+	res.Restarts = stats.restarts
+	res.LastError = stats.lastError
+	res.LastErrorAt = stats.lastErrorAt
+	if stats.dead {
+		res.Status = bots.Dead
 	}
-	return bots.Running, nil
+	return res, nil
 }
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+)
+
+// leaseTable отдаёт аренды ботов; остальные методы port.LeaseRepository статусу не нужны.
+type leaseTable struct {
+	port.LeaseRepository
+	leases map[bots.BotID]port.Lease
+}
+
+func (t leaseTable) Lease(_ context.Context, id bots.BotID) (port.Lease, error) {
+	lease, ok := t.leases[id]
+	if !ok {
+		return port.Lease{}, fmt.Errorf("%w: %s", port.ErrLeaseNotFound, id)
+	}
+	return lease, nil
+}
+
+func TestInstanceManager_StatusFromLease(t *testing.T) {
+	ctx := context.Background()
+	heartbeat := time.Now().Add(-5 * time.Second)
+	leases := leaseTable{leases: map[bots.BotID]port.Lease{
+		"remote":  {Replica: "replica-2", RenewedAt: heartbeat, ExpiresAt: heartbeat.Add(leaseTTL)},
+		"crashed": {Replica: "replica-3", RenewedAt: heartbeat.Add(-time.Minute), ExpiresAt: heartbeat},
+	}}
+	m := NewInstanceManager(nil, nil, leases, nil, nil, "replica-1", 1, logs.DefaultLogger(), nil, nil)
+
+	status, err := m.Status(ctx, "remote")
+	require.NoError(t, err)
+	require.Equal(t, bots.Running, status.Status)
+	require.Equal(t, "replica-2", status.Replica)
+	require.Equal(t, heartbeat, status.HeartbeatAt)
+	// Счётчики другой реплики неизвестны
+	require.Empty(t, status.StatsReplica)
+	require.Zero(t, status.StartedAt)
+
+	status, err = m.Status(ctx, "crashed")
+	require.NoError(t, err)
+	require.Equal(t, bots.Dead, status.Status)
+	require.Equal(t, "replica-3", status.Replica)
+
+	status, err = m.Status(ctx, "idle")
+	require.NoError(t, err)
+	require.Equal(t, bots.Idle, status.Status)
+	require.Empty(t, status.Replica)
+}
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+)
+
+// tokenVerifyTimeout ограничивает время запроса getMe при проверке токена.
+const tokenVerifyTimeout = 10 * time.Second
+
+// TokenVerifier проверяет токены ботов запросом getMe. Клиент не кэшируется в ClientCache:
+// проверяемый токен может так и не быть сохранён.
+type TokenVerifier struct {
+	client *http.Client
+}
+
+func NewTokenVerifier() *TokenVerifier {
+	return &TokenVerifier{
+		client: &http.Client{Timeout: tokenVerifyTimeout},
+	}
+}
+
+func (v *TokenVerifier) VerifyToken(_ context.Context, token bots.Token) (bots.TelegramAccount, error) {
+	api, err := tgbotapi.NewBotAPIWithClient(string(token), v.client)
+	var tgErr tgbotapi.Error
+	if errors.As(err, &tgErr) {
+		// Telegram отвечает 401 на неверный токен и 404 на токен неверного формата
+		return bots.TelegramAccount{}, fmt.Errorf("%w: %s", port.ErrTokenRejected, tgErr.Message)
+	}
+	if err != nil {
+		return bots.TelegramAccount{}, fmt.Errorf("requesting getMe: %w", sanitizeError(err))
+	}
+	return bots.NewTelegramAccount(int64(api.Self.ID), api.Self.UserName)
+}
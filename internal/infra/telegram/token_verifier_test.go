@@ -0,0 +1,60 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+)
+
+// redirectTransport отправляет запросы к Bot API на тестовый сервер.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestTokenVerifier_VerifyToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasPrefix(r.URL.Path, "/bot123:valid/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"Test","username":"test_bot"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	target, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	v := &TokenVerifier{client: &http.Client{Transport: redirectTransport{target}}}
+
+	a, err := v.VerifyToken(context.Background(), "123:valid")
+	require.NoError(t, err)
+	require.Equal(t, int64(123), a.ID())
+	require.Equal(t, "test_bot", a.Username())
+
+	_, err = v.VerifyToken(context.Background(), "123:typo")
+	require.ErrorIs(t, err, port.ErrTokenRejected)
+}
+
+func TestTokenVerifier_HidesTokenInErrors(t *testing.T) {
+	v := &TokenVerifier{client: &http.Client{Transport: redirectTransport{&url.URL{Scheme: "http", Host: "127.0.0.1:1"}}}}
+
+	_, err := v.VerifyToken(context.Background(), "123:secret")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, port.ErrTokenRejected)
+	require.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+)
+
+// enqueueRetryDelay задерживает повторную доставку события, которое не удалось поставить в очередь,
+// чтобы недоступная база данных не нагружалась повторами без паузы.
+const enqueueRetryDelay = time.Second
+
+// AddConsumers подписывает router на все доменные события: каждое событие превращается в тело
+// запроса и ставится в очередь доставки подписанным на него Webhook бота.
+func AddConsumers(
+	router *message.Router,
+	sub message.Subscriber,
+	handler port.WebhookEventHandler,
+	l *slog.Logger,
+) {
+	for _, name := range bots.EventNames() {
+		router.AddNoPublisherHandler("webhooks."+name, events.Topic(name), sub, consume(name, handler, l))
+	}
+}
+
+func consume(event string, handler port.WebhookEventHandler, l *slog.Logger) message.NoPublishHandlerFunc {
+	const op = "webhooks.consume"
+
+	return func(msg *message.Message) error {
+		ctx := msg.Context()
+		l := l.With(slog.String("op", op), slog.String("event", event))
+
+		botID, body, err := bodyFromEvent(event, msg.Payload)
+		if err != nil {
+			// Повторная обработка не исправит повреждённое сообщение
+			l.ErrorContext(ctx, "failed to build webhook body", slog.String("error", err.Error()))
+			return nil
+		}
+
+		err = handler.Enqueue(ctx, botID, msg.UUID, event, body)
+		if err != nil {
+			// Событие не подтверждается и будет доставлено повторно: иначе Webhook его не получат
+			l.ErrorContext(ctx, "failed to enqueue webhook deliveries",
+				slog.String("bot_id", string(botID)), slog.String("error", err.Error()))
+			select {
+			case <-time.After(enqueueRetryDelay):
+			case <-ctx.Done():
+			}
+			return fmt.Errorf("enqueueing webhook deliveries of bot %s: %w", botID, err)
+		}
+		return nil
+	}
+}
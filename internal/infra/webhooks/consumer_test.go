@@ -0,0 +1,63 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/webhooks"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+)
+
+// flakyQueue не ставит в очередь первое событие, имитируя недоступную базу данных.
+type flakyQueue struct {
+	mu       sync.Mutex
+	calls    int
+	enqueued chan bots.BotID
+}
+
+func (q *flakyQueue) Enqueue(_ context.Context, botID bots.BotID, _ string, _ string, _ []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.calls++
+	if q.calls == 1 {
+		return errors.New("database is unavailable")
+	}
+	q.enqueued <- botID
+	return nil
+}
+
+func TestAddConsumers_RedeliversOnEnqueueError(t *testing.T) {
+	l := logs.DefaultLogger()
+	bus := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	t.Cleanup(func() { _ = bus.Close() })
+	router, err := message.NewRouter(message.RouterConfig{}, watermill.NopLogger{})
+	require.NoError(t, err)
+
+	q := &flakyQueue{enqueued: make(chan bots.BotID, 1)}
+	webhooks.AddConsumers(router, bus, q, l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	go func() { _ = router.Run(ctx) }()
+	<-router.Running()
+
+	err = events.NewPublisher(l, bus).Publish(ctx, bots.BotEnabled{BotID: "bot", EnabledAt: time.Now()})
+	require.NoError(t, err)
+
+	select {
+	case botID := <-q.enqueued:
+		require.Equal(t, bots.BotID("bot"), botID)
+	case <-ctx.Done():
+		t.Fatal("event was not redelivered")
+	}
+}
@@ -0,0 +1,120 @@
+package webhooks
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/app/port"
+)
+
+const (
+	// pollInterval есть период опроса очереди доставок, если новых событий не ожидается.
+	pollInterval = time.Second
+	// claimBatch ограничивает количество доставок, захватываемых за один опрос.
+	claimBatch = 50
+	// claimLock есть время, на которое доставка захватывается репликой. Оно должно превышать
+	// sendTimeout, иначе доставку может повторно захватить другая реплика.
+	claimLock = time.Minute
+)
+
+// Dispatcher доставляет события из очереди доставок на Webhook. Порядок доставок не
+// гарантируется: получатели должны опираться на время события в теле запроса.
+type Dispatcher struct {
+	repo    port.WebhookRepository
+	handler port.WebhookDeliveryHandler
+	workers int
+
+	wake chan struct{}
+	l    *slog.Logger
+}
+
+func NewDispatcher(
+	repo port.WebhookRepository,
+	handler port.WebhookDeliveryHandler,
+	workers int,
+	l *slog.Logger,
+) *Dispatcher {
+	return &Dispatcher{
+		repo:    repo,
+		handler: handler,
+		workers: max(workers, 1),
+		wake:    make(chan struct{}, 1),
+		l:       l,
+	}
+}
+
+// Run доставляет события до отмены ctx. Уже начатые доставки завершаются.
+func (d *Dispatcher) Run(ctx context.Context) {
+	const op = "webhooks.Dispatcher.Run"
+	l := d.l.With(slog.String("op", op))
+	l.InfoContext(ctx, "webhook dispatcher started")
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		n, err := d.poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.ErrorContext(ctx, "failed to claim webhook deliveries", slog.String("error", err.Error()))
+		}
+		if n == claimBatch && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			l.InfoContext(ctx, "webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Notify сообщает о появлении в очереди новых доставок, чтобы не ждать следующего опроса.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) poll(ctx context.Context) (int, error) {
+	ds, err := d.repo.ClaimWebhookDeliveries(ctx, claimBatch, claimLock)
+	if err != nil {
+		return 0, err
+	}
+
+	// Доставка не прерывается отменой ctx, чтобы успеть зафиксировать её результат
+	deliverCtx := context.WithoutCancel(ctx)
+	sem := make(chan struct{}, d.workers)
+	var wg sync.WaitGroup
+	for _, delivery := range ds {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			d.deliver(deliverCtx, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(ds), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery port.WebhookDelivery) {
+	const op = "webhooks.Dispatcher.deliver"
+
+	if err := d.handler.Deliver(ctx, delivery); err != nil {
+		// Результат попытки уже сохранён в журнале, повторять здесь не нужно
+		d.l.WarnContext(ctx, "failed to deliver webhook",
+			slog.String("op", op),
+			slog.Int64("id", delivery.ID),
+			slog.String("webhook_id", string(delivery.WebhookID)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
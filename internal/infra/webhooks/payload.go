@@ -0,0 +1,100 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/domain/bots"
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/events"
+)
+
+// Body есть JSON-тело запроса на Webhook. Data совпадает с представлением события в
+// infra/events, кроме thread_completed, для которого используется ThreadCompleted.
+type Body struct {
+	Event string          `json:"event"`
+	BotID string          `json:"botId"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// ThreadCompleted есть данные события thread_completed, где ответы собраны по заголовкам
+// узлов, а не по номерам состояний: получателям не нужно знать устройство сценария.
+type ThreadCompleted struct {
+	ChatID      int64             `json:"chatId"`
+	UserID      int64             `json:"userId"`
+	ThreadID    string            `json:"threadId"`
+	Key         string            `json:"key"`
+	Answers     map[string]string `json:"answers"`
+	StartedAt   time.Time         `json:"startedAt"`
+	CompletedAt time.Time         `json:"completedAt"`
+}
+
+// bodyFromEvent строит тело запроса из сообщения о событии с именем event.
+func bodyFromEvent(event string, payload []byte) (bots.BotID, []byte, error) {
+	var header struct {
+		BotID string `json:"botId"`
+	}
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return "", nil, fmt.Errorf("decoding event %s: %w", event, err)
+	}
+	if header.BotID == "" {
+		return "", nil, fmt.Errorf("event %s has no bot id", event)
+	}
+
+	data := json.RawMessage(payload)
+	if event == bots.EventThreadCompleted {
+		var e events.ThreadCompleted
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return "", nil, fmt.Errorf("decoding event %s: %w", event, err)
+		}
+		var err error
+		data, err = json.Marshal(threadCompletedFromEvent(e))
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	body, err := json.Marshal(Body{Event: event, BotID: header.BotID, Data: data})
+	if err != nil {
+		return "", nil, err
+	}
+	return bots.BotID(header.BotID), body, nil
+}
+
+func threadCompletedFromEvent(e events.ThreadCompleted) ThreadCompleted {
+	return ThreadCompleted{
+		ChatID:      e.ChatID,
+		UserID:      e.UserID,
+		ThreadID:    e.ThreadID,
+		Key:         e.Key,
+		Answers:     answersByTitle(e.Answers, e.Titles),
+		StartedAt:   e.StartedAt,
+		CompletedAt: e.CompletedAt,
+	}
+}
+
+// answersByTitle переносит ответы с номеров состояний на заголовки узлов. Ответ на узел без
+// заголовка или с заголовком, уже занятым узлом с меньшим номером, остаётся под номером состояния.
+func answersByTitle(answers map[string]string, titles map[string]string) map[string]string {
+	states := make([]string, 0, len(answers))
+	for state := range answers {
+		states = append(states, state)
+	}
+	slices.SortFunc(states, func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	})
+
+	res := make(map[string]string, len(answers))
+	for _, state := range states {
+		key := titles[state]
+		if _, taken := res[key]; key == "" || taken {
+			key = state
+		}
+		res[key] = answers[state]
+	}
+	return res
+}
@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyFromEvent_ThreadCompleted(t *testing.T) {
+	payload := []byte(`{
+		"botId": "bot",
+		"chatId": 42,
+		"userId": 42,
+		"threadId": "thread",
+		"key": "start",
+		"answers": {"1": "Иванов Иван", "2": "ИУ7", "3": "Да", "10": "Нет"},
+		"titles": {"1": "ФИО", "2": "Группа", "3": "", "10": "ФИО"}
+	}`)
+
+	botID, body, err := bodyFromEvent("thread_completed", payload)
+	require.NoError(t, err)
+	require.Equal(t, "bot", string(botID))
+
+	var got struct {
+		Event string          `json:"event"`
+		BotID string          `json:"botId"`
+		Data  ThreadCompleted `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &got))
+	require.Equal(t, "thread_completed", got.Event)
+	require.Equal(t, "bot", got.BotID)
+	require.Equal(t, map[string]string{
+		"ФИО":    "Иванов Иван",
+		"Группа": "ИУ7",
+		"3":      "Да",
+		"10":     "Нет",
+	}, got.Data.Answers)
+}
+
+func TestBodyFromEvent_NoBotID(t *testing.T) {
+	_, _, err := bodyFromEvent("bot_enabled", []byte(`{"enabledAt": "2024-01-01T00:00:00Z"}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/safehttp"
+)
+
+const (
+	HeaderEvent     = "X-ItsReg-Event"
+	HeaderDelivery  = "X-ItsReg-Delivery"
+	HeaderSignature = "X-ItsReg-Signature"
+
+	// signaturePrefix указывает алгоритм подписи в заголовке HeaderSignature.
+	signaturePrefix = "sha256="
+	// sendTimeout ограничивает время ожидания ответа получателя.
+	sendTimeout = 10 * time.Second
+	// maxResponseBody ограничивает объём тела ответа, вычитываемого для переиспользования соединения.
+	maxResponseBody = 64 << 10
+)
+
+// Sender отправляет события POST-запросами с JSON-телом. Тело подписывается HMAC-SHA256 секретом
+// Webhook, подпись передаётся в заголовке HeaderSignature в виде "sha256=<hex>".
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender возвращает Sender, который не отправляет события во внутренние сети (см. safehttp).
+func NewSender() *Sender {
+	return NewSenderWithClient(safehttp.NewClient(sendTimeout))
+}
+
+// NewSenderWithClient возвращает Sender, отправляющий события клиентом client.
+func NewSenderWithClient(client *http.Client) *Sender {
+	return &Sender{
+		client: client,
+	}
+}
+
+func (s *Sender) Send(
+	ctx context.Context,
+	url string,
+	secret string,
+	event string,
+	deliveryID int64,
+	payload []byte,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "itsreg-bots-webhooks")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает значение заголовка HeaderSignature для тела payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
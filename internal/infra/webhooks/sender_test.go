@@ -0,0 +1,70 @@
+package webhooks_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/internal/infra/webhooks"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/safehttp"
+)
+
+func TestSender_Send(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"event":"thread_completed","botId":"bot","data":{}}`)
+
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	sender := webhooks.NewSenderWithClient(srv.Client())
+	code, err := sender.Send(context.Background(), srv.URL, secret, "thread_completed", 7, payload)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, code)
+
+	require.Equal(t, http.MethodPost, got.Method)
+	require.Equal(t, payload, gotBody)
+	require.Equal(t, "thread_completed", got.Header.Get(webhooks.HeaderEvent))
+	require.Equal(t, "7", got.Header.Get(webhooks.HeaderDelivery))
+	require.Equal(t, webhooks.Sign(secret, payload), got.Header.Get(webhooks.HeaderSignature))
+}
+
+func TestSender_Send_UnexpectedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	sender := webhooks.NewSenderWithClient(srv.Client())
+	code, err := sender.Send(context.Background(), srv.URL, "0123456789abcdef", "bot_enabled", 1, []byte("{}"))
+	require.Error(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestSender_Send_InternalAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	sender := webhooks.NewSender()
+	_, err := sender.Send(context.Background(), srv.URL, "0123456789abcdef", "bot_enabled", 1, []byte("{}"))
+	require.ErrorIs(t, err, safehttp.ErrForbiddenAddress)
+}
+
+func TestSign(t *testing.T) {
+	// Контрольное значение: echo -n '{}' | openssl dgst -sha256 -hmac 0123456789abcdef
+	require.Equal(t,
+		"sha256=f91e3e9f05cc2df64ac1c26f8adccdffda8d1e4a7a8c50a1a08eeadac6ddfec5",
+		webhooks.Sign("0123456789abcdef", []byte("{}")),
+	)
+}
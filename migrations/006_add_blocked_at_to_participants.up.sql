@@ -0,0 +1,2 @@
+ALTER TABLE participants
+    ADD COLUMN IF NOT EXISTS blocked_at TIMESTAMPTZ DEFAULT NULL;
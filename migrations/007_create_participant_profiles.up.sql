@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS participant_profiles (
+    bot_id          VARCHAR     NOT NULL,
+    user_id         BIGINT      NOT NULL,
+    username        VARCHAR     NOT NULL DEFAULT '',
+    first_name      VARCHAR     NOT NULL DEFAULT '',
+    last_name       VARCHAR     NOT NULL DEFAULT '',
+    language_code   VARCHAR     NOT NULL DEFAULT '',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (bot_id, user_id),
+
+    FOREIGN KEY (bot_id, user_id)
+        REFERENCES participants (bot_id, user_id)
+        ON DELETE CASCADE
+);
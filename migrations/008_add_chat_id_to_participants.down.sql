@@ -0,0 +1,36 @@
+-- Участники групповых чатов не могут быть представлены в старой схеме и удаляются.
+DELETE FROM participant_profiles WHERE chat_id <> user_id;
+DELETE FROM threads WHERE chat_id <> user_id;
+DELETE FROM participants WHERE chat_id <> user_id;
+
+ALTER TABLE threads
+    DROP CONSTRAINT IF EXISTS threads_bot_id_chat_id_user_id_fkey;
+ALTER TABLE participant_profiles
+    DROP CONSTRAINT IF EXISTS participant_profiles_bot_id_chat_id_user_id_fkey;
+ALTER TABLE participant_profiles
+    DROP CONSTRAINT IF EXISTS participant_profiles_pkey;
+ALTER TABLE participants
+    DROP CONSTRAINT IF EXISTS participants_pkey;
+
+ALTER TABLE participants
+    ADD PRIMARY KEY (bot_id, user_id);
+ALTER TABLE participant_profiles
+    ADD PRIMARY KEY (bot_id, user_id);
+
+ALTER TABLE threads
+    ADD FOREIGN KEY (bot_id, user_id)
+        REFERENCES participants (bot_id, user_id);
+ALTER TABLE participant_profiles
+    ADD FOREIGN KEY (bot_id, user_id)
+        REFERENCES participants (bot_id, user_id)
+        ON DELETE CASCADE;
+
+ALTER TABLE participant_profiles
+    DROP COLUMN IF EXISTS chat_id;
+ALTER TABLE threads
+    DROP COLUMN IF EXISTS chat_id;
+ALTER TABLE participants
+    DROP COLUMN IF EXISTS chat_id;
+
+ALTER TABLE bots
+    DROP COLUMN IF EXISTS chat_policy;
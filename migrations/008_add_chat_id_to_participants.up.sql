@@ -0,0 +1,43 @@
+ALTER TABLE bots
+    ADD COLUMN IF NOT EXISTS chat_policy VARCHAR NOT NULL DEFAULT 'private';
+
+-- Для личных чатов chat_id совпадает с user_id, поэтому существующие записи заполняются им.
+ALTER TABLE participants
+    ADD COLUMN IF NOT EXISTS chat_id BIGINT;
+UPDATE participants SET chat_id = user_id WHERE chat_id IS NULL;
+ALTER TABLE participants
+    ALTER COLUMN chat_id SET NOT NULL;
+
+ALTER TABLE threads
+    ADD COLUMN IF NOT EXISTS chat_id BIGINT;
+UPDATE threads SET chat_id = user_id WHERE chat_id IS NULL;
+ALTER TABLE threads
+    ALTER COLUMN chat_id SET NOT NULL;
+
+ALTER TABLE participant_profiles
+    ADD COLUMN IF NOT EXISTS chat_id BIGINT;
+UPDATE participant_profiles SET chat_id = user_id WHERE chat_id IS NULL;
+ALTER TABLE participant_profiles
+    ALTER COLUMN chat_id SET NOT NULL;
+
+ALTER TABLE threads
+    DROP CONSTRAINT IF EXISTS threads_bot_id_user_id_fkey;
+ALTER TABLE participant_profiles
+    DROP CONSTRAINT IF EXISTS participant_profiles_bot_id_user_id_fkey;
+ALTER TABLE participant_profiles
+    DROP CONSTRAINT IF EXISTS participant_profiles_pkey;
+ALTER TABLE participants
+    DROP CONSTRAINT IF EXISTS participants_pkey;
+
+ALTER TABLE participants
+    ADD PRIMARY KEY (bot_id, chat_id, user_id);
+ALTER TABLE participant_profiles
+    ADD PRIMARY KEY (bot_id, chat_id, user_id);
+
+ALTER TABLE threads
+    ADD FOREIGN KEY (bot_id, chat_id, user_id)
+        REFERENCES participants (bot_id, chat_id, user_id);
+ALTER TABLE participant_profiles
+    ADD FOREIGN KEY (bot_id, chat_id, user_id)
+        REFERENCES participants (bot_id, chat_id, user_id)
+        ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS bot_leases (
+    bot_id      VARCHAR     PRIMARY KEY,
+    replica     VARCHAR     NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+
+    FOREIGN KEY (bot_id)
+        REFERENCES bots (id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS bot_update_offsets;
+
+ALTER TABLE participants
+    DROP COLUMN IF EXISTS last_update_id;
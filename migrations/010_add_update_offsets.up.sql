@@ -0,0 +1,12 @@
+ALTER TABLE participants
+    ADD COLUMN IF NOT EXISTS last_update_id BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS bot_update_offsets (
+    bot_id      VARCHAR     PRIMARY KEY,
+    update_id   BIGINT      NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (bot_id)
+        REFERENCES bots (id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id              BIGSERIAL   PRIMARY KEY,
+    bot_id          VARCHAR     NOT NULL,
+    chat_id         BIGINT      NOT NULL,
+    user_id         BIGINT      NOT NULL,
+    text            TEXT        NOT NULL,
+    options         TEXT[]      NOT NULL DEFAULT '{}',
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until    TIMESTAMPTZ,
+    failed_at       TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (bot_id)
+        REFERENCES bots (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx
+    ON outbox_messages (bot_id, chat_id, id)
+    WHERE failed_at IS NULL;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id          VARCHAR     NOT NULL,
+    bot_id      VARCHAR     NOT NULL,
+    url         TEXT        NOT NULL,
+    secret      TEXT        NOT NULL,
+    events      TEXT[]      NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (bot_id, id),
+    FOREIGN KEY (bot_id)
+        REFERENCES bots (id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGSERIAL   PRIMARY KEY,
+    bot_id          VARCHAR     NOT NULL,
+    webhook_id      VARCHAR     NOT NULL,
+    event           VARCHAR     NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          VARCHAR     NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    response_code   INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until    TIMESTAMPTZ,
+    delivered_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (bot_id, webhook_id)
+        REFERENCES webhooks (bot_id, id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx
+    ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx
+    ON webhook_deliveries (bot_id, webhook_id, id);
@@ -0,0 +1,4 @@
+ALTER TABLE threads
+    DROP COLUMN IF EXISTS vars;
+
+DROP TABLE IF EXISTS node_requests;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS node_requests (
+    bot_id      VARCHAR     NOT NULL,
+    state       INTEGER     NOT NULL,
+    method      VARCHAR     NOT NULL,
+    url         TEXT        NOT NULL,
+    headers     JSONB       NOT NULL DEFAULT '{}',
+    body        TEXT        NOT NULL DEFAULT '',
+    save        JSONB       NOT NULL DEFAULT '{}',
+    on_success  INTEGER     NOT NULL,
+    on_failure  INTEGER     NOT NULL,
+
+    PRIMARY KEY (bot_id, state),
+
+    FOREIGN KEY (bot_id, state)
+        REFERENCES nodes (bot_id, state)
+        ON DELETE CASCADE
+);
+
+ALTER TABLE threads
+    ADD COLUMN IF NOT EXISTS vars JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,14 @@
+-- Откат возможен, только пока авторы всех ботов - прежние числовые ID: субъект JWT не приводится к BIGINT,
+-- и откат потерял бы владельцев ботов.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM bots WHERE author !~ '^legacy:[0-9]+$') THEN
+        RAISE EXCEPTION 'bots contain non-legacy authors';
+    END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS bots_author_idx;
+
+ALTER TABLE bots
+    ALTER COLUMN author TYPE BIGINT USING substring(author FROM 8)::BIGINT;
@@ -0,0 +1,7 @@
+-- Автор бота - субъект JWT платформы, а не числовой ID.
+-- Существующие боты сохраняют прежний ID с префиксом legacy:, чтобы он не совпал с числовым субъектом JWT;
+-- их авторов переназначает команда backfill-authors по таблице соответствия прежних ID субъектам (см. README).
+ALTER TABLE bots
+    ALTER COLUMN author TYPE VARCHAR USING 'legacy:' || author::VARCHAR;
+
+CREATE INDEX IF NOT EXISTS bots_author_idx ON bots (author);
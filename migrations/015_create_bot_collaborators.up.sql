@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS bot_collaborators (
+    bot_id      VARCHAR     NOT NULL,
+    account_id  VARCHAR     NOT NULL,
+    role        VARCHAR     NOT NULL,
+    added_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (bot_id, account_id),
+    FOREIGN KEY (bot_id)
+        REFERENCES bots (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS bot_collaborators_account_idx
+    ON bot_collaborators (account_id);
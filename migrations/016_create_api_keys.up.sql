@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id              VARCHAR     PRIMARY KEY,
+    account_id      VARCHAR     NOT NULL,
+    name            VARCHAR     NOT NULL,
+    hash            VARCHAR(64) NOT NULL,
+    bots            TEXT[]      NOT NULL DEFAULT '{}',
+    actions         TEXT[]      NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    last_used_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_account_idx
+    ON api_keys (account_id);
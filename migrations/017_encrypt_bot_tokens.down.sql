@@ -0,0 +1,13 @@
+-- Откат потерял бы зашифрованные токены, поэтому он возможен, только пока все токены хранятся открыто.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM bots WHERE token_key_id IS NOT NULL) THEN
+        RAISE EXCEPTION 'bots contain encrypted tokens';
+    END IF;
+END
+$$;
+
+ALTER TABLE bots
+    DROP COLUMN IF EXISTS token_key_id,
+    DROP COLUMN IF EXISTS token_data_key,
+    DROP COLUMN IF EXISTS token_ciphertext;
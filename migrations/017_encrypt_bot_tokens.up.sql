@@ -0,0 +1,8 @@
+-- Токен бота хранится зашифрованным конвертным шифрованием: token_data_key есть ключ данных,
+-- зашифрованный ключом token_key_id, token_ciphertext - токен, зашифрованный ключом данных.
+-- Для зашифрованных токенов token пуст; строки с token_key_id IS NULL хранят токен открыто
+-- до перешифрования.
+ALTER TABLE bots
+    ADD COLUMN IF NOT EXISTS token_key_id     VARCHAR,
+    ADD COLUMN IF NOT EXISTS token_data_key   BYTEA,
+    ADD COLUMN IF NOT EXISTS token_ciphertext BYTEA;
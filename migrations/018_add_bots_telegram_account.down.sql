@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS bots_telegram_id_key;
+
+ALTER TABLE bots
+    DROP COLUMN IF EXISTS telegram_id,
+    DROP COLUMN IF EXISTS telegram_username;
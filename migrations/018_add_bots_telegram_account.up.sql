@@ -0,0 +1,9 @@
+-- Аккаунт Telegram, которому принадлежит токен бота (по данным getMe). Пуст у ботов, сохранённых
+-- до проверки токенов. Один аккаунт Telegram может использовать только один неудалённый бот.
+ALTER TABLE bots
+    ADD COLUMN IF NOT EXISTS telegram_id       BIGINT,
+    ADD COLUMN IF NOT EXISTS telegram_username VARCHAR;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bots_telegram_id_key
+    ON bots (telegram_id)
+    WHERE deleted_at IS NULL;
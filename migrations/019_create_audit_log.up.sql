@@ -0,0 +1,13 @@
+-- Журнал аудита команд пользователей. Не ссылается на bots, чтобы пережить удаление бота.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id          BIGSERIAL   PRIMARY KEY,
+    actor       VARCHAR     NOT NULL,
+    action      VARCHAR     NOT NULL,
+    bot_id      VARCHAR,
+    payload     JSONB       NOT NULL,
+    error       VARCHAR,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_bot_idx
+    ON audit_log (bot_id, id DESC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS threads_bot_started_at_idx;
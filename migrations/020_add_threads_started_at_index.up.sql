@@ -0,0 +1,3 @@
+-- Постраничная выборка тредов бота упорядочена по времени начала и ID.
+CREATE INDEX IF NOT EXISTS threads_bot_started_at_idx
+    ON threads (bot_id, started_at, id);
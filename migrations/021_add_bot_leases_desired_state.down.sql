@@ -0,0 +1,4 @@
+ALTER TABLE bot_leases
+    DROP COLUMN IF EXISTS applied_generation,
+    DROP COLUMN IF EXISTS generation,
+    DROP COLUMN IF EXISTS stop_requested;
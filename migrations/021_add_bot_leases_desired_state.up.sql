@@ -0,0 +1,7 @@
+-- Желаемое состояние экземпляра бота, которое применяет реплика-владелец аренды:
+-- stop_requested - остановить бота; generation, превышающий applied_generation, - перезапустить
+-- бота с токеном из таблицы bots.
+ALTER TABLE bot_leases
+    ADD COLUMN IF NOT EXISTS stop_requested     BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS generation         BIGINT  NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS applied_generation BIGINT  NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE bot_leases
+    DROP COLUMN IF EXISTS renewed_at;
@@ -0,0 +1,3 @@
+-- Время последнего продления аренды показывается в статусе бота как heartbeat реплики-владельца.
+ALTER TABLE bot_leases
+    ADD COLUMN IF NOT EXISTS renewed_at TIMESTAMPTZ NOT NULL DEFAULT now();
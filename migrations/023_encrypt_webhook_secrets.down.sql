@@ -0,0 +1,13 @@
+-- Откат потерял бы зашифрованные секреты, поэтому он возможен, только пока все секреты хранятся открыто.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM webhooks WHERE secret_key_id IS NOT NULL) THEN
+        RAISE EXCEPTION 'webhooks contain encrypted secrets';
+    END IF;
+END
+$$;
+
+ALTER TABLE webhooks
+    DROP COLUMN IF EXISTS secret_key_id,
+    DROP COLUMN IF EXISTS secret_data_key,
+    DROP COLUMN IF EXISTS secret_ciphertext;
@@ -0,0 +1,6 @@
+-- Секрет Webhook хранится зашифрованным так же, как токен бота (017_encrypt_bot_tokens): для зашифрованных
+-- секретов secret пуст; строки с secret_key_id IS NULL хранят секрет открыто до перешифрования.
+ALTER TABLE webhooks
+    ADD COLUMN IF NOT EXISTS secret_key_id     VARCHAR,
+    ADD COLUMN IF NOT EXISTS secret_data_key   BYTEA,
+    ADD COLUMN IF NOT EXISTS secret_ciphertext BYTEA;
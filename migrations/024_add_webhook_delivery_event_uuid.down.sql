@@ -0,0 +1,5 @@
+ALTER TABLE webhook_deliveries
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_webhook_id_event_uuid_key;
+
+ALTER TABLE webhook_deliveries
+    DROP COLUMN IF EXISTS event_uuid;
@@ -0,0 +1,7 @@
+-- UUID сообщения Watermill, из которого поставлена доставка: повторно доставленное событие не создаёт дублей.
+-- Строки, поставленные до миграции, хранят NULL и ограничению не мешают.
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS event_uuid VARCHAR;
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_event_uuid_key UNIQUE (webhook_id, event_uuid);
@@ -0,0 +1,106 @@
+package decorator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AuditEntry есть запись журнала аудита о команде, выполненной пользователем.
+type AuditEntry struct {
+	Actor  string
+	Action string
+	// BotID пуст для команд, не относящихся к боту (например, выпуск API-ключа).
+	BotID string
+	// Payload есть JSON команды без секретов (см. Redacter).
+	Payload []byte
+	// Error пуст, если команда выполнена успешно.
+	Error string
+	At    time.Time
+}
+
+type AuditRecorder interface {
+	RecordAudit(ctx context.Context, entry AuditEntry) error
+}
+
+// Auditable реализуют команды, выполняемые пользователями: они записываются в журнал аудита.
+type Auditable interface {
+	// AuditTarget возвращает пользователя, выполняющего команду, и бот, к которому она относится.
+	AuditTarget() (actor string, botID string)
+}
+
+// Unaudited реализуют команды, которые намеренно не записываются в журнал аудита, потому что их
+// выполняет не пользователь (обработка обновлений Telegram, доставка сообщений, фоновые задачи).
+// Каждая команда реализует либо Auditable, либо Unaudited, иначе ApplyCommandDecorators паникует.
+type Unaudited interface {
+	Unaudited()
+}
+
+// checkAuditable паникует, если команда C не реализует ни Auditable, ни Unaudited: так новая команда
+// не может остаться вне журнала аудита незаметно.
+func checkAuditable[C any]() {
+	var cmd C
+	switch any(cmd).(type) {
+	case Auditable, Unaudited:
+	default:
+		panic(fmt.Sprintf("command %T implements neither decorator.Auditable nor decorator.Unaudited", cmd))
+	}
+}
+
+type commandAuditDecorator[C any] struct {
+	base     CommandHandler[C]
+	recorder AuditRecorder
+	logger   *slog.Logger
+}
+
+// Handle записывает в журнал и успешные, и отклонённые команды. Ошибка записи в журнал не отменяет
+// результат уже выполненной команды и только логируется.
+func (d commandAuditDecorator[C]) Handle(ctx context.Context, cmd C) error {
+	a, isAuditable := any(cmd).(Auditable)
+	if !isAuditable {
+		return d.base.Handle(ctx, cmd)
+	}
+
+	err := d.base.Handle(ctx, cmd)
+
+	actor, botID := a.AuditTarget()
+	entry := AuditEntry{
+		Actor:  actor,
+		Action: auditActionName(cmd),
+		BotID:  botID,
+		At:     time.Now().Truncate(time.Millisecond),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	var body any = cmd
+	if r, isRedacter := body.(Redacter); isRedacter {
+		body = r.Redact()
+	}
+	payload, mErr := json.Marshal(body)
+	if mErr != nil {
+		d.logger.ErrorContext(ctx, "failed to marshal audit payload", slog.String("error", mErr.Error()))
+		payload = []byte("null")
+	}
+	entry.Payload = payload
+
+	// Команда уже выполнена, поэтому запись не должна прерываться отменой запроса
+	if rErr := d.recorder.RecordAudit(context.WithoutCancel(ctx), entry); rErr != nil {
+		d.logger.ErrorContext(ctx, "failed to record audit entry",
+			slog.String("action", entry.Action),
+			slog.String("actor", entry.Actor),
+			slog.String("bot_id", entry.BotID),
+			slog.String("error", rErr.Error()),
+		)
+	}
+	return err
+}
+
+// auditActionName возвращает имя действия без суффикса Command, например DeleteBot.
+func auditActionName(cmd any) string {
+	return strings.TrimSuffix(generateActionName(cmd), "Command")
+}
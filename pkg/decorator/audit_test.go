@@ -0,0 +1,58 @@
+package decorator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+	"github.com/bmstu-itstech/itsreg-bots/pkg/logs"
+)
+
+type memAudit []decorator.AuditEntry
+
+func (m *memAudit) RecordAudit(_ context.Context, entry decorator.AuditEntry) error {
+	*m = append(*m, entry)
+	return nil
+}
+
+type serviceCommand struct{}
+
+func (serviceCommand) Unaudited() {}
+
+type unmarkedCommand struct{}
+
+type nopHandler[C any] struct{}
+
+func (nopHandler[C]) Handle(context.Context, C) error { return nil }
+
+func TestApplyCommandDecorators_Audits(t *testing.T) {
+	audit := &memAudit{}
+	h := decorator.ApplyCommandDecorators[secretCommand](handler{}, logs.DefaultLogger(), noOpMetrics{}, audit)
+	require.NoError(t, h.Handle(context.Background(), secretCommand{Name: "bot", Secret: "123456:secret"}))
+
+	require.Len(t, *audit, 1)
+	entry := (*audit)[0]
+	require.Equal(t, "user", entry.Actor)
+	require.Equal(t, "bot", entry.BotID)
+	require.Equal(t, "secret", entry.Action)
+	require.NotContains(t, string(entry.Payload), "123456:secret")
+}
+
+func TestApplyCommandDecorators_SkipsUnaudited(t *testing.T) {
+	audit := &memAudit{}
+	h := decorator.ApplyCommandDecorators[serviceCommand](
+		nopHandler[serviceCommand]{}, logs.DefaultLogger(), noOpMetrics{}, audit,
+	)
+	require.NoError(t, h.Handle(context.Background(), serviceCommand{}))
+	require.Empty(t, *audit)
+}
+
+func TestApplyCommandDecorators_RejectsUnmarkedCommands(t *testing.T) {
+	require.Panics(t, func() {
+		decorator.ApplyCommandDecorators[unmarkedCommand](
+			nopHandler[unmarkedCommand]{}, logs.DefaultLogger(), noOpMetrics{}, &memAudit{},
+		)
+	})
+}
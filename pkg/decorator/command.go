@@ -7,14 +7,22 @@ import (
 	"strings"
 )
 
+// ApplyCommandDecorators дополняет обработчик журналированием, метриками и записью команд, выполняемых
+// пользователями, в журнал аудита (см. Auditable и Unaudited).
 func ApplyCommandDecorators[H any](
 	handler CommandHandler[H],
 	logger *slog.Logger,
 	metricsClient MetricsClient,
+	auditRecorder AuditRecorder,
 ) CommandHandler[H] {
+	checkAuditable[H]()
 	return commandLoggingDecorator[H]{
 		base: commandMetricsDecorator[H]{
-			base:   handler,
+			base: commandAuditDecorator[H]{
+				base:     handler,
+				recorder: auditRecorder,
+				logger:   logger,
+			},
 			client: metricsClient,
 		},
 		logger: logger,
@@ -6,6 +6,19 @@ import (
 	"log/slog"
 )
 
+// Redacter реализуют команды и запросы с секретами (токенами, ключами): вместо них в журнал
+// записывается результат Redact.
+type Redacter interface {
+	Redact() any
+}
+
+func logBody(v any) string {
+	if r, ok := v.(Redacter); ok {
+		v = r.Redact()
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 type commandLoggingDecorator[C any] struct {
 	base   CommandHandler[C]
 	logger *slog.Logger
@@ -17,7 +30,7 @@ func (d commandLoggingDecorator[C]) Handle(ctx context.Context, cmd C) (err erro
 
 	logger := d.logger.With(
 		slog.String("command", handlerType),
-		slog.String("command_body", fmt.Sprintf("%v", cmd)),
+		slog.String("command_body", logBody(cmd)),
 	)
 
 	logger.DebugContext(ctx, "executing command")
@@ -41,7 +54,7 @@ type queryLoggingDecorator[C any, R any] struct {
 func (d queryLoggingDecorator[C, R]) Handle(ctx context.Context, cmd C) (_ R, err error) {
 	logger := d.logger.With(
 		slog.String("query", generateActionName(cmd)),
-		slog.String("query_body", fmt.Sprintf("%v", cmd)),
+		slog.String("query_body", logBody(cmd)),
 	)
 
 	logger.DebugContext(ctx, "executing query")
@@ -0,0 +1,46 @@
+package decorator_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/decorator"
+)
+
+type noOpMetrics struct{}
+
+func (noOpMetrics) Inc(string, int) {}
+
+type secretCommand struct {
+	Name   string
+	Secret string
+}
+
+func (c secretCommand) Redact() any {
+	c.Secret = "***"
+	return c
+}
+
+func (c secretCommand) AuditTarget() (string, string) {
+	return "user", c.Name
+}
+
+type handler struct{}
+
+func (handler) Handle(context.Context, secretCommand) error { return nil }
+
+func TestCommandLoggingDecorator_Redacts(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	h := decorator.ApplyCommandDecorators[secretCommand](handler{}, l, noOpMetrics{}, &memAudit{})
+	err := h.Handle(context.Background(), secretCommand{Name: "bot", Secret: "123456:secret"})
+	require.NoError(t, err)
+
+	require.Contains(t, buf.String(), "bot")
+	require.NotContains(t, buf.String(), "123456:secret")
+}
@@ -0,0 +1,54 @@
+package envelope
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyringFromEnv читает связку ключей из TOKEN_ENCRYPTION_KEYS или из файла TOKEN_ENCRYPTION_KEYS_FILE.
+// Ключи задаются как "id:base64", через запятую в переменной или по одному на строку в файле;
+// активным считается первый ключ. Если ни одна переменная не задана, возвращается nil.
+func KeyringFromEnv() (*Keyring, error) {
+	raw := os.Getenv("TOKEN_ENCRYPTION_KEYS")
+	if path := os.Getenv("TOKEN_ENCRYPTION_KEYS_FILE"); path != "" {
+		if raw != "" {
+			return nil, errors.New("only one of TOKEN_ENCRYPTION_KEYS and TOKEN_ENCRYPTION_KEYS_FILE must be set")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading TOKEN_ENCRYPTION_KEYS_FILE: %w", err)
+		}
+		raw = strings.ReplaceAll(string(data), "\n", ",")
+	}
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil //nolint:nilnil // шифрование не настроено
+	}
+
+	var active string
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("invalid token encryption key: expected \"id:base64\"")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token encryption key %q: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("duplicate token encryption key %q", id)
+		}
+		if active == "" {
+			active = id
+		}
+		keys[id] = key
+	}
+	return NewKeyring(active, keys)
+}
@@ -0,0 +1,138 @@
+// Package envelope реализует конвертное шифрование: каждое значение шифруется собственным случайным
+// ключом данных (DEK), а ключ данных - ключом шифрования ключей (KEK) из связки. Идентификатор KEK
+// хранится рядом с шифротекстом, поэтому после ротации ключей старые значения остаются читаемыми,
+// пока не будут перешифрованы активным ключом.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeySize есть размер ключа шифрования ключей: используется AES-256.
+const KeySize = 32
+
+var (
+	// ErrUnknownKey возвращается, если значение зашифровано ключом, которого нет в связке.
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrDecrypt возвращается, если значение не удалось расшифровать: данные или ключ повреждены.
+	ErrDecrypt = errors.New("decryption failed")
+)
+
+// Sealed есть значение, зашифрованное конвертным шифрованием.
+type Sealed struct {
+	// KeyID есть идентификатор KEK, которым зашифрован DataKey.
+	KeyID string
+	// DataKey есть зашифрованный ключ данных.
+	DataKey []byte
+	// Ciphertext есть значение, зашифрованное ключом данных.
+	Ciphertext []byte
+}
+
+// Keyring есть связка ключей шифрования ключей. Новые значения шифруются активным ключом,
+// остальные ключи используются только для расшифровки.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyring создаёт связку с активным ключом active. Ключ active должен быть в keys.
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no encryption keys")
+	}
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active encryption key %q not found", active)
+	}
+
+	ks := make(map[string][]byte, len(keys))
+	for id, k := range keys {
+		if id == "" {
+			return nil, errors.New("empty encryption key id")
+		}
+		if len(k) != KeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes, got %d", id, KeySize, len(k))
+		}
+		ks[id] = append([]byte(nil), k...)
+	}
+	return &Keyring{active: active, keys: ks}, nil
+}
+
+// ActiveKeyID возвращает идентификатор ключа, которым шифруются новые значения.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Seal шифрует plaintext новым ключом данных под активным ключом связки. Дополнительные данные aad
+// (например, идентификатор записи) не шифруются, но привязывают шифротекст к записи: расшифровать
+// его с другими aad нельзя.
+func (k *Keyring) Seal(plaintext []byte, aad []byte) (Sealed, error) {
+	dek := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return Sealed{}, fmt.Errorf("generating data key: %w", err)
+	}
+
+	ciphertext, err := seal(dek, plaintext, aad)
+	if err != nil {
+		return Sealed{}, err
+	}
+	dataKey, err := seal(k.keys[k.active], dek, []byte(k.active))
+	if err != nil {
+		return Sealed{}, err
+	}
+	return Sealed{KeyID: k.active, DataKey: dataKey, Ciphertext: ciphertext}, nil
+}
+
+// Open расшифровывает значение, зашифрованное Seal с теми же aad.
+func (k *Keyring) Open(s Sealed, aad []byte) ([]byte, error) {
+	kek, ok := k.keys[s.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, s.KeyID)
+	}
+	dek, err := open(kek, s.DataKey, []byte(s.KeyID))
+	if err != nil {
+		return nil, err
+	}
+	return open(dek, s.Ciphertext, aad)
+}
+
+// seal шифрует plaintext в AES-GCM и возвращает nonce вместе с шифротекстом.
+func seal(key []byte, plaintext []byte, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key []byte, data []byte, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
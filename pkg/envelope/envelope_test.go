@@ -0,0 +1,76 @@
+package envelope_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/envelope"
+)
+
+var (
+	oldKey = bytes.Repeat([]byte{1}, envelope.KeySize)
+	newKey = bytes.Repeat([]byte{2}, envelope.KeySize)
+)
+
+func TestKeyring_SealOpen(t *testing.T) {
+	old, err := envelope.NewKeyring("old", map[string][]byte{"old": oldKey})
+	require.NoError(t, err)
+
+	s, err := old.Seal([]byte("123456:secret"), []byte("bot"))
+	require.NoError(t, err)
+	require.Equal(t, "old", s.KeyID)
+	require.NotContains(t, string(s.Ciphertext), "secret")
+
+	plain, err := old.Open(s, []byte("bot"))
+	require.NoError(t, err)
+	require.Equal(t, "123456:secret", string(plain))
+
+	// Шифротекст привязан к записи
+	_, err = old.Open(s, []byte("other"))
+	require.ErrorIs(t, err, envelope.ErrDecrypt)
+
+	// После ротации старые значения читаются, новые шифруются новым ключом
+	rotated, err := envelope.NewKeyring("new", map[string][]byte{"new": newKey, "old": oldKey})
+	require.NoError(t, err)
+	plain, err = rotated.Open(s, []byte("bot"))
+	require.NoError(t, err)
+	require.Equal(t, "123456:secret", string(plain))
+
+	s2, err := rotated.Seal(plain, []byte("bot"))
+	require.NoError(t, err)
+	require.Equal(t, "new", s2.KeyID)
+	_, err = old.Open(s2, []byte("bot"))
+	require.ErrorIs(t, err, envelope.ErrUnknownKey)
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := envelope.NewKeyring("a", nil)
+	require.Error(t, err)
+
+	_, err = envelope.NewKeyring("b", map[string][]byte{"a": oldKey})
+	require.Error(t, err)
+
+	_, err = envelope.NewKeyring("a", map[string][]byte{"a": []byte("short")})
+	require.Error(t, err)
+}
+
+func TestKeyringFromEnv(t *testing.T) {
+	t.Setenv("TOKEN_ENCRYPTION_KEYS", "")
+	t.Setenv("TOKEN_ENCRYPTION_KEYS_FILE", "")
+	k, err := envelope.KeyringFromEnv()
+	require.NoError(t, err)
+	require.Nil(t, k)
+
+	t.Setenv("TOKEN_ENCRYPTION_KEYS",
+		"new:"+base64.StdEncoding.EncodeToString(newKey)+", old:"+base64.StdEncoding.EncodeToString(oldKey))
+	k, err = envelope.KeyringFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, "new", k.ActiveKeyID())
+
+	t.Setenv("TOKEN_ENCRYPTION_KEYS", "new")
+	_, err = envelope.KeyringFromEnv()
+	require.Error(t, err)
+}
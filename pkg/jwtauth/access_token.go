@@ -10,6 +10,16 @@ type accessTokenClaims struct {
 	UserUUID string `json:"user_uuid"`
 }
 
+// subject возвращает идентификатор пользователя токена: claim sub как есть, а для токенов, выпущенных
+// до его появления, - user_uuid. Субъект не сопоставляется с прежними числовыми ID авторов ботов:
+// их боты хранятся с префиксом legacy: до переназначения командой backfill-authors.
+func (c accessTokenClaims) subject() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	return c.UserUUID
+}
+
 type AccessTokenPayload struct {
 	UserUUID string
 }
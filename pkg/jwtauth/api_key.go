@@ -0,0 +1,23 @@
+package jwtauth
+
+import (
+	"context"
+	"errors"
+)
+
+// APIKeyHeader есть заголовок, в котором клиент передаёт API-ключ вместо JWT.
+const APIKeyHeader = "X-API-Key"
+
+// ErrInvalidAPIKey возвращается APIKeyVerifier для неизвестного, неверного или истёкшего ключа.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// Scope ограничивает доступ запроса, выполненного по API-ключу.
+type Scope struct {
+	Bots    []string // Пустой список означает все боты пользователя
+	Actions []string
+}
+
+// APIKeyVerifier проверяет API-ключ и возвращает идентификатор его владельца и ограничения ключа.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (string, Scope, error)
+}
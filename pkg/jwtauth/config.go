@@ -0,0 +1,96 @@
+package jwtauth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+// defaultJWKSRefreshInterval задаёт, как долго используется загруженный набор ключей JWKS.
+const defaultJWKSRefreshInterval = 10 * time.Minute
+
+// Config описывает, какие JWT принимаются.
+type Config struct {
+	// Secret есть ключ подписи HS256. Пуст, если такие токены не принимаются.
+	Secret []byte
+	// JWKS есть источник открытых ключей для RS256 и ES256. Равен nil, если такие токены не принимаются.
+	JWKS *JWKS
+	// Issuer, если не пуст, должен совпадать с claim iss токена.
+	Issuer string
+	// Audience, если не пуст, должен содержаться в claim aud токена.
+	Audience string
+	// Algorithms перечисляет разрешённые алгоритмы подписи. Токены с другим alg отклоняются.
+	Algorithms []string
+}
+
+// ConfigFromEnv читает конфигурацию из переменных окружения:
+//   - JWT_SECRET - ключ подписи HS256;
+//   - JWT_JWKS - путь к файлу или http(s) URL документа JWKS;
+//   - JWT_JWKS_REFRESH_INTERVAL - период перезагрузки JWKS (по умолчанию 10m);
+//   - JWT_ISSUER, JWT_AUDIENCE - ожидаемые iss и aud;
+//   - JWT_ALGORITHMS - разрешённые алгоритмы через запятую. По умолчанию HS256, если задан
+//     JWT_SECRET, и RS256, ES256, если задан JWT_JWKS.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Secret:   []byte(os.Getenv("JWT_SECRET")),
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+	}
+
+	if source := os.Getenv("JWT_JWKS"); source != "" {
+		refresh := defaultJWKSRefreshInterval
+		if s := os.Getenv("JWT_JWKS_REFRESH_INTERVAL"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil || d <= 0 {
+				return Config{}, fmt.Errorf("JWT_JWKS_REFRESH_INTERVAL must be a positive duration, got %q", s)
+			}
+			refresh = d
+		}
+		cfg.JWKS = NewJWKS(source, refresh)
+	}
+
+	if s := os.Getenv("JWT_ALGORITHMS"); s != "" {
+		for _, alg := range strings.Split(s, ",") {
+			cfg.Algorithms = append(cfg.Algorithms, strings.TrimSpace(alg))
+		}
+	} else {
+		if len(cfg.Secret) > 0 {
+			cfg.Algorithms = append(cfg.Algorithms, AlgHS256)
+		}
+		if cfg.JWKS != nil {
+			cfg.Algorithms = append(cfg.Algorithms, AlgRS256, AlgES256)
+		}
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate проверяет, что для каждого разрешённого алгоритма задан ключ.
+func (c Config) Validate() error {
+	if len(c.Algorithms) == 0 {
+		return errors.New("no JWT algorithms allowed: JWT_SECRET or JWT_JWKS must be set")
+	}
+	for _, alg := range c.Algorithms {
+		switch alg {
+		case AlgHS256:
+			if len(c.Secret) == 0 {
+				return errors.New("HS256 is allowed but JWT_SECRET is not set")
+			}
+		case AlgRS256, AlgES256:
+			if c.JWKS == nil {
+				return fmt.Errorf("%s is allowed but JWT_JWKS is not set", alg)
+			}
+		default:
+			return fmt.Errorf("unsupported JWT algorithm %q", alg)
+		}
+	}
+	return nil
+}
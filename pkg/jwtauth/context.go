@@ -7,18 +7,33 @@ import (
 
 type ctxKey int
 
-const userCtxKey ctxKey = iota
+const (
+	subjectCtxKey ctxKey = iota
+	scopeCtxKey
+)
 
-func userUUIDToContext(ctx context.Context, userUUID string) context.Context {
-	return context.WithValue(ctx, userCtxKey, userUUID)
+func subjectToContext(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectCtxKey, subject)
 }
 
-var ErrNoUserUUIDInContext = errors.New("no user uuid in context")
+var ErrNoSubjectInContext = errors.New("no JWT subject in context")
 
-func UserUUIDFromContext(ctx context.Context) (string, error) {
-	u, ok := ctx.Value(userCtxKey).(string)
-	if !ok {
-		return "", ErrNoUserUUIDInContext
+// SubjectFromContext возвращает идентификатор пользователя, чей JWT-токен или API-ключ проверен
+// NewHTTPMiddleware.
+func SubjectFromContext(ctx context.Context) (string, error) {
+	s, ok := ctx.Value(subjectCtxKey).(string)
+	if !ok || s == "" {
+		return "", ErrNoSubjectInContext
 	}
-	return u, nil
+	return s, nil
+}
+
+func scopeToContext(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeCtxKey, scope)
+}
+
+// ScopeFromContext возвращает ограничения API-ключа, если запрос аутентифицирован ключом, а не JWT.
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(scopeCtxKey).(Scope)
+	return s, ok
 }
@@ -0,0 +1,277 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksUnknownKeyInterval ограничивает частоту внеплановых перезагрузок JWKS из-за токенов с неизвестным
+// kid, чтобы поддельные токены не превращались в поток запросов к SSO.
+const jwksUnknownKeyInterval = time.Minute
+
+// jwksFetchTimeout ограничивает время загрузки JWKS по URL.
+const jwksFetchTimeout = 10 * time.Second
+
+// jwksMaxSize ограничивает размер документа JWKS.
+const jwksMaxSize = 1 << 20
+
+var ErrUnknownKey = errors.New("unknown JWT signing key")
+
+// JWKS есть набор открытых ключей (RFC 7517), загружаемый из файла или по URL. Набор кешируется на
+// refreshInterval и перезагружается раньше, если токен подписан неизвестным ключом: так
+// подхватывается ротация ключей SSO. Если перезагрузка не удалась, используется прежний набор.
+// Загрузка идёт без блокировки набора, а одновременные запросы ждут одну общую загрузку.
+type JWKS struct {
+	source          string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mu       sync.Mutex
+	keys     map[string]jwk
+	loadedAt time.Time
+	// triedAt есть время последней попытки загрузки, в том числе неудачной.
+	triedAt time.Time
+	// loading закрывается по окончании текущей загрузки; nil, если загрузка не идёт.
+	loading chan struct{}
+	loadErr error
+}
+
+type jwk struct {
+	alg string
+	key crypto.PublicKey
+}
+
+func NewJWKS(source string, refreshInterval time.Duration) *JWKS {
+	return &JWKS{
+		source:          source,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// Key возвращает открытый ключ с идентификатором kid для алгоритма alg. Пустой kid допустим, только
+// если в наборе ровно один ключ.
+func (s *JWKS) Key(ctx context.Context, kid string, alg string) (crypto.PublicKey, error) {
+	keys, err := s.refresh(ctx, false)
+	if err != nil && keys == nil {
+		return nil, err
+	}
+
+	k, ok := lookup(keys, kid)
+	if !ok {
+		keys, err = s.refresh(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		k, ok = lookup(keys, kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownKey, kid)
+	}
+
+	if k.alg != "" && k.alg != alg {
+		return nil, fmt.Errorf("JWT signing key %q is for %s, not %s", kid, k.alg, alg)
+	}
+	return k.key, nil
+}
+
+func lookup(keys map[string]jwk, kid string) (jwk, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, k := range keys {
+			return k, true
+		}
+	}
+	k, ok := keys[kid]
+	return k, ok
+}
+
+// refresh перезагружает набор, если он устарел, а при unknownKey - если с последней попытки
+// прошло не меньше jwksUnknownKeyInterval. После неудачной попытки устаревший набор также
+// перезагружается не чаще раза в jwksUnknownKeyInterval. Возвращает текущий набор и ошибку
+// загрузки, если она была.
+func (s *JWKS) refresh(ctx context.Context, unknownKey bool) (map[string]jwk, error) {
+	s.mu.Lock()
+	if loading := s.loading; loading != nil {
+		s.mu.Unlock()
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.keys, s.loadErr
+	}
+	if !s.due(time.Now(), unknownKey) {
+		defer s.mu.Unlock()
+		return s.keys, nil
+	}
+	loading := make(chan struct{})
+	s.loading = loading
+	s.triedAt = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.load(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.keys = keys
+		s.loadedAt = time.Now()
+	}
+	s.loadErr = err
+	s.loading = nil
+	close(loading)
+	return s.keys, err
+}
+
+// due сообщает, пора ли загружать набор. Вызывается под s.mu.
+func (s *JWKS) due(now time.Time, unknownKey bool) bool {
+	if s.keys == nil {
+		return true
+	}
+	recentlyTried := now.Sub(s.triedAt) < jwksUnknownKeyInterval
+	if unknownKey {
+		return !recentlyTried
+	}
+	if now.Sub(s.loadedAt) < s.refreshInterval {
+		return false
+	}
+	return !(recentlyTried && s.triedAt.After(s.loadedAt))
+}
+
+func (s *JWKS) load(ctx context.Context) (map[string]jwk, error) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading JWKS: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *JWKS) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+}
+
+type jwkJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS разбирает документ JWKS. Ключи шифрования и ключи неподдерживаемых типов пропускаются:
+// SSO может публиковать их вместе с ключами подписи.
+func parseJWKS(data []byte) (map[string]jwk, error) {
+	var doc struct {
+		Keys []jwkJSON `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]jwk, len(doc.Keys))
+	for _, j := range doc.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch {
+		case j.Kty == "RSA" && (j.Alg == "" || j.Alg == AlgRS256):
+			key, err = parseRSAKey(j)
+		case j.Kty == "EC" && j.Crv == "P-256" && (j.Alg == "" || j.Alg == AlgES256):
+			key, err = parseP256Key(j)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", j.Kid, err)
+		}
+		keys[j.Kid] = jwk{alg: j.Alg, key: key}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no supported signing keys")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(j jwkJSON) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(j.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(j.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid e: %w", err)
+	}
+	exp := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA key")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+}
+
+func parseP256Key(j jwkJSON) (*ecdsa.PublicKey, error) {
+	const size = 32
+	x, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil || len(x) != size {
+		return nil, errors.New("invalid x")
+	}
+	y, err := base64.RawURLEncoding.DecodeString(j.Y)
+	if err != nil || len(y) != size {
+		return nil, errors.New("invalid y")
+	}
+
+	// ecdh проверяет, что точка лежит на кривой
+	point := append(append([]byte{4}, x...), y...)
+	if _, err = ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package jwtauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/jwtauth"
+)
+
+// jwksServer отдаёт набор из одного ключа и считает запросы. Если release не nil, ответ задерживается
+// до его закрытия, а о полученном запросе сообщается в started.
+func jwksServer(t *testing.T, kid string, release <-chan struct{}, started chan<- struct{}) (string, *atomic.Int32) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]any{"keys": []map[string]string{rsaJWK(kid, key)}})
+	require.NoError(t, err)
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		if release != nil {
+			started <- struct{}{}
+			<-release
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, &hits
+}
+
+func TestJWKS_UnknownKeyRateLimited(t *testing.T) {
+	url, hits := jwksServer(t, "rsa-1", nil, nil)
+	jwks := jwtauth.NewJWKS(url, time.Hour)
+
+	_, err := jwks.Key(context.Background(), "rsa-1", jwtauth.AlgRS256)
+	require.NoError(t, err)
+
+	for range 10 {
+		_, err = jwks.Key(context.Background(), "forged", jwtauth.AlgRS256)
+		require.ErrorIs(t, err, jwtauth.ErrUnknownKey)
+	}
+	require.Equal(t, int32(1), hits.Load())
+}
+
+func TestJWKS_FetchDoesNotBlockCallers(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	url, hits := jwksServer(t, "rsa-1", release, started)
+	jwks := jwtauth.NewJWKS(url, time.Hour)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := jwks.Key(context.Background(), "rsa-1", jwtauth.AlgRS256)
+			errs <- err
+		}()
+	}
+	<-started
+
+	// Пока идёт загрузка, вызов с истёкшим контекстом возвращается сразу, а не ждёт её на мьютексе
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := jwks.Key(ctx, "rsa-1", jwtauth.AlgRS256)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), hits.Load())
+}
@@ -5,38 +5,70 @@ import (
 	"net/http"
 
 	"github.com/go-chi/render"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang-jwt/jwt/v5/request"
 )
 
-func HTTPMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var claims accessTokenClaims
-		token, err := request.ParseFromRequest(
-			r,
-			request.MultiExtractor{
-				request.AuthorizationHeaderExtractor,
-				request.ArgumentExtractor{"jwtToken"},
-			},
-			func(_ *jwt.Token) (_ interface{}, _ error) {
-				return []byte(secret), nil
-			},
-			request.WithClaims(&claims),
-		)
-		if err != nil {
-			httpError(w, r, err, http.StatusUnauthorized)
-			return
-		}
-
-		if !token.Valid {
-			httpError(w, r, errors.New("invalid JWT token"), http.StatusBadRequest)
-			return
-		}
-
-		r = r.WithContext(userUUIDToContext(r.Context(), claims.UserUUID))
-
-		next.ServeHTTP(w, r)
-	})
+// NewHTTPMiddleware возвращает middleware, которое принимает JWT, проверенные tokens, а если keys
+// не nil, то и API-ключи в заголовке APIKeyHeader.
+func NewHTTPMiddleware(tokens *Verifier, keys APIKeyVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(APIKeyHeader); key != "" && keys != nil {
+				serveAPIKey(w, r, next, keys, key)
+				return
+			}
+			serveJWT(w, r, next, tokens)
+		})
+	}
+}
+
+func serveAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, keys APIKeyVerifier, key string) {
+	subject, scope, err := keys.VerifyAPIKey(r.Context(), key)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		httpError(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		httpError(w, r, errors.New("failed to verify API key"), http.StatusInternalServerError)
+		return
+	}
+
+	ctx := subjectToContext(r.Context(), subject)
+	ctx = scopeToContext(ctx, scope)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func serveJWT(w http.ResponseWriter, r *http.Request, next http.Handler, tokens *Verifier) {
+	var claims accessTokenClaims
+	token, err := request.ParseFromRequest(
+		r,
+		request.MultiExtractor{
+			request.AuthorizationHeaderExtractor,
+			request.ArgumentExtractor{"jwtToken"},
+		},
+		tokens.keyFunc(r.Context()),
+		request.WithClaims(&claims),
+		request.WithParser(tokens.parser),
+	)
+	if err != nil {
+		httpError(w, r, err, http.StatusUnauthorized)
+		return
+	}
+
+	if !token.Valid {
+		httpError(w, r, errors.New("invalid JWT token"), http.StatusBadRequest)
+		return
+	}
+
+	subject := claims.subject()
+	if subject == "" {
+		httpError(w, r, errors.New("JWT token has no subject"), http.StatusUnauthorized)
+		return
+	}
+
+	r = r.WithContext(subjectToContext(r.Context(), subject))
+
+	next.ServeHTTP(w, r)
 }
 
 type authorizationError struct {
@@ -0,0 +1,178 @@
+package jwtauth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/jwtauth"
+)
+
+var testSecret = []byte("s3cr3t")
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func rsaJWK(kid string, k *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, k *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(k.X.FillBytes(make([]byte, 32))), "y": b64(k.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+// serve выполняет запрос с токеном и возвращает код ответа и субъект, который увидел обработчик.
+func serve(t *testing.T, v *jwtauth.Verifier, token string) (int, string) {
+	t.Helper()
+	var subject string
+	h := jwtauth.NewHTTPMiddleware(v, nil)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		subject, _ = jwtauth.SubjectFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/bots", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code, subject
+}
+
+func TestHTTPMiddleware_HS256(t *testing.T) {
+	v, err := jwtauth.NewVerifier(jwtauth.Config{
+		Secret:     testSecret,
+		Issuer:     "sso",
+		Audience:   "itsreg",
+		Algorithms: []string{jwtauth.AlgHS256},
+	})
+	require.NoError(t, err)
+
+	claims := jwt.RegisteredClaims{Subject: "user", Issuer: "sso", Audience: jwt.ClaimStrings{"itsreg"}}
+	code, subject := serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, claims))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "user", subject)
+
+	foreign := claims
+	foreign.Issuer = "other"
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, foreign))
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	foreign = claims
+	foreign.Audience = jwt.ClaimStrings{"other"}
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, foreign))
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodHS512, "", testSecret, claims))
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, claims))
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestHTTPMiddleware_Subject(t *testing.T) {
+	v, err := jwtauth.NewVerifier(jwtauth.Config{Secret: testSecret, Algorithms: []string{jwtauth.AlgHS256}})
+	require.NoError(t, err)
+
+	// Числовой sub остаётся субъектом как есть, даже если в токене есть user_uuid
+	code, subject := serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, jwt.MapClaims{
+		"sub": "42", "user_uuid": "user",
+	}))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "42", subject)
+
+	// user_uuid используется, только если sub нет
+	code, subject = serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, jwt.MapClaims{"user_uuid": "user"}))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "user", subject)
+
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, jwt.MapClaims{}))
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestHTTPMiddleware_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey))
+
+	v, err := jwtauth.NewVerifier(jwtauth.Config{
+		JWKS:       jwtauth.NewJWKS(path, time.Nanosecond),
+		Algorithms: []string{jwtauth.AlgRS256, jwtauth.AlgES256},
+	})
+	require.NoError(t, err)
+
+	claims := jwt.RegisteredClaims{Subject: "user"}
+	code, subject := serve(t, v, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "user", subject)
+
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodES256, "ec-1", ecKey, claims))
+	require.Equal(t, http.StatusOK, code)
+
+	// Ключ с другим kid не подходит, даже если подпись им верна
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodES256, "rsa-1", ecKey, claims))
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	// Симметричные токены не принимаются, если HS256 не разрешён
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodHS256, "", testSecret, claims))
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	// После ротации принимаются токены нового ключа, а старого - нет
+	rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writeJWKS(t, path, rsaJWK("rsa-2", rotated))
+
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodRS256, "rsa-2", rotated, claims))
+	require.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, v, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims))
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := jwtauth.NewVerifier(jwtauth.Config{})
+	require.Error(t, err)
+
+	_, err = jwtauth.NewVerifier(jwtauth.Config{Secret: testSecret, Algorithms: []string{jwtauth.AlgRS256}})
+	require.Error(t, err)
+
+	_, err = jwtauth.NewVerifier(jwtauth.Config{Secret: testSecret, Algorithms: []string{"none"}})
+	require.Error(t, err)
+}
@@ -1,7 +0,0 @@
-package jwtauth
-
-import "os"
-
-var (
-	secret = os.Getenv("JWT_SECRET")
-)
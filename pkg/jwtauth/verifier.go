@@ -0,0 +1,46 @@
+package jwtauth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Verifier проверяет подпись, алгоритм, издателя и аудиторию JWT.
+type Verifier struct {
+	cfg    Config
+	parser *jwt.Parser
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(cfg.Algorithms)}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	return &Verifier{cfg: cfg, parser: jwt.NewParser(opts...)}, nil
+}
+
+// keyFunc возвращает ключ проверки подписи токена. Алгоритм уже проверен парсером по списку
+// разрешённых, поэтому токен HS256 никогда не проверяется открытым ключом как секретом.
+func (v *Verifier) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		alg := t.Method.Alg()
+		switch alg {
+		case AlgHS256:
+			return v.cfg.Secret, nil
+		case AlgRS256, AlgES256:
+			kid, _ := t.Header["kid"].(string)
+			return v.cfg.JWKS.Key(ctx, kid, alg)
+		default:
+			return nil, fmt.Errorf("unsupported JWT algorithm %q", alg)
+		}
+	}
+}
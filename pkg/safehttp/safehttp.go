@@ -0,0 +1,84 @@
+// Package safehttp предоставляет HTTP-клиент для запросов по адресам, которые задают пользователи сервиса
+// (Webhook, узлы-запросы). Клиент не соединяется с адресами обратной петли, частных и локальных сетей
+// и служебными адресами, в том числе с сервисом метаданных облака 169.254.169.254, поэтому через него
+// нельзя обратиться к внутренним сервисам (SSRF).
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// dialTimeout ограничивает время установки соединения.
+const dialTimeout = 5 * time.Second
+
+var ErrForbiddenAddress = errors.New("forbidden destination address")
+
+// forbiddenPrefixes дополняют проверки netip.Addr диапазонами, не предназначенными для публичных сервисов.
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 позволил бы обратиться к любому IPv4-адресу
+}
+
+// NewClient возвращает HTTP-клиент с таймаутом timeout, проверяющий адрес каждого соединения, в том
+// числе после перенаправлений и разрешения имён. Прокси из окружения не используется: соединение
+// с прокси обошло бы проверку адреса назначения.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// Allowed сообщает, можно ли соединяться с адресом ip.
+func Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// control вызывается после разрешения имени перед каждым соединением, поэтому проверяет фактический адрес.
+func control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !Allowed(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
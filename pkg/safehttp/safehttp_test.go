@@ -0,0 +1,35 @@
+package safehttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bmstu-itstech/itsreg-bots/pkg/safehttp"
+)
+
+func TestAllowed(t *testing.T) {
+	for _, addr := range []string{
+		"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0",
+		"100.64.0.1", "fd00::1", "fe80::1", "::ffff:127.0.0.1", "::ffff:169.254.169.254", "224.0.0.1",
+	} {
+		require.False(t, safehttp.Allowed(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"1.1.1.1", "93.184.216.34", "2606:4700:4700::1111"} {
+		require.True(t, safehttp.Allowed(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestNewClient_Loopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := safehttp.NewClient(time.Second).Get(srv.URL)
+	require.ErrorIs(t, err, safehttp.ErrForbiddenAddress)
+}
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,30 +20,68 @@ import (
 
 const corsMaxAge = 300
 
-func RunHTTPServer(createHandler func(router chi.Router) http.Handler) {
-	RunHTTPServerOnAddr(":"+os.Getenv("PORT"), createHandler)
+// readHeaderTimeout ограничивает время чтения заголовков запроса.
+const readHeaderTimeout = 10 * time.Second
+
+// RunHTTPServer обслуживает запросы до отмены ctx, после чего перестаёт принимать новые соединения и
+// дожидается завершения текущих запросов не дольше shutdownTimeout. Запросы к API проходят через
+// middleware аутентификации auth.
+func RunHTTPServer(
+	ctx context.Context,
+	shutdownTimeout time.Duration,
+	auth func(http.Handler) http.Handler,
+	createHandler func(router chi.Router) http.Handler,
+) {
+	RunHTTPServerOnAddr(ctx, ":"+os.Getenv("PORT"), shutdownTimeout, auth, createHandler)
 }
 
-func RunHTTPServerOnAddr(addr string, createHandler func(router chi.Router) http.Handler) {
+func RunHTTPServerOnAddr(
+	ctx context.Context,
+	addr string,
+	shutdownTimeout time.Duration,
+	auth func(http.Handler) http.Handler,
+	createHandler func(router chi.Router) http.Handler,
+) {
 	log := logs.DefaultLogger()
 
 	apiRouter := chi.NewRouter()
-	setMiddlewares(apiRouter, log)
+	setMiddlewares(apiRouter, log, auth)
 
 	rootRouter := chi.NewRouter()
 	rootRouter.Mount("/api/v2", createHandler(apiRouter))
 
-	log.Info("starting: HTTP server", "addr", addr)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           rootRouter,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("starting: HTTP server", "addr", addr)
+		errCh <- srv.ListenAndServe()
+	}()
 
-	//nolint:gosec // Не имеется возможности переехать на другой фреймворк
-	err := http.ListenAndServe(addr, rootRouter)
-	if err != nil {
+	select {
+	case err := <-errCh:
 		log.Error("Unable to start HTTP server")
 		panic(err)
+	case <-ctx.Done():
+	}
+
+	log.Info("stopping: HTTP server", "addr", addr)
+	// Контекст запросов не отменяется: начатые обработчики, в том числе рассылки, завершаются до дедлайна
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to gracefully stop HTTP server", slog.String("error", err.Error()))
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("HTTP server stopped with error", slog.String("error", err.Error()))
 	}
 }
 
-func setMiddlewares(router *chi.Mux, log *slog.Logger) {
+func setMiddlewares(router *chi.Mux, log *slog.Logger, auth func(http.Handler) http.Handler) {
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
 	router.Use(sl.NewLoggerMiddleware(log))
@@ -53,7 +94,7 @@ func setMiddlewares(router *chi.Mux, log *slog.Logger) {
 		middleware.SetHeader("X-Frame-Options", "deny"),
 	)
 	router.Use(middleware.NoCache)
-	router.Use(jwtauth.HTTPMiddleware)
+	router.Use(auth)
 }
 
 func addCorsMiddleware(router *chi.Mux) {
@@ -65,7 +106,7 @@ func addCorsMiddleware(router *chi.Mux) {
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", jwtauth.APIKeyHeader},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           corsMaxAge,